@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"image/png"
+	"os"
+
+	"parser-service/internal/config"
+	"parser-service/internal/heatmap"
+	"parser-service/internal/parser"
+	"parser-service/internal/radar"
+	"parser-service/internal/types"
+	"parser-service/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `Usage: heatmap [flags] <demo file>
+
+Parses a demo and renders one heatmap layer as a PNG over the map's radar image.
+
+Layers:
+  deaths     Where players died
+  kills      Where players stood when they got a kill
+  density    Where players spent their time
+  grenades   Where grenades landed
+`
+
+func main() {
+	layer := flag.String("layer", heatmap.LayerDensity, "layer to render")
+	playerID := flag.String("player", "", "only include this player's steam ID")
+	round := flag.Int("round", 0, "only include this round")
+	level := flag.Int("level", 0, "map level to render, 0 being the default level")
+	size := flag.Int("size", heatmap.DefaultSize, "width and height of the image in pixels")
+	output := flag.String("out", "heatmap.png", "PNG file to write")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 || !heatmap.ValidLayer(*layer) || *size < heatmap.MinSize || *size > heatmap.MaxSize {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Density is read from the round replays, with a frame for every stored tick
+	if *layer == heatmap.LayerDensity {
+		cfg.Parser.RoundReplays = true
+		cfg.Parser.ReplayInterval = cfg.Parser.TickSampleRate
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	perfLogger, err := utils.NewPerformanceLogger(cfg, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to initialize performance logger")
+	}
+	defer perfLogger.Close()
+
+	demoParser, err := parser.NewDemoParser(cfg, logger, perfLogger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize demo parser")
+	}
+
+	parsedData, err := demoParser.ParseDemoFromFile(context.Background(), flag.Arg(0), func(types.ProgressUpdate) {})
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to parse demo")
+	}
+
+	registry, err := radar.LoadRegistry(cfg.Parser.RadarDir)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load radar overviews")
+	}
+	overview, exists := registry.Overview(parsedData.Match.Map)
+	if !exists {
+		logger.WithField("map", parsedData.Match.Map).Fatal("No radar overview for map")
+	}
+
+	positions, err := heatmap.Positions(parsedData, heatmap.Filter{
+		Layer:       *layer,
+		PlayerID:    *playerID,
+		RoundNumber: *round,
+	})
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to collect heatmap positions")
+	}
+
+	options := heatmap.DefaultOptions()
+	options.Size = *size
+	options.Level = *level
+	options.Background, err = heatmap.LoadRadarImage(cfg.Parser.RadarDir, overview, *level)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to load radar image, drawing without it")
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to create output file")
+	}
+	if err := png.Encode(file, heatmap.Render(overview, positions, options)); err != nil {
+		file.Close()
+		logger.WithError(err).Fatal("Failed to write heatmap")
+	}
+	if err := file.Close(); err != nil {
+		logger.WithError(err).Fatal("Failed to write heatmap")
+	}
+
+	fmt.Printf("Wrote %s: %d positions on %s\n", *output, len(positions), overview.Map)
+}
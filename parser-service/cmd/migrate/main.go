@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strconv"
 
 	"parser-service/internal/config"
 	"parser-service/internal/database"
@@ -11,7 +13,45 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const usage = `Usage: migrate [-dir migrations] <command> [args]
+
+Commands:
+  up            Run GORM AutoMigrate and apply all pending SQL migrations (default)
+  down [N]      Roll back the last N applied migrations (default 1)
+  status        Show applied and pending migrations
+  create NAME   Create empty up/down scripts for a new migration
+`
+
 func main() {
+	migrationsDir := flag.String("dir", "migrations", "directory containing versioned SQL migrations")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	command := "up"
+	args := flag.Args()
+	if len(args) > 0 {
+		command = args[0]
+		args = args[1:]
+	}
+
+	// create only touches the filesystem, so it does not need a database
+	if command == "create" {
+		if len(args) != 1 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		upPath, downPath, err := database.CreateMigration(*migrationsDir, args[0])
+		if err != nil {
+			fmt.Printf("Failed to create migration: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Created %s\nCreated %s\n", upPath, downPath)
+		return
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Printf("Failed to load configuration: %v\n", err)
@@ -28,39 +68,71 @@ func main() {
 	}
 	defer db.Close()
 
-	// Run GORM AutoMigrate
-	fmt.Println("Running GORM AutoMigrate...")
-	if err := db.AutoMigrate(); err != nil {
-		logger.WithError(err).Fatal("Failed to run AutoMigrate")
-	}
-	fmt.Println("✓ GORM AutoMigrate completed successfully")
+	ctx := context.Background()
+	migrator := database.NewMigrator(db.DB, logger, *migrationsDir)
 
-	// Run SQL migrations
-	migrationsDir := "migrations"
-	files, err := os.ReadDir(migrationsDir)
-	if err != nil {
-		logger.WithError(err).Fatal("Failed to read migrations directory")
-	}
+	switch command {
+	case "up":
+		// Run GORM AutoMigrate first so SQL migrations can rely on the tables
+		fmt.Println("Running GORM AutoMigrate...")
+		if err := db.AutoMigrate(); err != nil {
+			logger.WithError(err).Fatal("Failed to run AutoMigrate")
+		}
+		fmt.Println("✓ GORM AutoMigrate completed successfully")
 
-	for _, file := range files {
-		if filepath.Ext(file.Name()) == ".sql" {
-			fmt.Printf("Running SQL migration: %s...\n", file.Name())
-			sqlPath := filepath.Join(migrationsDir, file.Name())
-			sqlContent, err := os.ReadFile(sqlPath)
-			if err != nil {
-				logger.WithError(err).Errorf("Failed to read migration file: %s", file.Name())
-				continue
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("✓ Applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			logger.WithError(err).Fatal("Migration failed")
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			steps, err = strconv.Atoi(args[0])
+			if err != nil || steps < 1 {
+				fmt.Printf("Invalid number of migrations to roll back: %q\n", args[0])
+				os.Exit(2)
 			}
+		}
 
-			// Execute SQL migration
-			if err := db.DB.Exec(string(sqlContent)).Error; err != nil {
-				logger.WithError(err).Errorf("Failed to execute migration: %s", file.Name())
-				// Continue with other migrations even if one fails
-				continue
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, migration := range rolledBack {
+			fmt.Printf("✓ Rolled back %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			logger.WithError(err).Fatal("Rollback failed")
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("No applied migrations to roll back")
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to read migration status")
+		}
+
+		for _, status := range statuses {
+			state := "pending"
+			switch {
+			case status.Missing:
+				state = "applied (file missing)"
+			case status.Modified:
+				state = "applied (modified since)"
+			case status.Applied:
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
 			}
-			fmt.Printf("✓ Migration %s completed successfully\n", file.Name())
+			fmt.Printf("%04d_%-40s %s\n", status.Version, status.Name, state)
 		}
-	}
 
-	fmt.Println("\nAll migrations completed!")
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
 }
@@ -7,7 +7,12 @@ const (
 	ReadinessEndpoint = "/ready"
 
 	// API endpoints
-	ParseDemoEndpoint = "parse-demo"
+	ParseDemoEndpoint    = "parse-demo"
+	ParseMatchEndpoint   = "parse-match"
+	ParseSeriesEndpoint  = "parse-series"
+	MatchShotsEndpoint   = "matches/:match_id/shots"
+	MatchHeatmapEndpoint = "matches/:match_id/heatmap"
+	HeatmapEndpoint      = "heatmap"
 
 	// Event data endpoints - new format
 	JobEventEndpoint = "/api/job/%s/event/%s"
@@ -25,4 +30,13 @@ const (
 	EventTypeAim          = "aim"
 	EventTypeAimWeapon    = "aim-weapon"
 	EventTypeAchievements = "achievements"
+	EventTypePause        = "pause"
+	EventTypeSubstitution = "substitution"
+	EventTypeSeries       = "series"
+	EventTypeBomb         = "bomb"
+	EventTypeTeamEconomy  = "team-economy"
+	EventTypeItem         = "item"
+	EventTypeLoadout      = "loadout"
+	EventTypeTrade        = "trade"
+	EventTypeRoundReplay  = "round-replay"
 )
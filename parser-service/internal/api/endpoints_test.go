@@ -19,6 +19,10 @@ func TestEndpoints_Constants(t *testing.T) {
 		t.Errorf("Expected ParseDemoEndpoint to be 'parse-demo', got %s", ParseDemoEndpoint)
 	}
 
+	if MatchShotsEndpoint != "matches/:match_id/shots" {
+		t.Errorf("Expected MatchShotsEndpoint to be 'matches/:match_id/shots', got %s", MatchShotsEndpoint)
+	}
+
 	// Test job event endpoint format
 	expectedJobEventFormat := "/api/job/%s/event/%s"
 	if JobEventEndpoint != expectedJobEventFormat {
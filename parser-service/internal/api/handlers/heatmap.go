@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"net/http"
+	"strconv"
+
+	"parser-service/internal/config"
+	"parser-service/internal/database"
+	"parser-service/internal/heatmap"
+	"parser-service/internal/radar"
+	"parser-service/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type HeatmapHandler struct {
+	logger            *logrus.Logger
+	playerTickService *database.PlayerTickService
+	radarRegistry     *radar.Registry
+	radarDir          string
+}
+
+// HeatmapRequest is the body of a heatmap of posted positions
+type HeatmapRequest struct {
+	Map       string           `json:"map" binding:"required"`
+	Positions []types.Position `json:"positions"`
+	Level     int              `json:"level"`
+	Size      int              `json:"size"`
+}
+
+func NewHeatmapHandler(cfg *config.Config, logger *logrus.Logger, playerTickService *database.PlayerTickService) *HeatmapHandler {
+	registry, err := radar.LoadRegistry(cfg.Parser.RadarDir)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to load radar overviews, using the built in ones")
+		registry = radar.NewRegistry()
+	}
+
+	return &HeatmapHandler{
+		logger:            logger,
+		playerTickService: playerTickService,
+		radarRegistry:     registry,
+		radarDir:          cfg.Parser.RadarDir,
+	}
+}
+
+// GET /api/matches/:match_id/heatmap
+// What this does:
+// Renders a heatmap layer of a match as a PNG. Density is read from the stored tick
+// data, the kills, deaths and grenades layers from the positions stored after the parse
+// Required query: map
+// Optional query filters: layer, player_id, round, level, size
+
+func (h *HeatmapHandler) HandleGetMatchHeatmap(c *gin.Context) {
+	filter := database.HeatmapPointFilter{
+		MatchID:  c.Param("match_id"),
+		Layer:    c.DefaultQuery("layer", heatmap.LayerDensity),
+		PlayerID: c.Query("player_id"),
+	}
+
+	if filter.MatchID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "match_id is required",
+		})
+		return
+	}
+
+	if !heatmap.ValidLayer(filter.Layer) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "layer must be one of deaths, kills, density or grenades",
+		})
+		return
+	}
+
+	if round := c.Query("round"); round != "" {
+		roundNumber, err := strconv.Atoi(round)
+		if err != nil || roundNumber < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "round must be a positive integer",
+			})
+			return
+		}
+		filter.RoundNumber = roundNumber
+	}
+
+	options, overview, ok := h.renderOptions(c, c.Query("map"), c.Query("level"), c.Query("size"))
+	if !ok {
+		return
+	}
+
+	positions, err := h.layerPositions(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"match_id": filter.MatchID,
+			"layer":    filter.Layer,
+			"error":    err,
+		}).Error("Failed to query positions for heatmap")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to query heatmap positions",
+		})
+		return
+	}
+
+	h.writePNG(c, overview, positions, options)
+}
+
+// layerPositions returns the stored positions of a heatmap layer of a match
+func (h *HeatmapHandler) layerPositions(ctx context.Context, filter database.HeatmapPointFilter) ([]types.Position, error) {
+	if filter.Layer == heatmap.LayerDensity {
+		samples, err := h.playerTickService.GetPlayerTickData(ctx, database.TickDataFilter{
+			MatchID:     filter.MatchID,
+			PlayerID:    filter.PlayerID,
+			RoundNumber: filter.RoundNumber,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return heatmap.TickPositions(samples, filter.PlayerID), nil
+	}
+
+	points, err := h.playerTickService.GetHeatmapPoints(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return heatmap.PointPositions(points), nil
+}
+
+// POST /api/heatmap
+// What this does:
+// Renders posted positions, e.g. the kill, death or grenade positions of a match, as a PNG
+
+func (h *HeatmapHandler) HandlePostHeatmap(c *gin.Context) {
+	var req HeatmapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	options, overview, ok := h.renderOptions(c, req.Map, strconv.Itoa(req.Level), strconv.Itoa(req.Size))
+	if !ok {
+		return
+	}
+
+	h.writePNG(c, overview, req.Positions, options)
+}
+
+// renderOptions reads the map, level and size of a request. It writes the error
+// response and returns false when one of them is invalid.
+func (h *HeatmapHandler) renderOptions(c *gin.Context, mapName, level, size string) (heatmap.Options, radar.Overview, bool) {
+	options := heatmap.DefaultOptions()
+
+	overview, exists := h.radarRegistry.Overview(mapName)
+	if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "map is required and must have a radar overview",
+		})
+		return options, overview, false
+	}
+
+	if level != "" {
+		value, err := strconv.Atoi(level)
+		if err != nil || value < 0 || value > len(overview.Levels) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "level must be one of the map's levels",
+			})
+			return options, overview, false
+		}
+		options.Level = value
+	}
+
+	if size != "" && size != "0" {
+		value, err := strconv.Atoi(size)
+		if err != nil || value < heatmap.MinSize || value > heatmap.MaxSize {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "size must be between " + strconv.Itoa(heatmap.MinSize) + " and " + strconv.Itoa(heatmap.MaxSize),
+			})
+			return options, overview, false
+		}
+		options.Size = value
+	}
+
+	background, err := heatmap.LoadRadarImage(h.radarDir, overview, options.Level)
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to load radar image, drawing without it")
+	}
+	options.Background = background
+
+	return options, overview, true
+}
+
+func (h *HeatmapHandler) writePNG(c *gin.Context, overview radar.Overview, positions []types.Position, options heatmap.Options) {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, heatmap.Render(overview, positions, options)); err != nil {
+		h.logger.WithError(err).Error("Failed to encode heatmap")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to encode heatmap",
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", buffer.Bytes())
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"parser-service/internal/config"
+	"parser-service/internal/database"
+	"parser-service/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupHeatmapRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&types.PlayerTickData{}, &types.PlayerTickBlob{}, &types.HeatmapPoint{}))
+
+	logger := logrus.New()
+	service := database.NewPlayerTickService(db, logger)
+
+	samples := map[string][]*types.PlayerTickData{
+		"player-1": {
+			{MatchID: "match-1", PlayerID: "player-1", Tick: 100, Team: "A", PositionX: -1000, PositionY: 0},
+			{MatchID: "match-1", PlayerID: "player-1", Tick: 102, Team: "A", PositionX: -1000, PositionY: 0},
+		},
+	}
+	require.NoError(t, service.SavePlayerTickBlobs(context.Background(), "match-1", 1, samples))
+	require.NoError(t, service.SaveHeatmapPoints(context.Background(), []*types.HeatmapPoint{
+		{MatchID: "match-1", Layer: "deaths", RoundNumber: 1, PlayerID: "player-1", PositionX: -1000},
+		{MatchID: "match-1", Layer: "kills", RoundNumber: 2, PlayerID: "player-2", PositionX: -1000},
+	}))
+
+	handler := NewHeatmapHandler(&config.Config{}, logger, service)
+	router := gin.New()
+	router.GET("/api/matches/:match_id/heatmap", handler.HandleGetMatchHeatmap)
+	router.POST("/api/heatmap", handler.HandlePostHeatmap)
+	return router
+}
+
+func TestHeatmapHandler_HandleGetMatchHeatmap(t *testing.T) {
+	router := setupHeatmapRouter(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/matches/match-1/heatmap?map=de_mirage&player_id=player-1&size=256", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+
+	img, err := png.Decode(w.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 256, img.Bounds().Dx())
+}
+
+func TestHeatmapHandler_HandleGetMatchHeatmap_Layers(t *testing.T) {
+	router := setupHeatmapRouter(t)
+
+	for _, url := range []string{
+		"/api/matches/match-1/heatmap?map=de_mirage&layer=deaths&size=128",
+		"/api/matches/match-1/heatmap?map=de_mirage&layer=kills&player_id=player-2&round=2&size=128",
+		"/api/matches/match-1/heatmap?map=de_mirage&layer=grenades&size=128",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+
+		require.Equal(t, http.StatusOK, w.Code, url)
+		img, err := png.Decode(w.Body)
+		require.NoError(t, err, url)
+		assert.Equal(t, 128, img.Bounds().Dx(), url)
+	}
+}
+
+func TestHeatmapHandler_HandleGetMatchHeatmap_InvalidRequests(t *testing.T) {
+	router := setupHeatmapRouter(t)
+
+	for name, url := range map[string]string{
+		"unknown map":  "/api/matches/match-1/heatmap?map=de_unknown",
+		"missing map":  "/api/matches/match-1/heatmap",
+		"bad layer":    "/api/matches/match-1/heatmap?map=de_mirage&layer=footsteps",
+		"bad round":    "/api/matches/match-1/heatmap?map=de_mirage&round=0",
+		"bad level":    "/api/matches/match-1/heatmap?map=de_mirage&level=1",
+		"size too big": "/api/matches/match-1/heatmap?map=de_mirage&size=10000",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+	}
+}
+
+func TestHeatmapHandler_HandlePostHeatmap(t *testing.T) {
+	router := setupHeatmapRouter(t)
+
+	body, err := json.Marshal(HeatmapRequest{
+		Map:       "de_nuke",
+		Positions: []types.Position{{X: 0, Y: 0, Z: -600}},
+		Level:     1,
+		Size:      128,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/heatmap", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	img, err := png.Decode(w.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 128, img.Bounds().Dx())
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/heatmap", bytes.NewReader([]byte(`{"positions": []}`)))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -274,8 +274,10 @@ func (h *ParseDemoHandler) processDemo(ctx context.Context, job *types.Processin
 		h.perfLogger.FinalizeRun()
 		h.perfLogger.Close()
 
-		// Clean up temporary file if it exists
-		h.cleanupTempFile(job.TempFilePath)
+		// Clean up temporary files if they exist
+		for _, path := range jobDemoPaths(job) {
+			h.cleanupTempFile(path)
+		}
 
 		if r := recover(); r != nil {
 			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeUnknown, types.ErrorSeverityCritical, "Panic in demo processing", nil)
@@ -340,31 +342,7 @@ func (h *ParseDemoHandler) processDemo(ctx context.Context, job *types.Processin
 	}
 
 	parseTimer := h.perfLogger.StartTimer("parse_demo").WithMetadata("job_id", job.JobID)
-	parsedData, err := h.demoParser.ParseDemo(ctx, job.TempFilePath, func(update types.ProgressUpdate) {
-		// Update job with new progress data
-		job.Progress = update.Progress
-		job.CurrentStep = update.CurrentStep
-		job.StepProgress = update.StepProgress
-		job.TotalSteps = update.TotalSteps
-		job.CurrentStepNum = update.CurrentStepNum
-		job.Context = update.Context
-		job.LastUpdateTime = update.LastUpdateTime
-
-		// Update status based on progress
-		if update.Progress < 20 {
-			job.Status = types.StatusParsing
-		} else if update.Progress < 85 {
-			job.Status = types.StatusProcessingEvents
-		} else {
-			job.Status = types.StatusFinalizing
-		}
-
-		if err := h.sendProgressUpdate(ctx, job); err != nil {
-			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeProgressUpdate, types.ErrorSeverityInfo, "Failed to send progress update", err)
-			h.progressManager.ReportParseError(parseError)
-		}
-	})
-
+	parsedData, err := h.parseDemos(ctx, job, jobDemoPaths(job))
 	if err != nil {
 		parseTimer.StopWithError(err)
 		// Check if it's a ParseError with severity information
@@ -478,6 +456,80 @@ func (h *ParseDemoHandler) processDemo(ctx context.Context, job *types.Processin
 	jobTimer.WithMetadata("status", "completed").Stop()
 }
 
+// jobDemoPaths returns the demo files of a job in the order they were uploaded
+func jobDemoPaths(job *types.ProcessingJob) []string {
+	if len(job.TempFilePaths) > 0 {
+		return job.TempFilePaths
+	}
+	return []string{job.TempFilePath}
+}
+
+// parseDemos parses demo files that together hold one match and stitches them
+// into a single result. Progress of each file is reported as its share of the job.
+func (h *ParseDemoHandler) parseDemos(ctx context.Context, job *types.ProcessingJob, paths []string) (*types.ParsedDemoData, error) {
+	parts := make([]*types.ParsedDemoData, 0, len(paths))
+
+	for i, path := range paths {
+		part, err := h.demoParser.ParseDemo(ctx, path, func(update types.ProgressUpdate) {
+			// Update job with new progress data
+			job.Progress = (i*100 + update.Progress) / len(paths)
+			job.CurrentStep = update.CurrentStep
+			job.StepProgress = update.StepProgress
+			job.TotalSteps = update.TotalSteps
+			job.CurrentStepNum = update.CurrentStepNum
+			job.Context = update.Context
+			job.LastUpdateTime = update.LastUpdateTime
+
+			if len(paths) > 1 {
+				if job.Context == nil {
+					job.Context = make(map[string]interface{})
+				}
+				job.Context["demo_index"] = i
+				job.Context["demo_count"] = len(paths)
+			}
+
+			// Update status based on progress
+			if job.Progress < 20 {
+				job.Status = types.StatusParsing
+			} else if job.Progress < 85 {
+				job.Status = types.StatusProcessingEvents
+			} else {
+				job.Status = types.StatusFinalizing
+			}
+
+			if err := h.sendProgressUpdate(ctx, job); err != nil {
+				parseError := types.NewParseErrorWithSeverity(types.ErrorTypeProgressUpdate, types.ErrorSeverityInfo, "Failed to send progress update", err)
+				h.progressManager.ReportParseError(parseError)
+			}
+		})
+		if err != nil {
+			if len(paths) > 1 {
+				if parseErr, ok := err.(*types.ParseError); ok {
+					return nil, parseErr.WithContext("demo_index", i)
+				}
+			}
+			return nil, err
+		}
+
+		parts = append(parts, part)
+	}
+
+	stitched, err := parser.StitchParsedDemos(h.logger, parts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parts) > 1 {
+		if err := h.demoParser.MergeStitchedMatchData(ctx, stitched); err != nil {
+			h.logger.WithError(err).WithField("match_id", stitched.Match.MatchID).Warn("Failed to merge stored data of stitched demos")
+		}
+	}
+	if err := h.demoParser.SaveHeatmapPoints(ctx, stitched); err != nil {
+		h.logger.WithError(err).WithField("match_id", stitched.Match.MatchID).Warn("Failed to save heatmap points")
+	}
+	return stitched, nil
+}
+
 // Sends progress updates to the callback URLs
 // Creates a progress update struct with current job status
 // Marshals the struct to JSON
@@ -586,6 +638,18 @@ func (h *ParseDemoHandler) sendProgressUpdateWithMatchData(ctx context.Context,
 		payload["error_code"] = *update.ErrorCode
 	}
 
+	// Demos from crashed servers only contain the rounds completed before the crash
+	if parsedData.Partial {
+		payload["partial"] = true
+		payload["last_complete_round"] = parsedData.LastCompleteRound
+		payload["diagnostics"] = parsedData.Diagnostics
+	}
+
+	// Matches stitched from several demos list which rounds each demo holds
+	if len(parsedData.Segments) > 0 {
+		payload["segments"] = parsedData.Segments
+	}
+
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal progress update with match data: %w", err)
@@ -681,6 +745,78 @@ func (h *ParseDemoHandler) sendAllEvents(ctx context.Context, job *types.Process
 	}
 	timer.Stop()
 
+	timer = h.perfLogger.StartTimer("send_bomb_events").
+		WithMetadata("job_id", job.JobID).
+		WithMetadata("event_count", len(parsedData.BombEvents))
+	if err := h.batchSender.SendBombEvents(ctx, job.JobID, job.CompletionCallbackURL, parsedData.BombEvents); err != nil {
+		timer.StopWithError(err)
+		return types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send bomb events", err)
+	}
+	timer.Stop()
+
+	timer = h.perfLogger.StartTimer("send_team_economy_events").
+		WithMetadata("job_id", job.JobID).
+		WithMetadata("event_count", len(parsedData.TeamEconomyEvents))
+	if err := h.batchSender.SendTeamEconomyEvents(ctx, job.JobID, job.CompletionCallbackURL, parsedData.TeamEconomyEvents); err != nil {
+		timer.StopWithError(err)
+		return types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send team economy events", err)
+	}
+	timer.Stop()
+
+	timer = h.perfLogger.StartTimer("send_item_events").
+		WithMetadata("job_id", job.JobID).
+		WithMetadata("event_count", len(parsedData.ItemEvents))
+	if err := h.batchSender.SendItemEvents(ctx, job.JobID, job.CompletionCallbackURL, parsedData.ItemEvents); err != nil {
+		timer.StopWithError(err)
+		return types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send item events", err)
+	}
+	timer.Stop()
+
+	timer = h.perfLogger.StartTimer("send_loadout_events").
+		WithMetadata("job_id", job.JobID).
+		WithMetadata("event_count", len(parsedData.LoadoutEvents))
+	if err := h.batchSender.SendLoadoutEvents(ctx, job.JobID, job.CompletionCallbackURL, parsedData.LoadoutEvents); err != nil {
+		timer.StopWithError(err)
+		return types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send loadout events", err)
+	}
+	timer.Stop()
+
+	timer = h.perfLogger.StartTimer("send_trade_events").
+		WithMetadata("job_id", job.JobID).
+		WithMetadata("event_count", len(parsedData.TradeEvents))
+	if err := h.batchSender.SendTradeEvents(ctx, job.JobID, job.CompletionCallbackURL, parsedData.TradeEvents); err != nil {
+		timer.StopWithError(err)
+		return types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send trade events", err)
+	}
+	timer.Stop()
+
+	timer = h.perfLogger.StartTimer("send_round_replays").
+		WithMetadata("job_id", job.JobID).
+		WithMetadata("event_count", len(parsedData.RoundReplays))
+	if err := h.batchSender.SendRoundReplays(ctx, job.JobID, job.CompletionCallbackURL, parsedData.RoundReplays); err != nil {
+		timer.StopWithError(err)
+		return types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send round replays", err)
+	}
+	timer.Stop()
+
+	timer = h.perfLogger.StartTimer("send_pause_events").
+		WithMetadata("job_id", job.JobID).
+		WithMetadata("event_count", len(parsedData.PauseEvents))
+	if err := h.batchSender.SendPauseEvents(ctx, job.JobID, job.CompletionCallbackURL, parsedData.PauseEvents); err != nil {
+		timer.StopWithError(err)
+		return types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send pause events", err)
+	}
+	timer.Stop()
+
+	timer = h.perfLogger.StartTimer("send_substitutions").
+		WithMetadata("job_id", job.JobID).
+		WithMetadata("event_count", len(parsedData.Substitutions))
+	if err := h.batchSender.SendSubstitutions(ctx, job.JobID, job.CompletionCallbackURL, parsedData.Substitutions); err != nil {
+		timer.StopWithError(err)
+		return types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send substitutions", err)
+	}
+	timer.Stop()
+
 	// Send aim tracking events
 	timer = h.perfLogger.StartTimer("send_aim_events").
 		WithMetadata("job_id", job.JobID).
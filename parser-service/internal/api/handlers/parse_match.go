@@ -0,0 +1,416 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"parser-service/internal/parser"
+	"parser-service/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// POST /api/parse-match
+// Receives several demo files that each hold part of one match, in playing order,
+// and parses them as a single match. Processing continues as for a single demo.
+func (h *ParseDemoHandler) HandleParseMatch(c *gin.Context) {
+	requestTimer := h.perfLogger.StartTimer("http_request_parse_match")
+	defer requestTimer.Stop()
+
+	var req types.ParseMatchRequest
+	if err := c.ShouldBind(&req); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeValidation, types.ErrorSeverityError, "Failed to bind file upload request", err)
+		h.progressManager.ReportParseError(parseError)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid file upload request format",
+		})
+		return
+	}
+
+	job, ok := h.queueMultiDemoJob(c, req.JobID, req.ProgressCallbackURL, req.CompletionCallbackURL, req.DemoFiles)
+	if !ok {
+		return
+	}
+
+	// Start background processing
+	go h.processDemo(context.Background(), job)
+
+	c.JSON(http.StatusAccepted, types.ParseDemoResponse{
+		Success: true,
+		JobID:   job.JobID,
+		Message: "Match parsing started",
+	})
+}
+
+// POST /api/parse-series
+// Receives the demo files of a best-of series in playing order. Each map is sent
+// under its own job ID, "<job_id>-map-<n>", followed by the series summary and the
+// completion of the series job.
+func (h *ParseDemoHandler) HandleParseSeries(c *gin.Context) {
+	requestTimer := h.perfLogger.StartTimer("http_request_parse_series")
+	defer requestTimer.Stop()
+
+	var req types.ParseSeriesRequest
+	if err := c.ShouldBind(&req); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeValidation, types.ErrorSeverityError, "Failed to bind file upload request", err)
+		h.progressManager.ReportParseError(parseError)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid file upload request format",
+		})
+		return
+	}
+
+	if err := validateMapNumbers(req.MapNumbers, len(req.DemoFiles)); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeValidation, types.ErrorSeverityError, "Map number validation failed", err)
+		h.progressManager.ReportParseError(parseError)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	job, ok := h.queueMultiDemoJob(c, req.JobID, req.ProgressCallbackURL, req.CompletionCallbackURL, req.DemoFiles)
+	if !ok {
+		return
+	}
+
+	// Start background processing
+	go h.processSeries(context.Background(), job, groupSeriesDemos(job.TempFilePaths, req.MapNumbers))
+
+	c.JSON(http.StatusAccepted, types.ParseDemoResponse{
+		Success: true,
+		JobID:   job.JobID,
+		Message: "Series parsing started",
+	})
+}
+
+// queueMultiDemoJob validates and saves the uploaded demo files and registers the
+// job. On failure the response has been written and false is returned.
+func (h *ParseDemoHandler) queueMultiDemoJob(c *gin.Context, jobID string, progressCallbackURL string, completionCallbackURL string, files []*multipart.FileHeader) (*types.ProcessingJob, bool) {
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "at least one demo file is required",
+		})
+		return nil, false
+	}
+
+	// Validate files
+	for _, file := range files {
+		if err := h.validateUploadedFile(file); err != nil {
+			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeValidation, types.ErrorSeverityError, "File validation failed", err)
+			h.progressManager.ReportParseError(parseError)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return nil, false
+		}
+	}
+
+	if jobID == "" {
+		jobID = uuid.New().String()
+	}
+
+	if _, exists := h.jobs[jobID]; exists {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeValidation, types.ErrorSeverityError, "Job already exists", nil)
+		parseError = parseError.WithContext("job_id", jobID)
+		h.progressManager.ReportParseError(parseError)
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Job already exists",
+			"job_id":  jobID,
+		})
+		return nil, false
+	}
+
+	// Save uploaded files to temporary locations
+	saveTimer := h.perfLogger.StartTimer("save_uploaded_files").
+		WithMetadata("job_id", jobID).
+		WithMetadata("file_count", len(files))
+	tempFilePaths := make([]string, 0, len(files))
+	for _, file := range files {
+		tempFilePath, err := h.saveUploadedFile(file)
+		if err != nil {
+			saveTimer.StopWithError(err)
+			for _, path := range tempFilePaths {
+				h.cleanupTempFile(path)
+			}
+
+			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeResourceExhausted, types.ErrorSeverityCritical, "Failed to save uploaded file", err)
+			h.progressManager.ReportParseError(parseError)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Failed to save uploaded file",
+			})
+			return nil, false
+		}
+		tempFilePaths = append(tempFilePaths, tempFilePath)
+	}
+	saveTimer.Stop()
+
+	job := &types.ProcessingJob{
+		JobID:                 jobID,
+		TempFilePath:          tempFilePaths[0],
+		TempFilePaths:         tempFilePaths,
+		ProgressCallbackURL:   progressCallbackURL,
+		CompletionCallbackURL: completionCallbackURL,
+		Status:                types.StatusQueued,
+		Progress:              0,
+		CurrentStep:           "Job queued",
+		StartTime:             time.Now(),
+	}
+
+	h.jobs[jobID] = job
+
+	return job, true
+}
+
+// validateMapNumbers checks that every demo file has a map number and that map
+// numbers start at 1 and never decrease, since files are uploaded in playing order
+func validateMapNumbers(mapNumbers []int, fileCount int) error {
+	if len(mapNumbers) == 0 {
+		return nil
+	}
+
+	if len(mapNumbers) != fileCount {
+		return types.NewParseErrorWithSeverity(types.ErrorTypeValidation, types.ErrorSeverityError, fmt.Sprintf("expected %d map numbers, one per demo file, got %d", fileCount, len(mapNumbers)), nil)
+	}
+
+	previous := 0
+	for i, mapNumber := range mapNumbers {
+		if (i == 0 && mapNumber != 1) || mapNumber < previous || mapNumber > previous+1 {
+			return types.NewParseErrorWithSeverity(types.ErrorTypeValidation, types.ErrorSeverityError, "map numbers must start at 1 and follow the order of the demo files", nil)
+		}
+		previous = mapNumber
+	}
+
+	return nil
+}
+
+// groupSeriesDemos splits the demo files of a series into maps. Without map
+// numbers every file is a map of its own.
+func groupSeriesDemos(paths []string, mapNumbers []int) [][]string {
+	groups := make([][]string, 0, len(paths))
+	for i, path := range paths {
+		if len(mapNumbers) == 0 || i == 0 || mapNumbers[i] != mapNumbers[i-1] {
+			groups = append(groups, []string{path})
+			continue
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], path)
+	}
+	return groups
+}
+
+// seriesMapJobID returns the job ID a map of a series is sent under
+func seriesMapJobID(jobID string, mapNumber int) string {
+	return fmt.Sprintf("%s-map-%d", jobID, mapNumber)
+}
+
+// processSeries parses every map of a series, sends each map's data under its own
+// job ID and finishes with the series summary
+func (h *ParseDemoHandler) processSeries(ctx context.Context, job *types.ProcessingJob, mapPaths [][]string) {
+	// Get file info for performance logging
+	var fileSize int64
+	for _, path := range job.TempFilePaths {
+		if fileInfo, err := os.Stat(path); err == nil {
+			fileSize += fileInfo.Size()
+		}
+	}
+
+	// Initialize performance logger for this run
+	if err := h.perfLogger.InitializeRun(job.JobID, filepath.Base(job.TempFilePath), fileSize); err != nil {
+		h.logger.WithError(err).Warn("Failed to initialize performance logger for run")
+	}
+
+	jobTimer := h.perfLogger.StartTimer("process_series_job").
+		WithMetadata("job_id", job.JobID).
+		WithMetadata("maps", len(mapPaths))
+
+	defer func() {
+		// Finalize performance logging
+		h.perfLogger.FinalizeRun()
+		h.perfLogger.Close()
+
+		// Clean up temporary files if they exist
+		for _, path := range jobDemoPaths(job) {
+			h.cleanupTempFile(path)
+		}
+
+		if r := recover(); r != nil {
+			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeUnknown, types.ErrorSeverityCritical, "Panic in series processing", nil)
+			parseError = parseError.WithContext("job_id", job.JobID)
+			parseError = parseError.WithContext("panic", r)
+			h.progressManager.ReportParseError(parseError)
+
+			job.Status = types.StatusFailed
+			job.ErrorMessage = "Internal processing error"
+
+			if err := h.batchSender.SendError(ctx, job.JobID, job.CompletionCallbackURL, job.ErrorMessage); err != nil {
+				parseError = types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityCritical, "Failed to send error to Laravel", err)
+				h.progressManager.ReportParseError(parseError)
+			}
+		}
+	}()
+
+	job.Status = types.StatusParsing
+	job.CurrentStep = "Parsing series"
+	job.Context = map[string]interface{}{"step": "parsing_series", "map_count": len(mapPaths)}
+	if err := h.sendProgressUpdate(ctx, job); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeProgressUpdate, types.ErrorSeverityInfo, "Failed to send parsing progress update", err)
+		h.progressManager.ReportParseError(parseError)
+	}
+
+	maps := make([]*types.ParsedDemoData, 0, len(mapPaths))
+	for i, paths := range mapPaths {
+		parseTimer := h.perfLogger.StartTimer("parse_series_map").
+			WithMetadata("job_id", job.JobID).
+			WithMetadata("map_number", i+1)
+
+		mapJob := &types.ProcessingJob{
+			JobID:                 seriesMapJobID(job.JobID, i+1),
+			ProgressCallbackURL:   job.ProgressCallbackURL,
+			CompletionCallbackURL: job.CompletionCallbackURL,
+			Context:               make(map[string]interface{}),
+		}
+		data, err := h.parseDemos(ctx, mapJob, paths)
+		if err != nil {
+			parseTimer.StopWithError(err)
+			if parseErr, ok := err.(*types.ParseError); ok {
+				h.progressManager.ReportParseError(parseErr.WithContext("map_number", i+1))
+			} else {
+				parseError := types.NewParseErrorWithSeverity(types.ErrorTypeParsing, types.ErrorSeverityCritical, "Demo parsing failed", err)
+				parseError = parseError.WithContext("job_id", job.JobID)
+				parseError = parseError.WithContext("map_number", i+1)
+				h.progressManager.ReportParseError(parseError)
+			}
+
+			job.Status = types.StatusParseFailed
+			job.ErrorMessage = fmt.Sprintf("map %d: %s", i+1, err.Error())
+
+			if err := h.batchSender.SendError(ctx, job.JobID, job.CompletionCallbackURL, job.ErrorMessage); err != nil {
+				parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityCritical, "Failed to send error to Laravel", err)
+				h.progressManager.ReportParseError(parseError)
+			}
+			return
+		}
+		parseTimer.Stop()
+
+		maps = append(maps, data)
+
+		job.Progress = 85 * (i + 1) / len(mapPaths)
+		job.CurrentStep = fmt.Sprintf("Parsed map %d of %d", i+1, len(mapPaths))
+		job.LastUpdateTime = time.Now()
+		if err := h.sendProgressUpdate(ctx, job); err != nil {
+			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeProgressUpdate, types.ErrorSeverityInfo, "Failed to send progress update", err)
+			h.progressManager.ReportParseError(parseError)
+		}
+	}
+
+	// Aligns team letters across maps, so it must run before any map is sent
+	series := parser.AggregateSeries(maps)
+
+	job.Status = types.StatusSendingEvents
+	job.CurrentStep = "Sending map data"
+	job.Progress = 90
+	job.LastUpdateTime = time.Now()
+	job.Context["step"] = "sending_events"
+	if err := h.sendProgressUpdate(ctx, job); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeProgressUpdate, types.ErrorSeverityInfo, "Failed to send progress update", err)
+		h.progressManager.ReportParseError(parseError)
+	}
+
+	mapJobIDs := make([]string, len(series.Maps))
+	for i, data := range series.Maps {
+		mapJob := &types.ProcessingJob{
+			JobID:                 seriesMapJobID(job.JobID, i+1),
+			ProgressCallbackURL:   job.ProgressCallbackURL,
+			CompletionCallbackURL: job.CompletionCallbackURL,
+			Status:                types.StatusSendingMetadata,
+			Progress:              90,
+			CurrentStep:           "Sending match metadata",
+			StartTime:             job.StartTime,
+			LastUpdateTime:        time.Now(),
+			MatchData:             data,
+			Context:               map[string]interface{}{"step": "sending_metadata", "series_job_id": job.JobID, "map_number": i + 1},
+		}
+		mapJobIDs[i] = mapJob.JobID
+
+		if err := h.sendProgressUpdateWithMatchData(ctx, mapJob, data); err != nil {
+			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeProgressUpdate, types.ErrorSeverityInfo, "Failed to send progress update with match data", err)
+			h.progressManager.ReportParseError(parseError)
+		}
+
+		if err := h.sendAllEvents(ctx, mapJob, data); err != nil {
+			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "Failed to send events", err)
+			parseError = parseError.WithContext("job_id", mapJob.JobID)
+			h.progressManager.ReportParseError(parseError)
+
+			job.Status = types.StatusCallbackFailed
+			job.ErrorMessage = "Failed to send events"
+			if err := h.batchSender.SendError(ctx, job.JobID, job.CompletionCallbackURL, job.ErrorMessage); err != nil {
+				parseError = types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityCritical, "Failed to send error to Laravel", err)
+				h.progressManager.ReportParseError(parseError)
+			}
+			return
+		}
+
+		if err := h.batchSender.SendCompletion(ctx, mapJob.JobID, job.CompletionCallbackURL); err != nil {
+			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "Failed to send completion signal", err)
+			parseError = parseError.WithContext("job_id", mapJob.JobID)
+			h.progressManager.ReportParseError(parseError)
+		}
+	}
+
+	if err := h.batchSender.SendSeriesSummary(ctx, job.JobID, job.CompletionCallbackURL, series, mapJobIDs); err != nil {
+		job.Status = types.StatusCallbackFailed
+		job.ErrorMessage = "Failed to send series summary"
+		if err := h.batchSender.SendError(ctx, job.JobID, job.CompletionCallbackURL, job.ErrorMessage); err != nil {
+			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityCritical, "Failed to send error to Laravel", err)
+			h.progressManager.ReportParseError(parseError)
+		}
+		return
+	}
+
+	// Finalizing
+	job.Status = types.StatusFinalizing
+	job.CurrentStep = "Finalizing job"
+	job.Progress = 98
+	job.IsFinal = true
+	job.LastUpdateTime = time.Now()
+	job.Context["step"] = "finalization"
+
+	if err := h.sendProgressUpdate(ctx, job); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeProgressUpdate, types.ErrorSeverityInfo, "Failed to send progress update", err)
+		h.progressManager.ReportParseError(parseError)
+	}
+
+	if err := h.batchSender.SendCompletion(ctx, job.JobID, job.CompletionCallbackURL); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "Failed to send completion signal", err)
+		parseError = parseError.WithContext("job_id", job.JobID)
+		h.progressManager.ReportParseError(parseError)
+
+		job.Status = types.StatusCallbackFailed
+		job.ErrorMessage = "Failed to send completion signal"
+		if err := h.batchSender.SendError(ctx, job.JobID, job.CompletionCallbackURL, job.ErrorMessage); err != nil {
+			parseError = types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityCritical, "Failed to send error to Laravel", err)
+			h.progressManager.ReportParseError(parseError)
+		}
+		return
+	}
+
+	job.Status = types.StatusCompleted
+	job.Progress = 100
+	job.CurrentStep = "Completed"
+
+	jobTimer.WithMetadata("status", "completed").Stop()
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateMapNumbers(t *testing.T) {
+	tests := []struct {
+		name       string
+		mapNumbers []int
+		fileCount  int
+		wantErr    bool
+	}{
+		{"No map numbers", nil, 3, false},
+		{"One file per map", []int{1, 2, 3}, 3, false},
+		{"Map split across files", []int{1, 1, 2, 3}, 4, false},
+		{"Missing map numbers", []int{1, 2}, 3, true},
+		{"Not starting at 1", []int{2, 3}, 2, true},
+		{"Out of order", []int{1, 2, 1}, 3, true},
+		{"Skipped map", []int{1, 3}, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMapNumbers(tt.mapNumbers, tt.fileCount)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMapNumbers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGroupSeriesDemos(t *testing.T) {
+	paths := []string{"a.dem", "b.dem", "c.dem", "d.dem"}
+
+	groups := groupSeriesDemos(paths, []int{1, 2, 2, 3})
+	expected := [][]string{{"a.dem"}, {"b.dem", "c.dem"}, {"d.dem"}}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("Expected %v, got %v", expected, groups)
+	}
+
+	groups = groupSeriesDemos(paths, nil)
+	if len(groups) != 4 {
+		t.Errorf("Expected every demo to be a map, got %v", groups)
+	}
+
+	if id := seriesMapJobID("job", 2); id != "job-map-2" {
+		t.Errorf("Expected job-map-2, got %s", id)
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"parser-service/internal/database"
+	"parser-service/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ShootingDataHandler struct {
+	logger            *logrus.Logger
+	playerTickService *database.PlayerTickService
+}
+
+// SprayBreakdown summarises one player's shots with one weapon
+type SprayBreakdown struct {
+	PlayerID         string  `json:"player_id"`
+	WeaponName       string  `json:"weapon_name"`
+	TotalShots       int     `json:"total_shots"`
+	SprayingShots    int     `json:"spraying_shots"`
+	SpraySequences   int     `json:"spray_sequences"`
+	LongestSpray     int     `json:"longest_spray"`
+	SprayingShotRate float64 `json:"spraying_shot_rate"`
+}
+
+func NewShootingDataHandler(logger *logrus.Logger, playerTickService *database.PlayerTickService) *ShootingDataHandler {
+	return &ShootingDataHandler{
+		logger:            logger,
+		playerTickService: playerTickService,
+	}
+}
+
+// GET /api/matches/:match_id/shots
+// What this does:
+// Returns the persisted shots of a match in firing order
+// Optional query filters: player_id, round, weapon
+// Includes a per player and weapon spray breakdown of the returned shots
+
+func (h *ShootingDataHandler) HandleGetShots(c *gin.Context) {
+	filter := database.ShootingDataFilter{
+		MatchID:    c.Param("match_id"),
+		PlayerID:   c.Query("player_id"),
+		WeaponName: c.Query("weapon"),
+	}
+
+	if filter.MatchID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "match_id is required",
+		})
+		return
+	}
+
+	if round := c.Query("round"); round != "" {
+		roundNumber, err := strconv.Atoi(round)
+		if err != nil || roundNumber < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "round must be a positive integer",
+			})
+			return
+		}
+		filter.RoundNumber = roundNumber
+	}
+
+	shots, err := h.playerTickService.GetPlayerShootingData(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"match_id": filter.MatchID,
+			"error":    err,
+		}).Error("Failed to query shooting data")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to query shooting data",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"match_id":        filter.MatchID,
+		"count":           len(shots),
+		"shots":           shots,
+		"spray_breakdown": buildSprayBreakdown(shots),
+	})
+}
+
+// buildSprayBreakdown groups shots by player and weapon. A spray sequence is a run
+// of consecutive spraying shots within a round.
+func buildSprayBreakdown(shots []*types.PlayerShootingData) []SprayBreakdown {
+	type groupKey struct {
+		playerID string
+		weapon   string
+	}
+
+	groups := make(map[groupKey]*SprayBreakdown)
+	currentRun := make(map[groupKey]int)
+	lastRound := make(map[groupKey]int)
+
+	for _, shot := range shots {
+		key := groupKey{playerID: shot.PlayerID, weapon: shot.WeaponName}
+		breakdown, exists := groups[key]
+		if !exists {
+			breakdown = &SprayBreakdown{PlayerID: shot.PlayerID, WeaponName: shot.WeaponName}
+			groups[key] = breakdown
+		}
+
+		// Sprays never continue across rounds
+		if lastRound[key] != shot.RoundNumber {
+			currentRun[key] = 0
+			lastRound[key] = shot.RoundNumber
+		}
+
+		breakdown.TotalShots++
+		if !shot.IsSpraying {
+			currentRun[key] = 0
+			continue
+		}
+
+		breakdown.SprayingShots++
+		if currentRun[key] == 0 {
+			breakdown.SpraySequences++
+		}
+		currentRun[key]++
+		if currentRun[key] > breakdown.LongestSpray {
+			breakdown.LongestSpray = currentRun[key]
+		}
+	}
+
+	result := make([]SprayBreakdown, 0, len(groups))
+	for _, breakdown := range groups {
+		if breakdown.TotalShots > 0 {
+			breakdown.SprayingShotRate = float64(breakdown.SprayingShots) / float64(breakdown.TotalShots)
+		}
+		result = append(result, *breakdown)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PlayerID != result[j].PlayerID {
+			return result[i].PlayerID < result[j].PlayerID
+		}
+		return result[i].WeaponName < result[j].WeaponName
+	})
+
+	return result
+}
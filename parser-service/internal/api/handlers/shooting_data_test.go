@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"parser-service/internal/database"
+	"parser-service/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupShootingDataRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&types.PlayerShootingData{}))
+
+	logger := logrus.New()
+	service := database.NewPlayerTickService(db, logger)
+
+	shots := []*types.PlayerShootingData{
+		{MatchID: "match-1", RoundNumber: 1, Tick: 100, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle", IsSpraying: true},
+		{MatchID: "match-1", RoundNumber: 1, Tick: 106, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle", IsSpraying: true},
+		{MatchID: "match-1", RoundNumber: 1, Tick: 300, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+		{MatchID: "match-1", RoundNumber: 1, Tick: 400, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle", IsSpraying: true},
+		{MatchID: "match-1", RoundNumber: 2, Tick: 900, PlayerID: "player-2", WeaponName: "M4A4", WeaponCategory: "rifle"},
+	}
+	require.NoError(t, service.SavePlayerShootingDataBatch(context.Background(), shots))
+
+	handler := NewShootingDataHandler(logger, service)
+	router := gin.New()
+	router.GET("/api/matches/:match_id/shots", handler.HandleGetShots)
+	return router
+}
+
+func TestShootingDataHandler_HandleGetShots(t *testing.T) {
+	router := setupShootingDataRouter(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/matches/match-1/shots?player_id=player-1&round=1&weapon=AK-47", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Success        bool                       `json:"success"`
+		MatchID        string                     `json:"match_id"`
+		Count          int                        `json:"count"`
+		Shots          []types.PlayerShootingData `json:"shots"`
+		SprayBreakdown []SprayBreakdown           `json:"spray_breakdown"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	assert.True(t, response.Success)
+	assert.Equal(t, "match-1", response.MatchID)
+	assert.Equal(t, 4, response.Count)
+	require.Len(t, response.Shots, 4)
+	assert.Equal(t, int64(100), response.Shots[0].Tick)
+
+	require.Len(t, response.SprayBreakdown, 1)
+	breakdown := response.SprayBreakdown[0]
+	assert.Equal(t, "player-1", breakdown.PlayerID)
+	assert.Equal(t, 4, breakdown.TotalShots)
+	assert.Equal(t, 3, breakdown.SprayingShots)
+	assert.Equal(t, 2, breakdown.SpraySequences)
+	assert.Equal(t, 2, breakdown.LongestSpray)
+	assert.InDelta(t, 0.75, breakdown.SprayingShotRate, 0.001)
+}
+
+func TestShootingDataHandler_HandleGetShots_InvalidRound(t *testing.T) {
+	router := setupShootingDataRouter(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/matches/match-1/shots?round=abc", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestShootingDataHandler_HandleGetShots_UnknownMatch(t *testing.T) {
+	router := setupShootingDataRouter(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/matches/unknown/shots", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(0), response["count"])
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/spf13/viper"
@@ -37,6 +38,48 @@ type ParserConfig struct {
 	MaxDemoSize       int64         `mapstructure:"max_demo_size"`
 	TempDir           string        `mapstructure:"temp_dir"`
 	TickSampleRate    int           `mapstructure:"tick_sample_rate"` // Store every Nth tick (1=all, 2=every 2nd, 3=every 3rd)
+	// Extra match type rules, evaluated after the built in ones
+	MatchTypeRules []MatchTypeRuleConfig `mapstructure:"match_type_rules"`
+	// Return the completed rounds of a truncated or corrupt demo instead of failing
+	TolerantParsing bool `mapstructure:"tolerant_parsing"`
+	// Report bots as players. Kills made while a human controls a bot are credited to the human either way.
+	IncludeBots bool `mapstructure:"include_bots"`
+	// Named areas by map name, used to name positions the demo has no place name for
+	MapZones map[string][]MapZoneConfig `mapstructure:"map_zones"`
+	// Add radar image coordinates to every position. Overview files in RadarDir add
+	// maps or replace the built in ones.
+	RadarCoordinates bool   `mapstructure:"radar_coordinates"`
+	RadarDir         string `mapstructure:"radar_dir"`
+	// Store the kill, death and grenade positions of every match for the heatmap API.
+	// The density layer reads the tick data, which is then kept even with cleanup_on_finish
+	// until tick_data_retention expires.
+	Heatmaps bool `mapstructure:"heatmaps"`
+	// Build a 2D replay of every round from the stored tick data, with a track frame
+	// every ReplayInterval ticks
+	RoundReplays   bool `mapstructure:"round_replays"`
+	ReplayInterval int  `mapstructure:"replay_interval"`
+	// Trajectory points kept per grenade, 0 turns trajectories off
+	GrenadeTrajectoryPoints int `mapstructure:"grenade_trajectory_points"`
+	// Seconds after a death in which killing the killer counts as a trade
+	TradeWindowSeconds float64 `mapstructure:"trade_window_seconds"`
+}
+
+// MapZoneConfig names an area of a map. The polygon is a list of [x, y] points in
+// world coordinates; MinZ and MaxZ optionally limit it to one level of the map.
+type MapZoneConfig struct {
+	Name    string      `mapstructure:"name"`
+	Polygon [][]float64 `mapstructure:"polygon"`
+	MinZ    *float64    `mapstructure:"min_z"`
+	MaxZ    *float64    `mapstructure:"max_z"`
+}
+
+// MatchTypeRuleConfig recognises a platform from its server names or plugin convars
+type MatchTypeRuleConfig struct {
+	Name               string   `mapstructure:"name"`
+	MatchType          string   `mapstructure:"match_type"`
+	Confidence         float64  `mapstructure:"confidence"`
+	ServerNamePatterns []string `mapstructure:"server_name_patterns"` // Words matched case-insensitively
+	ConVarPrefixes     []string `mapstructure:"convar_prefixes"`
 }
 
 type BatchConfig struct {
@@ -69,6 +112,17 @@ type DatabaseConfig struct {
 	MaxIdle         int    `mapstructure:"max_idle"`
 	MaxOpen         int    `mapstructure:"max_open"`
 	CleanupOnFinish bool   `mapstructure:"cleanup_on_finish"`
+	TickStorage     string `mapstructure:"tick_storage"` // "columnar" or "rows"
+
+	// Background tick writer settings
+	TickWriterQueueSize     int           `mapstructure:"tick_writer_queue_size"`
+	TickWriterBatchSize     int           `mapstructure:"tick_writer_batch_size"`
+	TickWriterFlushInterval time.Duration `mapstructure:"tick_writer_flush_interval"`
+
+	// How long persisted shooting data is kept; 0 keeps it forever
+	ShootingDataRetention time.Duration `mapstructure:"shooting_data_retention"`
+	// How long tick data kept after a parse is kept, e.g. for heatmaps; 0 keeps it forever
+	TickDataRetention time.Duration `mapstructure:"tick_data_retention"`
 }
 
 type AimProcessingConfig struct {
@@ -99,9 +153,24 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
+// validate rejects settings that only take one of a fixed set of values
+func (c *Config) validate() error {
+	switch c.Database.TickStorage {
+	case "columnar", "rows":
+	default:
+		return fmt.Errorf("database.tick_storage must be \"columnar\" or \"rows\", got %q", c.Database.TickStorage)
+	}
+
+	return nil
+}
+
 // This is used to set the default values for the config file
 // If the config file doesn't have a value for a field, it will use the default value
 func setDefaults() {
@@ -117,6 +186,15 @@ func setDefaults() {
 	viper.SetDefault("parser.max_demo_size", 500*1024*1024)
 	viper.SetDefault("parser.temp_dir", "/tmp/parser-service")
 	viper.SetDefault("parser.tick_sample_rate", 2) // Default: store every 2nd tick (50% reduction)
+	viper.SetDefault("parser.tolerant_parsing", true)
+	viper.SetDefault("parser.include_bots", false)
+	viper.SetDefault("parser.radar_coordinates", false)
+	viper.SetDefault("parser.radar_dir", "")
+	viper.SetDefault("parser.heatmaps", true)
+	viper.SetDefault("parser.round_replays", false)
+	viper.SetDefault("parser.replay_interval", 16)
+	viper.SetDefault("parser.grenade_trajectory_points", 64)
+	viper.SetDefault("parser.trade_window_seconds", 3.0)
 
 	viper.SetDefault("batch.gunfight_events_size", 100)
 	viper.SetDefault("batch.grenade_events_size", 50)
@@ -143,6 +221,12 @@ func setDefaults() {
 	viper.SetDefault("database.max_idle", 10)
 	viper.SetDefault("database.max_open", 100)
 	viper.SetDefault("database.cleanup_on_finish", false)
+	viper.SetDefault("database.tick_storage", "columnar")
+	viper.SetDefault("database.tick_writer_queue_size", 256)
+	viper.SetDefault("database.tick_writer_batch_size", 10000)
+	viper.SetDefault("database.tick_writer_flush_interval", "2s")
+	viper.SetDefault("database.shooting_data_retention", "720h") // 30 days
+	viper.SetDefault("database.tick_data_retention", "720h")
 
 	viper.SetDefault("aim_processing.limit_aim_processing", false)
 	viper.SetDefault("aim_processing.player_ids", []string{})
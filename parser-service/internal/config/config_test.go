@@ -27,6 +27,7 @@ func TestLoad_DefaultValues(t *testing.T) {
 	assert.Equal(t, 5*time.Second, cfg.Parser.ProgressInterval)
 	assert.Equal(t, int64(500*1024*1024), cfg.Parser.MaxDemoSize) // 500MB
 	assert.Equal(t, "/tmp/parser-service", cfg.Parser.TempDir)
+	assert.True(t, cfg.Parser.Heatmaps)
 
 	assert.Equal(t, 100, cfg.Batch.GunfightEventsSize)
 	assert.Equal(t, 50, cfg.Batch.GrenadeEventsSize)
@@ -348,6 +349,12 @@ func TestLoad_DatabaseConfigDefaults(t *testing.T) {
 	assert.Equal(t, 10, cfg.Database.MaxIdle)
 	assert.Equal(t, 100, cfg.Database.MaxOpen)
 	assert.Equal(t, false, cfg.Database.CleanupOnFinish)
+	assert.Equal(t, "columnar", cfg.Database.TickStorage)
+	assert.Equal(t, 256, cfg.Database.TickWriterQueueSize)
+	assert.Equal(t, 10000, cfg.Database.TickWriterBatchSize)
+	assert.Equal(t, 2*time.Second, cfg.Database.TickWriterFlushInterval)
+	assert.Equal(t, 720*time.Hour, cfg.Database.ShootingDataRetention)
+	assert.Equal(t, 720*time.Hour, cfg.Database.TickDataRetention)
 }
 
 func TestLoad_DatabaseConfigFromFile(t *testing.T) {
@@ -395,6 +402,42 @@ database:
 	assert.Equal(t, true, cfg.Database.CleanupOnFinish)
 }
 
+func TestLoad_MapZonesFromFile(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	configContent := `
+parser:
+  map_zones:
+    de_mirage:
+      - name: "B Apartments"
+        polygon: [[-2300, 600], [-1700, 600], [-1700, 1000], [-2300, 1000]]
+        min_z: -200
+`
+
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	assert.NoError(t, err)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+
+	err = os.Chdir(tempDir)
+	assert.NoError(t, err)
+
+	cfg, err := Load()
+
+	assert.NoError(t, err)
+	if assert.Len(t, cfg.Parser.MapZones["de_mirage"], 1) {
+		zone := cfg.Parser.MapZones["de_mirage"][0]
+		assert.Equal(t, "B Apartments", zone.Name)
+		assert.Equal(t, [][]float64{{-2300, 600}, {-1700, 600}, {-1700, 1000}, {-2300, 1000}}, zone.Polygon)
+		if assert.NotNil(t, zone.MinZ) {
+			assert.Equal(t, -200.0, *zone.MinZ)
+		}
+		assert.Nil(t, zone.MaxZ)
+	}
+}
+
 func TestLoad_EnvironmentVariableOverrides(t *testing.T) {
 	// Skip this test for now as viper state is shared across tests
 	// This would require a more complex setup to isolate viper state
@@ -462,6 +505,33 @@ parser:
 	assert.Nil(t, cfg)
 }
 
+func TestLoad_InvalidTickStorageInConfig(t *testing.T) {
+	// Create a config file with a misspelled tick storage format
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	configContent := `
+database:
+  tick_storage: "columner"
+`
+
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	assert.NoError(t, err)
+
+	// Change to the temp directory so viper can find the config
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+
+	err = os.Chdir(tempDir)
+	assert.NoError(t, err)
+
+	cfg, err := Load()
+
+	// This should fail because the format is unknown
+	assert.ErrorContains(t, err, "tick_storage")
+	assert.Nil(t, cfg)
+}
+
 func TestLoad_ConfigFileInSubdirectory(t *testing.T) {
 	// Create a config file in a subdirectory
 	tempDir := t.TempDir()
@@ -0,0 +1,351 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrTickWriterClosed is returned when writing to a tick writer that has been closed
+var ErrTickWriterClosed = errors.New("tick writer is closed")
+
+// TickWriterOptions configures an AsyncTickWriter
+type TickWriterOptions struct {
+	// Format is the storage format, types.TickStorageColumnar or types.TickStorageRows
+	Format string
+	// QueueSize bounds the number of frames waiting to be written. Writers block
+	// once the queue is full instead of dropping data.
+	QueueSize int
+	// BatchSize is the number of rows accumulated before a multi-row insert
+	BatchSize int
+	// FlushInterval flushes accumulated rows even if BatchSize is not reached
+	FlushInterval time.Duration
+}
+
+type tickWriteRequest struct {
+	roundNumber int
+	rows        []*types.PlayerTickData
+	shots       []*types.PlayerShootingData
+	// discardShotsFrom removes the shots of this round and later ones written or
+	// queued so far; 0 means no discard
+	discardShotsFrom int
+	// mapRound renumbers the tick data written or queued so far; nil means no renumbering
+	mapRound func(int) int
+	flushed  chan error
+}
+
+// AsyncTickWriter writes player tick data on a background goroutine so demo parsing
+// does not wait on database round trips. The first write failure is kept and
+// returned from every subsequent call so the caller can fail the job.
+type AsyncTickWriter struct {
+	service *PlayerTickService
+	logger  *logrus.Logger
+	matchID string
+	options TickWriterOptions
+
+	queue     chan tickWriteRequest
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// sendMu guards closed and sends on queue; errMu guards err. They are separate
+	// so a producer blocked on a full queue never stalls the background goroutine.
+	sendMu sync.Mutex
+	closed bool
+	errMu  sync.Mutex
+	err    error
+}
+
+// NewAsyncTickWriter creates a tick writer for a match and starts its background goroutine
+func NewAsyncTickWriter(service *PlayerTickService, logger *logrus.Logger, matchID string, options TickWriterOptions) *AsyncTickWriter {
+	if options.QueueSize < 1 {
+		options.QueueSize = 1
+	}
+	if options.BatchSize < 1 {
+		options.BatchSize = 1000
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = time.Second
+	}
+
+	w := &AsyncTickWriter{
+		service: service,
+		logger:  logger,
+		matchID: matchID,
+		options: options,
+		queue:   make(chan tickWriteRequest, options.QueueSize),
+		done:    make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+// Write queues the tick rows of one frame. It blocks while the queue is full.
+func (w *AsyncTickWriter) Write(ctx context.Context, roundNumber int, rows []*types.PlayerTickData) error {
+	if len(rows) == 0 {
+		return w.Err()
+	}
+
+	return w.enqueue(ctx, tickWriteRequest{roundNumber: roundNumber, rows: rows})
+}
+
+// WriteShots queues shooting data for a round. Shots are written in batches
+// alongside tick rows and are always stored as rows.
+func (w *AsyncTickWriter) WriteShots(ctx context.Context, shots []*types.PlayerShootingData) error {
+	if len(shots) == 0 {
+		return w.Err()
+	}
+
+	return w.enqueue(ctx, tickWriteRequest{shots: shots})
+}
+
+// DiscardShots drops the shooting data written so far for fromRound and every later
+// round, e.g. after a match restart or a backup restore. Shots queued after the call
+// are kept.
+func (w *AsyncTickWriter) DiscardShots(ctx context.Context, fromRound int) error {
+	if fromRound < 1 {
+		fromRound = 1
+	}
+	return w.enqueue(ctx, tickWriteRequest{discardShotsFrom: fromRound})
+}
+
+// RenumberRounds applies a round renumbering to the tick data written or queued so
+// far, so dropped warmup, knife and restarted rounds do not leave ticks behind.
+// mapRound returns the new number of a round, or 0 to delete its ticks.
+func (w *AsyncTickWriter) RenumberRounds(ctx context.Context, mapRound func(int) int) error {
+	return w.enqueue(ctx, tickWriteRequest{mapRound: mapRound})
+}
+
+// Flush blocks until everything queued before the call has been written
+func (w *AsyncTickWriter) Flush(ctx context.Context) error {
+	flushed := make(chan error, 1)
+	if err := w.enqueue(ctx, tickWriteRequest{flushed: flushed}); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-flushed:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close writes any pending data, stops the background goroutine and returns the
+// first write error, if any. It is safe to call more than once.
+func (w *AsyncTickWriter) Close(ctx context.Context) error {
+	w.closeOnce.Do(func() {
+		w.sendMu.Lock()
+		w.closed = true
+		close(w.queue)
+		w.sendMu.Unlock()
+	})
+
+	select {
+	case <-w.done:
+		return w.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Err returns the first write error encountered by the background goroutine
+func (w *AsyncTickWriter) Err() error {
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+	return w.err
+}
+
+func (w *AsyncTickWriter) enqueue(ctx context.Context, request tickWriteRequest) error {
+	if err := w.Err(); err != nil {
+		return err
+	}
+
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+
+	if w.closed {
+		return ErrTickWriterClosed
+	}
+
+	select {
+	case w.queue <- request:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *AsyncTickWriter) setErr(err error) {
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+func (w *AsyncTickWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+
+	// Columnar data is only written at flush barriers so each round stays in as
+	// few blobs as possible; rows are written on size or time.
+	blobWriter := NewTickBlobWriter(w.service, w.matchID)
+	var pendingRows []*types.PlayerTickData
+	var pendingShots []*types.PlayerShootingData
+	// spans tracks the ticks written under each round number, since rows have no round
+	spans := make(map[int]TickSpan)
+
+	// flush writes pending rows and shots; blobs are only included at barriers
+	flush := func(includeBlobs bool) error {
+		if err := w.Err(); err != nil {
+			pendingRows = nil
+			pendingShots = nil
+			return err
+		}
+
+		ctx := context.Background()
+		if len(pendingRows) > 0 {
+			if err := w.service.SavePlayerTickDataBatch(ctx, pendingRows); err != nil {
+				w.setErr(fmt.Errorf("tick writer failed: %w", err))
+			}
+			pendingRows = nil
+		}
+		if len(pendingShots) > 0 {
+			if err := w.service.SavePlayerShootingDataBatch(ctx, pendingShots); err != nil {
+				w.setErr(fmt.Errorf("tick writer failed: %w", err))
+			}
+			pendingShots = nil
+		}
+		if includeBlobs && blobWriter.BufferedTicks() > 0 {
+			if err := blobWriter.Flush(ctx); err != nil {
+				w.setErr(fmt.Errorf("tick writer failed: %w", err))
+			}
+		}
+
+		return w.Err()
+	}
+
+	for {
+		select {
+		case request, ok := <-w.queue:
+			if !ok {
+				if err := flush(true); err != nil {
+					w.logger.WithFields(logrus.Fields{
+						"match_id": w.matchID,
+						"error":    err,
+					}).Error("Tick writer closed with error")
+				}
+				return
+			}
+
+			if request.flushed != nil {
+				request.flushed <- flush(true)
+				continue
+			}
+
+			// After a failure keep draining so producers never block forever
+			if w.Err() != nil {
+				continue
+			}
+
+			if request.discardShotsFrom > 0 {
+				keptShots := pendingShots[:0]
+				for _, shot := range pendingShots {
+					if shot.RoundNumber < request.discardShotsFrom {
+						keptShots = append(keptShots, shot)
+					}
+				}
+				pendingShots = keptShots
+				if err := w.service.DeletePlayerShootingDataFromRound(context.Background(), w.matchID, request.discardShotsFrom); err != nil {
+					w.setErr(fmt.Errorf("tick writer failed: %w", err))
+				}
+				continue
+			}
+
+			if request.mapRound != nil {
+				renumbered := make(map[int]TickSpan, len(spans))
+				var dropped []TickSpan
+				for round, span := range spans {
+					newRound := request.mapRound(round)
+					if newRound == 0 {
+						dropped = append(dropped, span)
+						continue
+					}
+					if existing, exists := renumbered[newRound]; exists {
+						span.StartTick = min(span.StartTick, existing.StartTick)
+						span.EndTick = max(span.EndTick, existing.EndTick)
+					}
+					renumbered[newRound] = span
+				}
+				spans = renumbered
+
+				keptRows := pendingRows[:0]
+				for _, row := range pendingRows {
+					if !inTickSpans(row.Tick, dropped) {
+						keptRows = append(keptRows, row)
+					}
+				}
+				pendingRows = keptRows
+				blobWriter.Renumber(request.mapRound)
+
+				if err := w.service.RenumberPlayerTickData(context.Background(), w.matchID, request.mapRound, dropped); err != nil {
+					w.setErr(fmt.Errorf("tick writer failed: %w", err))
+				}
+				continue
+			}
+
+			if len(request.shots) > 0 {
+				pendingShots = append(pendingShots, request.shots...)
+				if len(pendingShots) >= w.options.BatchSize {
+					flush(false)
+				}
+				continue
+			}
+
+			for _, row := range request.rows {
+				span, exists := spans[request.roundNumber]
+				if !exists {
+					span = TickSpan{StartTick: row.Tick, EndTick: row.Tick}
+				}
+				span.StartTick = min(span.StartTick, row.Tick)
+				span.EndTick = max(span.EndTick, row.Tick)
+				spans[request.roundNumber] = span
+			}
+
+			if w.options.Format == types.TickStorageRows {
+				pendingRows = append(pendingRows, request.rows...)
+				if len(pendingRows) >= w.options.BatchSize {
+					flush(false)
+				}
+			} else {
+				for _, row := range request.rows {
+					blobWriter.Append(request.roundNumber, row)
+				}
+			}
+
+		case <-ticker.C:
+			if len(pendingRows) > 0 || len(pendingShots) > 0 {
+				flush(false)
+			}
+		}
+	}
+}
+
+func inTickSpans(tick int64, spans []TickSpan) bool {
+	for _, span := range spans {
+		if tick >= span.StartTick && tick <= span.EndTick {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,280 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newTestTickDB opens an in-memory database limited to one connection, since every
+// new SQLite :memory: connection would otherwise see its own empty database
+func newTestTickDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	return db
+}
+
+func newTestTickFrame(tick int64) []*types.PlayerTickData {
+	return []*types.PlayerTickData{
+		{MatchID: "test-match-123", PlayerID: "player-1", Tick: tick, Team: "A"},
+		{MatchID: "test-match-123", PlayerID: "player-2", Tick: tick, Team: "B"},
+	}
+}
+
+func TestAsyncTickWriter_RowsFlushOnSize(t *testing.T) {
+	db := newTestTickDB(t)
+
+	err := db.AutoMigrate(&types.PlayerTickData{})
+	assert.NoError(t, err)
+
+	service := NewPlayerTickService(db, logrus.New())
+	writer := NewAsyncTickWriter(service, logrus.New(), "test-match-123", TickWriterOptions{
+		Format:        types.TickStorageRows,
+		QueueSize:     4,
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+	})
+
+	ctx := context.Background()
+	for tick := int64(0); tick < 5; tick++ {
+		assert.NoError(t, writer.Write(ctx, 1, newTestTickFrame(tick)))
+	}
+
+	// A flush barrier guarantees the rows are visible
+	assert.NoError(t, writer.Flush(ctx))
+
+	var count int64
+	err = db.Model(&types.PlayerTickData{}).Count(&count).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), count)
+
+	assert.NoError(t, writer.Close(ctx))
+}
+
+func TestAsyncTickWriter_RowsFlushOnInterval(t *testing.T) {
+	db := newTestTickDB(t)
+
+	err := db.AutoMigrate(&types.PlayerTickData{})
+	assert.NoError(t, err)
+
+	service := NewPlayerTickService(db, logrus.New())
+	writer := NewAsyncTickWriter(service, logrus.New(), "test-match-123", TickWriterOptions{
+		Format:        types.TickStorageRows,
+		QueueSize:     4,
+		BatchSize:     1000,
+		FlushInterval: 10 * time.Millisecond,
+	})
+	defer writer.Close(context.Background())
+
+	assert.NoError(t, writer.Write(context.Background(), 1, newTestTickFrame(100)))
+
+	assert.Eventually(t, func() bool {
+		var count int64
+		db.Model(&types.PlayerTickData{}).Count(&count)
+		return count == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestAsyncTickWriter_ColumnarFlushBeforeLoad(t *testing.T) {
+	db := newTestTickDB(t)
+
+	err := db.AutoMigrate(&types.PlayerTickBlob{})
+	assert.NoError(t, err)
+
+	service := NewPlayerTickService(db, logrus.New())
+	writer := NewAsyncTickWriter(service, logrus.New(), "test-match-123", TickWriterOptions{
+		Format:    types.TickStorageColumnar,
+		QueueSize: 2,
+	})
+
+	ctx := context.Background()
+	for tick := int64(1000); tick < 1100; tick++ {
+		assert.NoError(t, writer.Write(ctx, 1, newTestTickFrame(tick)))
+	}
+	assert.NoError(t, writer.Flush(ctx))
+
+	data, err := service.GetPlayerTickDataFromBlobs(ctx, "test-match-123", 1000, 1099)
+	assert.NoError(t, err)
+	assert.Len(t, data, 200)
+
+	assert.NoError(t, writer.Close(ctx))
+	assert.ErrorIs(t, writer.Write(ctx, 1, newTestTickFrame(2000)), ErrTickWriterClosed)
+}
+
+func TestAsyncTickWriter_SurfacesWriteErrors(t *testing.T) {
+	// No migration, so every insert fails
+	db := newTestTickDB(t)
+
+	service := NewPlayerTickService(db, logrus.New())
+	writer := NewAsyncTickWriter(service, logrus.New(), "test-match-123", TickWriterOptions{
+		Format:        types.TickStorageRows,
+		QueueSize:     1,
+		BatchSize:     1,
+		FlushInterval: time.Hour,
+	})
+
+	ctx := context.Background()
+	assert.NoError(t, writer.Write(ctx, 1, newTestTickFrame(1)))
+
+	err := writer.Flush(ctx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "tick writer failed")
+
+	// The first failure sticks for every later call
+	assert.Error(t, writer.Write(ctx, 1, newTestTickFrame(2)))
+	assert.Error(t, writer.Close(ctx))
+}
+
+func TestAsyncTickWriter_WriteShots(t *testing.T) {
+	db := newTestTickDB(t)
+
+	err := db.AutoMigrate(&types.PlayerShootingData{}, &types.PlayerTickBlob{})
+	assert.NoError(t, err)
+
+	service := NewPlayerTickService(db, logrus.New())
+	writer := NewAsyncTickWriter(service, logrus.New(), "test-match-123", TickWriterOptions{
+		Format:        types.TickStorageColumnar,
+		QueueSize:     2,
+		FlushInterval: time.Hour,
+	})
+
+	ctx := context.Background()
+	shots := []*types.PlayerShootingData{
+		{MatchID: "test-match-123", RoundNumber: 1, Tick: 10, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+		{MatchID: "test-match-123", RoundNumber: 1, Tick: 15, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+	}
+	assert.NoError(t, writer.WriteShots(ctx, shots))
+	assert.NoError(t, writer.Close(ctx))
+
+	stored, err := service.GetPlayerShootingData(ctx, ShootingDataFilter{MatchID: "test-match-123"})
+	assert.NoError(t, err)
+	assert.Len(t, stored, 2)
+}
+
+func TestAsyncTickWriter_DiscardShots(t *testing.T) {
+	db := newTestTickDB(t)
+
+	err := db.AutoMigrate(&types.PlayerShootingData{}, &types.PlayerTickBlob{})
+	assert.NoError(t, err)
+
+	service := NewPlayerTickService(db, logrus.New())
+	writer := NewAsyncTickWriter(service, logrus.New(), "test-match-123", TickWriterOptions{
+		Format:        types.TickStorageColumnar,
+		QueueSize:     2,
+		FlushInterval: time.Hour,
+	})
+
+	ctx := context.Background()
+	assert.NoError(t, writer.WriteShots(ctx, []*types.PlayerShootingData{
+		{MatchID: "test-match-123", RoundNumber: 1, Tick: 10, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+		{MatchID: "test-match-123", RoundNumber: 2, Tick: 20, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+	}))
+	assert.NoError(t, writer.Flush(ctx))
+
+	// Persisted and still pending shots of dropped rounds go, later shots are kept
+	assert.NoError(t, writer.WriteShots(ctx, []*types.PlayerShootingData{
+		{MatchID: "test-match-123", RoundNumber: 3, Tick: 30, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+	}))
+	assert.NoError(t, writer.DiscardShots(ctx, 2))
+	assert.NoError(t, writer.WriteShots(ctx, []*types.PlayerShootingData{
+		{MatchID: "test-match-123", RoundNumber: 2, Tick: 500, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+	}))
+	assert.NoError(t, writer.Close(ctx))
+
+	stored, err := service.GetPlayerShootingData(ctx, ShootingDataFilter{MatchID: "test-match-123"})
+	assert.NoError(t, err)
+	if assert.Len(t, stored, 2) {
+		assert.Equal(t, int64(10), stored[0].Tick)
+		assert.Equal(t, int64(500), stored[1].Tick)
+	}
+}
+
+func TestAsyncTickWriter_RenumberRoundsColumnar(t *testing.T) {
+	db := newTestTickDB(t)
+
+	err := db.AutoMigrate(&types.PlayerTickData{}, &types.PlayerTickBlob{})
+	assert.NoError(t, err)
+
+	service := NewPlayerTickService(db, logrus.New())
+	writer := NewAsyncTickWriter(service, logrus.New(), "test-match-123", TickWriterOptions{
+		Format:    types.TickStorageColumnar,
+		QueueSize: 2,
+	})
+
+	ctx := context.Background()
+	// A knife round that is already stored, then the first live round still buffered
+	for tick := int64(1000); tick < 1010; tick++ {
+		assert.NoError(t, writer.Write(ctx, 1, newTestTickFrame(tick)))
+	}
+	assert.NoError(t, writer.Flush(ctx))
+	for tick := int64(2000); tick < 2010; tick++ {
+		assert.NoError(t, writer.Write(ctx, 2, newTestTickFrame(tick)))
+	}
+
+	assert.NoError(t, writer.RenumberRounds(ctx, func(round int) int { return round - 1 }))
+	assert.NoError(t, writer.Close(ctx))
+
+	var blobs []*types.PlayerTickBlob
+	assert.NoError(t, db.Order("player_id").Find(&blobs).Error)
+	if assert.Len(t, blobs, 2) {
+		for _, blob := range blobs {
+			assert.Equal(t, 1, blob.RoundNumber)
+			assert.Equal(t, int64(2000), blob.StartTick)
+		}
+	}
+}
+
+func TestAsyncTickWriter_RenumberRoundsRows(t *testing.T) {
+	db := newTestTickDB(t)
+
+	err := db.AutoMigrate(&types.PlayerTickData{}, &types.PlayerTickBlob{})
+	assert.NoError(t, err)
+
+	service := NewPlayerTickService(db, logrus.New())
+	writer := NewAsyncTickWriter(service, logrus.New(), "test-match-123", TickWriterOptions{
+		Format:        types.TickStorageRows,
+		QueueSize:     2,
+		BatchSize:     4,
+		FlushInterval: time.Hour,
+	})
+
+	ctx := context.Background()
+	for tick := int64(0); tick < 5; tick++ {
+		assert.NoError(t, writer.Write(ctx, 1, newTestTickFrame(tick)))
+	}
+	for tick := int64(10); tick < 13; tick++ {
+		assert.NoError(t, writer.Write(ctx, 2, newTestTickFrame(tick)))
+	}
+
+	// Round 2 is superseded, its rows are partly stored and partly pending
+	assert.NoError(t, writer.RenumberRounds(ctx, func(round int) int {
+		if round >= 2 {
+			return 0
+		}
+		return round
+	}))
+	assert.NoError(t, writer.Write(ctx, 2, newTestTickFrame(20)))
+	assert.NoError(t, writer.Close(ctx))
+
+	data, err := service.GetPlayerTickDataByMatch(ctx, "test-match-123")
+	assert.NoError(t, err)
+	ticks := make(map[int64]bool)
+	for _, row := range data {
+		ticks[row.Tick] = true
+	}
+	assert.Len(t, data, 12)
+	assert.False(t, ticks[10] || ticks[11] || ticks[12], "rows of the superseded round should be deleted")
+	assert.True(t, ticks[20], "rows of the replayed round should be kept")
+}
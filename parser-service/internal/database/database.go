@@ -62,7 +62,9 @@ func (d *Database) AutoMigrate() error {
 
 	err := d.DB.AutoMigrate(
 		&types.PlayerTickData{},
+		&types.PlayerTickBlob{},
 		&types.PlayerShootingData{},
+		&types.HeatmapPoint{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HeatmapPointFilter selects the stored points of a heatmap layer. An empty PlayerID
+// matches every player and a RoundNumber of 0 every round.
+type HeatmapPointFilter struct {
+	MatchID     string
+	Layer       string
+	PlayerID    string
+	RoundNumber int
+}
+
+// SaveHeatmapPoints saves the heatmap points of a match in a batch
+func (s *PlayerTickService) SaveHeatmapPoints(ctx context.Context, points []*types.HeatmapPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	if err := s.db.WithContext(ctx).CreateInBatches(points, 1000).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"batch_size": len(points),
+			"error":      err,
+		}).Error("Failed to save heatmap points")
+		return fmt.Errorf("failed to save heatmap points: %w", err)
+	}
+
+	return nil
+}
+
+// GetHeatmapPoints retrieves the stored points of a heatmap layer of a match
+func (s *PlayerTickService) GetHeatmapPoints(ctx context.Context, filter HeatmapPointFilter) ([]*types.HeatmapPoint, error) {
+	var points []*types.HeatmapPoint
+
+	query := s.db.WithContext(ctx).Where("match_id = ? AND layer = ?", filter.MatchID, filter.Layer)
+	if filter.PlayerID != "" {
+		query = query.Where("player_id = ?", filter.PlayerID)
+	}
+	if filter.RoundNumber > 0 {
+		query = query.Where("round_number = ?", filter.RoundNumber)
+	}
+
+	if err := query.Order("round_number ASC, id ASC").Find(&points).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"match_id":  filter.MatchID,
+			"layer":     filter.Layer,
+			"player_id": filter.PlayerID,
+			"round":     filter.RoundNumber,
+			"error":     err,
+		}).Error("Failed to get heatmap points")
+		return nil, fmt.Errorf("failed to get heatmap points: %w", err)
+	}
+
+	return points, nil
+}
+
+// DeleteHeatmapPointsByMatch deletes the heatmap points of every layer of a match
+func (s *PlayerTickService) DeleteHeatmapPointsByMatch(ctx context.Context, matchID string) error {
+	if err := s.db.WithContext(ctx).
+		Where("match_id = ?", matchID).
+		Delete(&types.HeatmapPoint{}).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"match_id": matchID,
+			"error":    err,
+		}).Error("Failed to delete heatmap points by match")
+		return fmt.Errorf("failed to delete heatmap points by match: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerTickService_HeatmapPoints(t *testing.T) {
+	db := newTestTickDB(t)
+	assert.NoError(t, db.AutoMigrate(&types.HeatmapPoint{}))
+
+	service := NewPlayerTickService(db, logrus.New())
+	ctx := context.Background()
+
+	points := []*types.HeatmapPoint{
+		{MatchID: "match-1", Layer: "kills", RoundNumber: 1, PlayerID: "player-1", PositionX: 100},
+		{MatchID: "match-1", Layer: "kills", RoundNumber: 2, PlayerID: "player-1", PositionX: 200},
+		{MatchID: "match-1", Layer: "kills", RoundNumber: 2, PlayerID: "player-2", PositionX: 300},
+		{MatchID: "match-1", Layer: "deaths", RoundNumber: 1, PlayerID: "player-2", PositionX: 400},
+		{MatchID: "match-2", Layer: "kills", RoundNumber: 1, PlayerID: "player-1", PositionX: 500},
+	}
+	assert.NoError(t, service.SaveHeatmapPoints(ctx, points))
+
+	kills, err := service.GetHeatmapPoints(ctx, HeatmapPointFilter{MatchID: "match-1", Layer: "kills"})
+	assert.NoError(t, err)
+	assert.Len(t, kills, 3)
+
+	byPlayer, err := service.GetHeatmapPoints(ctx, HeatmapPointFilter{MatchID: "match-1", Layer: "kills", PlayerID: "player-1"})
+	assert.NoError(t, err)
+	assert.Len(t, byPlayer, 2)
+
+	byRound, err := service.GetHeatmapPoints(ctx, HeatmapPointFilter{MatchID: "match-1", Layer: "kills", PlayerID: "player-1", RoundNumber: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, byRound, 1) {
+		assert.Equal(t, 200.0, byRound[0].PositionX)
+	}
+
+	assert.NoError(t, service.DeleteHeatmapPointsByMatch(ctx, "match-1"))
+	remaining, err := service.GetHeatmapPoints(ctx, HeatmapPointFilter{MatchID: "match-1", Layer: "deaths"})
+	assert.NoError(t, err)
+	assert.Empty(t, remaining)
+
+	other, err := service.GetHeatmapPoints(ctx, HeatmapPointFilter{MatchID: "match-2", Layer: "kills"})
+	assert.NoError(t, err)
+	assert.Len(t, other, 1)
+}
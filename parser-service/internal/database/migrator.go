@@ -0,0 +1,387 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// migrationFilePattern matches files like 0001_add_player_tick_index.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is a versioned pair of up and down SQL scripts
+type Migration struct {
+	Version  int64
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string
+}
+
+// MigrationStatus describes a migration and whether it has been applied
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+	// Modified is set when the up script changed after it was applied
+	Modified bool
+	// Missing is set when an applied version has no file on disk
+	Missing bool
+}
+
+// Migrator applies versioned SQL migrations and records them in schema_migrations
+type Migrator struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+	dir    string
+}
+
+// NewMigrator creates a new migrator for the migrations in dir
+func NewMigrator(db *gorm.DB, logger *logrus.Logger, dir string) *Migrator {
+	return &Migrator{
+		db:     db,
+		logger: logger,
+		dir:    dir,
+	}
+}
+
+// LoadMigrations reads and orders the migrations in dir. Every version needs an up
+// script; the down script is optional but required to roll the version back.
+func LoadMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q, expected <version>_<name>.up.sql or .down.sql", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", entry.Name(), err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d used by both %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.UpSQL = string(content)
+			migration.Checksum = checksum(content)
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.UpSQL == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// CreateMigration writes empty up and down scripts for the next version in dir
+func CreateMigration(dir, name string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration name is required")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create migrations directory: %w", err)
+	}
+
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	next := int64(1)
+	if len(migrations) > 0 {
+		next = migrations[len(migrations)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%04d_%s", next, name)
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+
+	if err := os.WriteFile(upPath, []byte(fmt.Sprintf("-- Migration: %s\n", name)), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write up migration: %w", err)
+	}
+	if err := os.WriteFile(downPath, []byte(fmt.Sprintf("-- Rollback: %s\n", name)), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write down migration: %w", err)
+	}
+
+	return upPath, downPath, nil
+}
+
+// Status returns every known migration with its applied state, including applied
+// versions whose files are no longer on disk
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(m.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Migration: migration}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Modified = record.Checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range applied {
+		appliedAt := record.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Migration: Migration{Version: record.Version, Name: record.Name, Checksum: record.Checksum},
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Missing:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Up applies all pending migrations in version order. It refuses to run if an
+// applied migration has been modified since it was applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, status := range statuses {
+		if status.Modified {
+			return nil, fmt.Errorf("migration %d_%s was modified after it was applied (checksum mismatch)", status.Version, status.Name)
+		}
+	}
+
+	var applied []Migration
+	for _, status := range statuses {
+		if status.Applied {
+			continue
+		}
+
+		if err := m.apply(ctx, status.Migration, status.UpSQL, true); err != nil {
+			return applied, err
+		}
+		applied = append(applied, status.Migration)
+
+		m.logger.WithFields(logrus.Fields{
+			"version": status.Version,
+			"name":    status.Name,
+		}).Info("Applied migration")
+	}
+
+	return applied, nil
+}
+
+// Down rolls back the most recently applied steps migrations
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("number of migrations to roll back must be at least 1")
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rolledBack []Migration
+	for i := len(statuses) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+		status := statuses[i]
+		if !status.Applied {
+			continue
+		}
+		if status.Missing {
+			return rolledBack, fmt.Errorf("cannot roll back migration %d_%s: file is missing", status.Version, status.Name)
+		}
+		if strings.TrimSpace(stripSQLComments(status.DownSQL)) == "" {
+			return rolledBack, fmt.Errorf("cannot roll back migration %d_%s: no down script", status.Version, status.Name)
+		}
+
+		if err := m.apply(ctx, status.Migration, status.DownSQL, false); err != nil {
+			return rolledBack, err
+		}
+		rolledBack = append(rolledBack, status.Migration)
+
+		m.logger.WithFields(logrus.Fields{
+			"version": status.Version,
+			"name":    status.Name,
+		}).Info("Rolled back migration")
+	}
+
+	return rolledBack, nil
+}
+
+// apply runs a script and updates schema_migrations. Databases with transactional
+// DDL run both in one transaction; MySQL commits DDL implicitly, so there the
+// version is only recorded after every statement succeeded. Either way the script
+// runs on a single connection so session variables carry across statements.
+func (m *Migrator) apply(ctx context.Context, migration Migration, script string, up bool) error {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+
+	run := func(tx *gorm.DB) error {
+		for _, statement := range splitSQLStatements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("migration %d_%s (%s) failed: %w", migration.Version, migration.Name, direction, err)
+			}
+		}
+
+		if up {
+			record := &types.SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum,
+				AppliedAt: time.Now(),
+			}
+			if err := tx.Create(record).Error; err != nil {
+				return fmt.Errorf("failed to record migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			return nil
+		}
+
+		if err := tx.Where("version = ?", migration.Version).Delete(&types.SchemaMigration{}).Error; err != nil {
+			return fmt.Errorf("failed to remove migration record %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		return nil
+	}
+
+	db := m.db.WithContext(ctx)
+	if supportsTransactionalDDL(db) {
+		return db.Transaction(run)
+	}
+	return db.Connection(run)
+}
+
+func (m *Migrator) appliedMigrations(ctx context.Context) (map[int64]types.SchemaMigration, error) {
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(&types.SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var records []types.SchemaMigration
+	if err := db.Order("version ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[int64]types.SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+func supportsTransactionalDDL(db *gorm.DB) bool {
+	switch db.Dialector.Name() {
+	case "sqlite", "postgres":
+		return true
+	default:
+		return false
+	}
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// splitSQLStatements splits a script into statements terminated by a semicolon at
+// the end of a line. Comment lines are dropped.
+func splitSQLStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(stripSQLComments(script), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSpace(current.String())
+			statements = append(statements, strings.TrimSuffix(statement, ";"))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
+
+func stripSQLComments(script string) string {
+	lines := strings.Split(script, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
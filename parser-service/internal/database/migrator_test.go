@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func writeMigrationFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, *gorm.DB, string) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// Each SQLite :memory: connection is a separate database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	dir := t.TempDir()
+	writeMigrationFile(t, dir, "0001_create_widgets.up.sql", "-- Migration: widgets\nCREATE TABLE widgets (\n  id INTEGER PRIMARY KEY\n);\n")
+	writeMigrationFile(t, dir, "0001_create_widgets.down.sql", "DROP TABLE widgets;\n")
+	writeMigrationFile(t, dir, "0002_add_widget_index.up.sql", "CREATE INDEX IF NOT EXISTS idx_widgets_id ON widgets (id);\n")
+	writeMigrationFile(t, dir, "0002_add_widget_index.down.sql", "DROP INDEX IF EXISTS idx_widgets_id;\n")
+
+	return NewMigrator(db, logrus.New(), dir), db, dir
+}
+
+func TestLoadMigrations_OrdersAndValidates(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrationFile(t, dir, "0002_second.up.sql", "SELECT 2;")
+	writeMigrationFile(t, dir, "0001_first.up.sql", "SELECT 1;")
+	writeMigrationFile(t, dir, "0001_first.down.sql", "SELECT 1;")
+
+	migrations, err := LoadMigrations(dir)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "first", migrations[0].Name)
+	assert.NotEmpty(t, migrations[0].Checksum)
+	assert.Empty(t, migrations[1].DownSQL)
+
+	writeMigrationFile(t, dir, "add_index.sql", "SELECT 3;")
+	_, err = LoadMigrations(dir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid migration file name")
+}
+
+func TestMigrator_UpIsRepeatable(t *testing.T) {
+	migrator, db, _ := newTestMigrator(t)
+	ctx := context.Background()
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+	assert.True(t, db.Migrator().HasTable("widgets"))
+
+	// A second run has nothing to do
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	for _, status := range statuses {
+		assert.True(t, status.Applied)
+		assert.False(t, status.Modified)
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	migrator, db, _ := newTestMigrator(t)
+	ctx := context.Background()
+
+	_, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	rolledBack, err := migrator.Down(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, rolledBack, 2)
+	assert.Equal(t, int64(2), rolledBack[0].Version)
+	assert.Equal(t, int64(1), rolledBack[1].Version)
+	assert.False(t, db.Migrator().HasTable("widgets"))
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.False(t, status.Applied)
+	}
+
+	_, err = migrator.Down(ctx, 0)
+	assert.Error(t, err)
+}
+
+func TestMigrator_DetectsModifiedMigration(t *testing.T) {
+	migrator, _, dir := newTestMigrator(t)
+	ctx := context.Background()
+
+	_, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	writeMigrationFile(t, dir, "0002_add_widget_index.up.sql", "CREATE INDEX IF NOT EXISTS idx_widgets_other ON widgets (id);\n")
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[1].Modified)
+
+	_, err = migrator.Up(ctx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+}
+
+func TestMigrator_FailedMigrationRollsBack(t *testing.T) {
+	migrator, db, dir := newTestMigrator(t)
+	ctx := context.Background()
+
+	writeMigrationFile(t, dir, "0003_broken.up.sql", "CREATE TABLE gadgets (id INTEGER);\nNOT VALID SQL;\n")
+
+	applied, err := migrator.Up(ctx)
+	assert.Error(t, err)
+	assert.Len(t, applied, 2)
+
+	// SQLite supports transactional DDL, so the partial migration is undone
+	assert.False(t, db.Migrator().HasTable("gadgets"))
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.False(t, statuses[2].Applied)
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrationFile(t, dir, "0001_first.up.sql", "SELECT 1;")
+
+	upPath, downPath, err := CreateMigration(dir, "Add Match Index")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0002_add_match_index.up.sql"), upPath)
+	assert.Equal(t, filepath.Join(dir, "0002_add_match_index.down.sql"), downPath)
+	assert.FileExists(t, upPath)
+	assert.FileExists(t, downPath)
+
+	_, _, err = CreateMigration(dir, "  ")
+	assert.Error(t, err)
+}
+
+func TestSplitSQLStatements(t *testing.T) {
+	script := "-- comment\nSET @a := 1;\nSELECT\n  @a;\n\n-- trailing\nSELECT 2"
+	statements := splitSQLStatements(script)
+	assert.Equal(t, []string{"SET @a := 1", "SELECT\n  @a", "SELECT 2"}, statements)
+}
+
+func TestRepositoryMigrationsLoad(t *testing.T) {
+	migrations, err := LoadMigrations(filepath.Join("..", "..", "migrations"))
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, "add_player_tick_index", migrations[0].Name)
+	assert.NotEmpty(t, migrations[0].DownSQL)
+}
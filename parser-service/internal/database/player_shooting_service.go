@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ShootingDataFilter narrows a shooting data query. Zero values are ignored.
+type ShootingDataFilter struct {
+	MatchID     string
+	PlayerID    string
+	RoundNumber int
+	WeaponName  string
+}
+
+// SavePlayerShootingDataBatch saves multiple shooting data records in a batch
+func (s *PlayerTickService) SavePlayerShootingDataBatch(ctx context.Context, data []*types.PlayerShootingData) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := s.db.WithContext(ctx).CreateInBatches(data, 1000).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"batch_size": len(data),
+			"error":      err,
+		}).Error("Failed to save player shooting data batch")
+		return fmt.Errorf("failed to save player shooting data batch: %w", err)
+	}
+
+	return nil
+}
+
+// GetPlayerShootingData retrieves shooting data for a match, ordered as a shot sequence
+func (s *PlayerTickService) GetPlayerShootingData(ctx context.Context, filter ShootingDataFilter) ([]*types.PlayerShootingData, error) {
+	var data []*types.PlayerShootingData
+
+	query := s.db.WithContext(ctx).Where("match_id = ?", filter.MatchID)
+	if filter.PlayerID != "" {
+		query = query.Where("player_id = ?", filter.PlayerID)
+	}
+	if filter.RoundNumber > 0 {
+		query = query.Where("round_number = ?", filter.RoundNumber)
+	}
+	if filter.WeaponName != "" {
+		query = query.Where("weapon_name = ?", filter.WeaponName)
+	}
+
+	if err := query.Order("round_number ASC, tick ASC, player_id ASC").Find(&data).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"match_id":  filter.MatchID,
+			"player_id": filter.PlayerID,
+			"round":     filter.RoundNumber,
+			"weapon":    filter.WeaponName,
+			"error":     err,
+		}).Error("Failed to get player shooting data")
+		return nil, fmt.Errorf("failed to get player shooting data: %w", err)
+	}
+
+	return data, nil
+}
+
+// DeletePlayerShootingDataByMatch deletes all shooting data for a specific match
+func (s *PlayerTickService) DeletePlayerShootingDataByMatch(ctx context.Context, matchID string) error {
+	if err := s.db.WithContext(ctx).
+		Where("match_id = ?", matchID).
+		Delete(&types.PlayerShootingData{}).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"match_id": matchID,
+			"error":    err,
+		}).Error("Failed to delete player shooting data by match")
+		return fmt.Errorf("failed to delete player shooting data by match: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePlayerShootingDataFromRound deletes the shooting data of a match from the
+// given round onwards
+func (s *PlayerTickService) DeletePlayerShootingDataFromRound(ctx context.Context, matchID string, fromRound int) error {
+	if err := s.db.WithContext(ctx).
+		Where("match_id = ? AND round_number >= ?", matchID, fromRound).
+		Delete(&types.PlayerShootingData{}).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"match_id":   matchID,
+			"from_round": fromRound,
+			"error":      err,
+		}).Error("Failed to delete player shooting data from round")
+		return fmt.Errorf("failed to delete player shooting data from round: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePlayerShootingDataOlderThan deletes shooting data created before cutoff and
+// returns the number of rows removed
+func (s *PlayerTickService) DeletePlayerShootingDataOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Where("created_at < ?", cutoff).
+		Delete(&types.PlayerShootingData{})
+	if result.Error != nil {
+		s.logger.WithFields(logrus.Fields{
+			"cutoff": cutoff,
+			"error":  result.Error,
+		}).Error("Failed to delete expired player shooting data")
+		return 0, fmt.Errorf("failed to delete expired player shooting data: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestShootingService(t *testing.T) (*PlayerTickService, *gorm.DB) {
+	// Use SQLite for testing
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&types.PlayerShootingData{})
+	assert.NoError(t, err)
+
+	return NewPlayerTickService(db, logrus.New()), db
+}
+
+func TestPlayerTickService_GetPlayerShootingData_Filters(t *testing.T) {
+	service, _ := newTestShootingService(t)
+	ctx := context.Background()
+
+	shots := []*types.PlayerShootingData{
+		{MatchID: "match-1", RoundNumber: 1, Tick: 120, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle", IsSpraying: true},
+		{MatchID: "match-1", RoundNumber: 1, Tick: 100, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle", IsSpraying: true},
+		{MatchID: "match-1", RoundNumber: 2, Tick: 900, PlayerID: "player-1", WeaponName: "Glock-18", WeaponCategory: "pistol"},
+		{MatchID: "match-1", RoundNumber: 2, Tick: 950, PlayerID: "player-2", WeaponName: "M4A4", WeaponCategory: "rifle"},
+		{MatchID: "match-2", RoundNumber: 1, Tick: 100, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+	}
+	assert.NoError(t, service.SavePlayerShootingDataBatch(ctx, shots))
+
+	all, err := service.GetPlayerShootingData(ctx, ShootingDataFilter{MatchID: "match-1"})
+	assert.NoError(t, err)
+	assert.Len(t, all, 4)
+	assert.Equal(t, int64(100), all[0].Tick, "shots are returned in firing order")
+
+	byPlayer, err := service.GetPlayerShootingData(ctx, ShootingDataFilter{MatchID: "match-1", PlayerID: "player-1"})
+	assert.NoError(t, err)
+	assert.Len(t, byPlayer, 3)
+
+	byRound, err := service.GetPlayerShootingData(ctx, ShootingDataFilter{MatchID: "match-1", RoundNumber: 2})
+	assert.NoError(t, err)
+	assert.Len(t, byRound, 2)
+
+	byWeapon, err := service.GetPlayerShootingData(ctx, ShootingDataFilter{MatchID: "match-1", PlayerID: "player-1", WeaponName: "AK-47"})
+	assert.NoError(t, err)
+	assert.Len(t, byWeapon, 2)
+	assert.True(t, byWeapon[0].IsSpraying)
+}
+
+func TestPlayerTickService_DeletePlayerShootingData(t *testing.T) {
+	service, db := newTestShootingService(t)
+	ctx := context.Background()
+
+	shots := []*types.PlayerShootingData{
+		{MatchID: "match-old", RoundNumber: 1, Tick: 100, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+		{MatchID: "match-new", RoundNumber: 1, Tick: 100, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+	}
+	assert.NoError(t, service.SavePlayerShootingDataBatch(ctx, shots))
+
+	// Age one match beyond the retention window
+	err := db.Model(&types.PlayerShootingData{}).
+		Where("match_id = ?", "match-old").
+		UpdateColumn("created_at", time.Now().Add(-48*time.Hour)).Error
+	assert.NoError(t, err)
+
+	deleted, err := service.DeletePlayerShootingDataOlderThan(ctx, time.Now().Add(-24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	remaining, err := service.GetPlayerShootingData(ctx, ShootingDataFilter{MatchID: "match-new"})
+	assert.NoError(t, err)
+	assert.Len(t, remaining, 1)
+
+	assert.NoError(t, service.DeletePlayerShootingDataByMatch(ctx, "match-new"))
+	remaining, err = service.GetPlayerShootingData(ctx, ShootingDataFilter{MatchID: "match-new"})
+	assert.NoError(t, err)
+	assert.Empty(t, remaining)
+}
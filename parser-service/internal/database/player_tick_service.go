@@ -126,9 +126,254 @@ func (s *PlayerTickService) DeletePlayerTickDataByMatch(ctx context.Context, mat
 		return fmt.Errorf("failed to delete player tick data by match: %w", err)
 	}
 
+	if err := s.db.WithContext(ctx).
+		Where("match_id = ?", matchID).
+		Delete(&types.PlayerTickBlob{}).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"match_id": matchID,
+			"error":    err,
+		}).Error("Failed to delete player tick blobs by match")
+		return fmt.Errorf("failed to delete player tick blobs by match: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePlayerTickDataOlderThan deletes tick rows and blobs created before cutoff and
+// returns the number of records removed
+func (s *PlayerTickService) DeletePlayerTickDataOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	var deleted int64
+	for _, model := range []interface{}{&types.PlayerTickData{}, &types.PlayerTickBlob{}} {
+		result := s.db.WithContext(ctx).
+			Where("created_at < ?", cutoff).
+			Delete(model)
+		if result.Error != nil {
+			s.logger.WithFields(logrus.Fields{
+				"cutoff": cutoff,
+				"error":  result.Error,
+			}).Error("Failed to delete expired player tick data")
+			return deleted, fmt.Errorf("failed to delete expired player tick data: %w", result.Error)
+		}
+		deleted += result.RowsAffected
+	}
+
+	return deleted, nil
+}
+
+// TickSpan is the first and last tick written under a round number
+type TickSpan struct {
+	StartTick int64
+	EndTick   int64
+}
+
+// RenumberPlayerTickData applies a round renumbering to the stored tick data of a
+// match. Blobs of rounds mapped to 0 are deleted and the others take their new number.
+// Rows carry no round number, so the rows inside the dropped spans are deleted instead.
+func (s *PlayerTickService) RenumberPlayerTickData(ctx context.Context, matchID string, mapRound func(int) int, dropped []TickSpan) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rounds []int
+		if err := tx.Model(&types.PlayerTickBlob{}).
+			Where("match_id = ?", matchID).
+			Distinct().
+			Pluck("round_number", &rounds).Error; err != nil {
+			return err
+		}
+
+		// Renumbered rounds go through negative numbers first, so a round that takes
+		// the old number of another is not renumbered twice
+		for _, round := range rounds {
+			newRound := mapRound(round)
+			if newRound == round {
+				continue
+			}
+
+			query := tx.Where("match_id = ? AND round_number = ?", matchID, round)
+			if newRound == 0 {
+				if err := query.Delete(&types.PlayerTickBlob{}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := query.Model(&types.PlayerTickBlob{}).Update("round_number", -newRound).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&types.PlayerTickBlob{}).
+			Where("match_id = ? AND round_number < 0", matchID).
+			Update("round_number", gorm.Expr("-round_number")).Error; err != nil {
+			return err
+		}
+
+		for _, span := range dropped {
+			if err := tx.Where("match_id = ? AND tick >= ? AND tick <= ?", matchID, span.StartTick, span.EndTick).
+				Delete(&types.PlayerTickData{}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"match_id": matchID,
+			"error":    err,
+		}).Error("Failed to renumber player tick data")
+		return fmt.Errorf("failed to renumber player tick data: %w", err)
+	}
+
 	return nil
 }
 
+// MatchDataMove moves the stored tick and shooting data of one demo under the match
+// ID of another, shifting its round numbers by RoundOffset. When LastRound is set,
+// data of later rounds is deleted first; rows carry no round number, so rows after
+// LastTick are deleted instead.
+type MatchDataMove struct {
+	FromMatchID string
+	ToMatchID   string
+	RoundOffset int
+	LastRound   int
+	LastTick    int64
+}
+
+// MoveMatchData applies a MatchDataMove in one transaction. Ticks are left as they
+// were recorded, so reads by round are the way to tell the moved data apart.
+func (s *PlayerTickService) MoveMatchData(ctx context.Context, move MatchDataMove) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if move.LastRound > 0 {
+			if err := tx.Where("match_id = ? AND round_number > ?", move.FromMatchID, move.LastRound).
+				Delete(&types.PlayerShootingData{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("match_id = ? AND round_number > ?", move.FromMatchID, move.LastRound).
+				Delete(&types.PlayerTickBlob{}).Error; err != nil {
+				return err
+			}
+			if move.LastTick > 0 {
+				if err := tx.Where("match_id = ? AND tick > ?", move.FromMatchID, move.LastTick).
+					Delete(&types.PlayerTickData{}).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if move.FromMatchID == move.ToMatchID && move.RoundOffset == 0 {
+			return nil
+		}
+
+		moved := map[string]interface{}{
+			"match_id":     move.ToMatchID,
+			"round_number": gorm.Expr("round_number + ?", move.RoundOffset),
+		}
+		if err := tx.Model(&types.PlayerShootingData{}).
+			Where("match_id = ?", move.FromMatchID).
+			Updates(moved).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&types.PlayerTickBlob{}).
+			Where("match_id = ?", move.FromMatchID).
+			Updates(moved).Error; err != nil {
+			return err
+		}
+		return tx.Model(&types.PlayerTickData{}).
+			Where("match_id = ?", move.FromMatchID).
+			Update("match_id", move.ToMatchID).Error
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"from_match_id": move.FromMatchID,
+			"to_match_id":   move.ToMatchID,
+			"error":         err,
+		}).Error("Failed to move match data")
+		return fmt.Errorf("failed to move match data: %w", err)
+	}
+
+	return nil
+}
+
+// SavePlayerTickBlobs encodes and saves per-player tick runs in the columnar format.
+// Each entry of samplesByPlayer must be ordered by tick and belong to a single player.
+func (s *PlayerTickService) SavePlayerTickBlobs(ctx context.Context, matchID string, roundNumber int, samplesByPlayer map[string][]*types.PlayerTickData) error {
+	blobs := make([]*types.PlayerTickBlob, 0, len(samplesByPlayer))
+
+	for playerID, samples := range samplesByPlayer {
+		if len(samples) == 0 {
+			continue
+		}
+
+		data, err := EncodePlayerTicks(samples)
+		if err != nil {
+			return fmt.Errorf("failed to encode player tick blob: %w", err)
+		}
+
+		blobs = append(blobs, &types.PlayerTickBlob{
+			MatchID:     matchID,
+			RoundNumber: roundNumber,
+			PlayerID:    playerID,
+			Team:        samples[0].Team,
+			StartTick:   samples[0].Tick,
+			EndTick:     samples[len(samples)-1].Tick,
+			TickCount:   len(samples),
+			Data:        data,
+		})
+	}
+
+	if len(blobs) == 0 {
+		return nil
+	}
+
+	if err := s.db.WithContext(ctx).Create(&blobs).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"match_id":   matchID,
+			"round":      roundNumber,
+			"blob_count": len(blobs),
+			"error":      err,
+		}).Error("Failed to save player tick blobs")
+		return fmt.Errorf("failed to save player tick blobs: %w", err)
+	}
+
+	return nil
+}
+
+// GetPlayerTickDataFromBlobs retrieves columnar tick data overlapping a tick range and
+// decodes it back into rows, keeping only ticks within the range
+func (s *PlayerTickService) GetPlayerTickDataFromBlobs(ctx context.Context, matchID string, startTick, endTick int64) ([]*types.PlayerTickData, error) {
+	var blobs []*types.PlayerTickBlob
+
+	if err := s.db.WithContext(ctx).
+		Where("match_id = ? AND start_tick <= ? AND end_tick >= ?", matchID, endTick, startTick).
+		Order("start_tick ASC, player_id ASC").
+		Find(&blobs).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"match_id":   matchID,
+			"start_tick": startTick,
+			"end_tick":   endTick,
+			"error":      err,
+		}).Error("Failed to get player tick blobs by tick range")
+		return nil, fmt.Errorf("failed to get player tick blobs by tick range: %w", err)
+	}
+
+	var data []*types.PlayerTickData
+	for _, blob := range blobs {
+		samples, err := DecodePlayerTicks(blob)
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"match_id":  matchID,
+				"player_id": blob.PlayerID,
+				"blob_id":   blob.ID,
+				"error":     err,
+			}).Error("Failed to decode player tick blob")
+			return nil, fmt.Errorf("failed to decode player tick blob: %w", err)
+		}
+
+		for _, sample := range samples {
+			if sample.Tick >= startTick && sample.Tick <= endTick {
+				data = append(data, sample)
+			}
+		}
+	}
+
+	return data, nil
+}
+
 // GetPlayerTickDataByRound retrieves player tick data for a specific round
 func (s *PlayerTickService) GetPlayerTickDataByRound(ctx context.Context, matchID string, roundStartTick, roundEndTick int64) ([]*types.PlayerTickData, error) {
 	var data []*types.PlayerTickData
@@ -149,6 +394,57 @@ func (s *PlayerTickService) GetPlayerTickDataByRound(ctx context.Context, matchI
 	return data, nil
 }
 
+// TickDataFilter selects stored tick data of a match. An empty PlayerID matches every
+// player and a RoundNumber of 0 every round.
+type TickDataFilter struct {
+	MatchID     string
+	PlayerID    string
+	RoundNumber int
+}
+
+// GetPlayerTickData retrieves the tick data of a match, preferring the columnar blobs
+// and falling back to per-tick rows. Rows carry no round number, so a round can only
+// be selected for matches stored in the columnar format.
+func (s *PlayerTickService) GetPlayerTickData(ctx context.Context, filter TickDataFilter) ([]*types.PlayerTickData, error) {
+	query := s.db.WithContext(ctx).Where("match_id = ?", filter.MatchID)
+	if filter.PlayerID != "" {
+		query = query.Where("player_id = ?", filter.PlayerID)
+	}
+	if filter.RoundNumber > 0 {
+		query = query.Where("round_number = ?", filter.RoundNumber)
+	}
+
+	var blobs []*types.PlayerTickBlob
+	if err := query.Order("start_tick ASC, player_id ASC").Find(&blobs).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"match_id": filter.MatchID,
+			"error":    err,
+		}).Error("Failed to get player tick blobs")
+		return nil, fmt.Errorf("failed to get player tick blobs: %w", err)
+	}
+
+	if len(blobs) == 0 {
+		if filter.RoundNumber > 0 {
+			return nil, nil
+		}
+		if filter.PlayerID != "" {
+			return s.GetPlayerTickDataByPlayer(ctx, filter.MatchID, filter.PlayerID)
+		}
+		return s.GetPlayerTickDataByMatch(ctx, filter.MatchID)
+	}
+
+	var data []*types.PlayerTickData
+	for _, blob := range blobs {
+		samples, err := DecodePlayerTicks(blob)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode player tick blob: %w", err)
+		}
+		data = append(data, samples...)
+	}
+
+	return data, nil
+}
+
 // GetPlayerTickDataStats returns statistics about player tick data for a match
 func (s *PlayerTickService) GetPlayerTickDataStats(ctx context.Context, matchID string) (map[string]interface{}, error) {
 	var stats struct {
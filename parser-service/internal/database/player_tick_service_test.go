@@ -280,7 +280,7 @@ func TestPlayerTickService_DeletePlayerTickDataByMatch(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Auto-migrate the table
-	err = db.AutoMigrate(&types.PlayerTickData{})
+	err = db.AutoMigrate(&types.PlayerTickData{}, &types.PlayerTickBlob{})
 	assert.NoError(t, err)
 
 	logger := logrus.New()
@@ -434,3 +434,230 @@ func TestPlayerTickService_ConcurrentAccess(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int64(10), count)
 }
+
+func TestPlayerTickService_SaveAndGetPlayerTickBlobs(t *testing.T) {
+	// Use SQLite for testing
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	// Auto-migrate the table
+	err = db.AutoMigrate(&types.PlayerTickBlob{})
+	assert.NoError(t, err)
+
+	logger := logrus.New()
+	service := NewPlayerTickService(db, logger)
+
+	matchID := "test-match-123"
+	samplesByPlayer := make(map[string][]*types.PlayerTickData)
+	for _, playerID := range []string{"player-1", "player-2"} {
+		for i := 0; i < 100; i++ {
+			samplesByPlayer[playerID] = append(samplesByPlayer[playerID], &types.PlayerTickData{
+				MatchID:   matchID,
+				PlayerID:  playerID,
+				Tick:      int64(1000 + i*2),
+				Team:      "A",
+				PositionX: float64(100 + i),
+				PositionY: float64(200 + i),
+				PositionZ: 50.0,
+				AimX:      45.5,
+				AimY:      float64(i % 90),
+			})
+		}
+	}
+
+	ctx := context.Background()
+	err = service.SavePlayerTickBlobs(ctx, matchID, 3, samplesByPlayer)
+	assert.NoError(t, err)
+
+	// One row per player instead of one row per sample
+	var count int64
+	err = db.Model(&types.PlayerTickBlob{}).Where("match_id = ?", matchID).Count(&count).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Only ticks inside the requested range are returned
+	data, err := service.GetPlayerTickDataFromBlobs(ctx, matchID, 1010, 1019)
+	assert.NoError(t, err)
+	assert.Len(t, data, 10)
+	for _, sample := range data {
+		assert.GreaterOrEqual(t, sample.Tick, int64(1010))
+		assert.LessOrEqual(t, sample.Tick, int64(1019))
+		assert.Equal(t, "A", sample.Team)
+	}
+
+	// Ranges outside the blob index are skipped
+	data, err = service.GetPlayerTickDataFromBlobs(ctx, matchID, 5000, 6000)
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestPlayerTickService_GetPlayerTickData(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&types.PlayerTickData{}, &types.PlayerTickBlob{}))
+
+	service := NewPlayerTickService(db, logrus.New())
+	ctx := context.Background()
+
+	for round, playerID := range map[int]string{1: "player-1", 2: "player-2"} {
+		samples := map[string][]*types.PlayerTickData{
+			playerID: {
+				{MatchID: "columnar", PlayerID: playerID, Tick: int64(round * 1000), Team: "A"},
+				{MatchID: "columnar", PlayerID: playerID, Tick: int64(round*1000 + 2), Team: "A"},
+			},
+		}
+		assert.NoError(t, service.SavePlayerTickBlobs(ctx, "columnar", round, samples))
+	}
+
+	data, err := service.GetPlayerTickData(ctx, TickDataFilter{MatchID: "columnar"})
+	assert.NoError(t, err)
+	assert.Len(t, data, 4)
+
+	data, err = service.GetPlayerTickData(ctx, TickDataFilter{MatchID: "columnar", RoundNumber: 2})
+	assert.NoError(t, err)
+	assert.Len(t, data, 2)
+	for _, sample := range data {
+		assert.Equal(t, "player-2", sample.PlayerID)
+	}
+
+	// Matches stored as rows are read from the rows
+	assert.NoError(t, service.SavePlayerTickDataBatch(ctx, []*types.PlayerTickData{
+		{MatchID: "rows", PlayerID: "player-1", Tick: 10, Team: "A"},
+		{MatchID: "rows", PlayerID: "player-2", Tick: 10, Team: "B"},
+	}))
+
+	data, err = service.GetPlayerTickData(ctx, TickDataFilter{MatchID: "rows", PlayerID: "player-2"})
+	assert.NoError(t, err)
+	assert.Len(t, data, 1)
+
+	data, err = service.GetPlayerTickData(ctx, TickDataFilter{MatchID: "rows", RoundNumber: 1})
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestTickBlobWriter_Flush(t *testing.T) {
+	// Use SQLite for testing
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	// Auto-migrate the table
+	err = db.AutoMigrate(&types.PlayerTickBlob{})
+	assert.NoError(t, err)
+
+	logger := logrus.New()
+	service := NewPlayerTickService(db, logger)
+	writer := NewTickBlobWriter(service, "test-match-123")
+
+	for round := 1; round <= 2; round++ {
+		for i := 0; i < 5; i++ {
+			writer.Append(round, &types.PlayerTickData{
+				MatchID:  "test-match-123",
+				PlayerID: "player-1",
+				Tick:     int64(round*1000 + i),
+				Team:     "B",
+			})
+		}
+	}
+	assert.Equal(t, 10, writer.BufferedTicks())
+
+	ctx := context.Background()
+	err = writer.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, writer.BufferedTicks())
+
+	var blobs []types.PlayerTickBlob
+	err = db.Order("round_number ASC").Find(&blobs).Error
+	assert.NoError(t, err)
+	assert.Len(t, blobs, 2)
+	assert.Equal(t, 1, blobs[0].RoundNumber)
+	assert.Equal(t, int64(1000), blobs[0].StartTick)
+	assert.Equal(t, int64(1004), blobs[0].EndTick)
+	assert.Equal(t, 5, blobs[1].TickCount)
+
+	// Flushing an empty buffer is a no-op
+	err = writer.Flush(ctx)
+	assert.NoError(t, err)
+}
+
+func TestPlayerTickService_MoveMatchData(t *testing.T) {
+	db := newTestTickDB(t)
+	assert.NoError(t, db.AutoMigrate(&types.PlayerTickData{}, &types.PlayerTickBlob{}))
+	// SQLite index names are global and both tables name theirs idx_match_tick_player
+	assert.NoError(t, db.Migrator().DropIndex(&types.PlayerTickData{}, "idx_match_tick_player"))
+	assert.NoError(t, db.AutoMigrate(&types.PlayerShootingData{}))
+
+	service := NewPlayerTickService(db, logrus.New())
+	ctx := context.Background()
+
+	// The second demo of a match ended with its round 3 in progress, which ran past tick 300
+	for round := 1; round <= 3; round++ {
+		tick := int64(round * 100)
+		samples := map[string][]*types.PlayerTickData{
+			"player-1": {{MatchID: "part-2", PlayerID: "player-1", Tick: tick}},
+		}
+		assert.NoError(t, service.SavePlayerTickBlobs(ctx, "part-2", round, samples))
+		assert.NoError(t, service.SavePlayerTickDataBatch(ctx, []*types.PlayerTickData{{MatchID: "part-2", PlayerID: "player-1", Tick: tick}}))
+		assert.NoError(t, service.SavePlayerShootingDataBatch(ctx, []*types.PlayerShootingData{
+			{MatchID: "part-2", RoundNumber: round, Tick: tick, PlayerID: "player-1", WeaponName: "AK-47", WeaponCategory: "rifle"},
+		}))
+	}
+
+	err := service.MoveMatchData(ctx, MatchDataMove{FromMatchID: "part-2", ToMatchID: "part-1", RoundOffset: 12, LastRound: 2, LastTick: 250})
+	assert.NoError(t, err)
+
+	var left int64
+	assert.NoError(t, db.Model(&types.PlayerTickBlob{}).Where("match_id = ?", "part-2").Count(&left).Error)
+	assert.Zero(t, left)
+
+	var rounds []int
+	assert.NoError(t, db.Model(&types.PlayerTickBlob{}).Where("match_id = ?", "part-1").Order("round_number").Pluck("round_number", &rounds).Error)
+	assert.Equal(t, []int{13, 14}, rounds)
+
+	shots, err := service.GetPlayerShootingData(ctx, ShootingDataFilter{MatchID: "part-1"})
+	assert.NoError(t, err)
+	if assert.Len(t, shots, 2) {
+		assert.Equal(t, 13, shots[0].RoundNumber)
+		assert.Equal(t, 14, shots[1].RoundNumber)
+	}
+
+	rows, err := service.GetPlayerTickDataByMatch(ctx, "part-1")
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, int64(200), rows[1].Tick)
+	}
+}
+
+func TestPlayerTickService_DeletePlayerTickDataOlderThan(t *testing.T) {
+	db := newTestTickDB(t)
+	assert.NoError(t, db.AutoMigrate(&types.PlayerTickData{}, &types.PlayerTickBlob{}))
+
+	service := NewPlayerTickService(db, logrus.New())
+	ctx := context.Background()
+
+	for _, matchID := range []string{"match-old", "match-new"} {
+		assert.NoError(t, service.SavePlayerTickDataBatch(ctx, []*types.PlayerTickData{{MatchID: matchID, PlayerID: "player-1", Tick: 100}}))
+		assert.NoError(t, service.SavePlayerTickBlobs(ctx, matchID, 1, map[string][]*types.PlayerTickData{
+			"player-1": {{MatchID: matchID, PlayerID: "player-1", Tick: 100}},
+		}))
+	}
+
+	// Age one match beyond the retention window
+	for _, model := range []interface{}{&types.PlayerTickData{}, &types.PlayerTickBlob{}} {
+		err := db.Model(model).
+			Where("match_id = ?", "match-old").
+			UpdateColumn("created_at", time.Now().Add(-48*time.Hour)).Error
+		assert.NoError(t, err)
+	}
+
+	deleted, err := service.DeletePlayerTickDataOlderThan(ctx, time.Now().Add(-24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	remaining, err := service.GetPlayerTickData(ctx, TickDataFilter{MatchID: "match-new"})
+	assert.NoError(t, err)
+	assert.Len(t, remaining, 1)
+
+	expired, err := service.GetPlayerTickData(ctx, TickDataFilter{MatchID: "match-old"})
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"parser-service/internal/types"
+)
+
+// TickBlobWriter buffers tick samples per round and player and writes them as
+// columnar blobs, so a whole round costs one insert instead of one row per sample
+type TickBlobWriter struct {
+	service *PlayerTickService
+	matchID string
+
+	// buffers maps: round -> playerID -> samples ordered by tick
+	buffers  map[int]map[string][]*types.PlayerTickData
+	buffered int
+}
+
+// NewTickBlobWriter creates a new tick blob writer for a match
+func NewTickBlobWriter(service *PlayerTickService, matchID string) *TickBlobWriter {
+	return &TickBlobWriter{
+		service: service,
+		matchID: matchID,
+		buffers: make(map[int]map[string][]*types.PlayerTickData),
+	}
+}
+
+// Append buffers a tick sample for the given round
+func (w *TickBlobWriter) Append(roundNumber int, sample *types.PlayerTickData) {
+	players, exists := w.buffers[roundNumber]
+	if !exists {
+		players = make(map[string][]*types.PlayerTickData)
+		w.buffers[roundNumber] = players
+	}
+
+	players[sample.PlayerID] = append(players[sample.PlayerID], sample)
+	w.buffered++
+}
+
+// Flush writes all buffered samples as blobs and resets the buffer. Samples appended
+// for a round after it was flushed end up in an additional blob for that round.
+func (w *TickBlobWriter) Flush(ctx context.Context) error {
+	for roundNumber, players := range w.buffers {
+		if err := w.service.SavePlayerTickBlobs(ctx, w.matchID, roundNumber, players); err != nil {
+			return fmt.Errorf("failed to flush tick blobs for round %d: %w", roundNumber, err)
+		}
+		delete(w.buffers, roundNumber)
+	}
+
+	w.buffered = 0
+	return nil
+}
+
+// Renumber moves buffered samples to their new round numbers and drops the samples of
+// rounds mapped to 0
+func (w *TickBlobWriter) Renumber(mapRound func(int) int) {
+	buffers := make(map[int]map[string][]*types.PlayerTickData, len(w.buffers))
+	w.buffered = 0
+
+	for roundNumber, players := range w.buffers {
+		newRound := mapRound(roundNumber)
+		if newRound == 0 {
+			continue
+		}
+
+		target, exists := buffers[newRound]
+		if !exists {
+			target = make(map[string][]*types.PlayerTickData)
+			buffers[newRound] = target
+		}
+		for playerID, samples := range players {
+			merged := append(target[playerID], samples...)
+			if len(merged) > len(samples) {
+				sort.SliceStable(merged, func(i, j int) bool { return merged[i].Tick < merged[j].Tick })
+			}
+			target[playerID] = merged
+			w.buffered += len(samples)
+		}
+	}
+
+	w.buffers = buffers
+}
+
+// BufferedTicks returns the number of samples waiting to be flushed
+func (w *TickBlobWriter) BufferedTicks() int {
+	return w.buffered
+}
@@ -0,0 +1,159 @@
+package database
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"parser-service/internal/types"
+)
+
+// Columnar tick blob layout (before compression):
+//
+//	version   byte
+//	count     uvarint
+//	ticks     count zigzag varints, delta-encoded against the previous tick
+//	positionX count zigzag varints, quantized and delta-encoded
+//	positionY ...
+//	positionZ ...
+//	aimX      ...
+//	aimY      ...
+//
+// Positions are quantized to 1/100 of a game unit and view angles to 1/1000 of a
+// degree, which is well below the precision any of the analysis code relies on.
+const (
+	tickBlobVersion       byte    = 1
+	tickBlobColumns               = 6 // Every sample takes at least one byte in each column
+	positionQuantizeScale float64 = 100
+	aimQuantizeScale      float64 = 1000
+)
+
+var errTickBlobCorrupt = errors.New("corrupt tick blob")
+
+// EncodePlayerTicks encodes one player's tick samples into a compressed columnar blob.
+// Samples must be ordered by tick.
+func EncodePlayerTicks(samples []*types.PlayerTickData) ([]byte, error) {
+	var raw bytes.Buffer
+	raw.WriteByte(tickBlobVersion)
+
+	scratch := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(scratch, uint64(len(samples)))
+	raw.Write(scratch[:n])
+
+	writeColumn := func(value func(*types.PlayerTickData) int64) {
+		var prev int64
+		for _, sample := range samples {
+			current := value(sample)
+			n := binary.PutVarint(scratch, current-prev)
+			raw.Write(scratch[:n])
+			prev = current
+		}
+	}
+
+	writeColumn(func(s *types.PlayerTickData) int64 { return s.Tick })
+	writeColumn(func(s *types.PlayerTickData) int64 { return quantize(s.PositionX, positionQuantizeScale) })
+	writeColumn(func(s *types.PlayerTickData) int64 { return quantize(s.PositionY, positionQuantizeScale) })
+	writeColumn(func(s *types.PlayerTickData) int64 { return quantize(s.PositionZ, positionQuantizeScale) })
+	writeColumn(func(s *types.PlayerTickData) int64 { return quantize(s.AimX, aimQuantizeScale) })
+	writeColumn(func(s *types.PlayerTickData) int64 { return quantize(s.AimY, aimQuantizeScale) })
+
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	if _, err := writer.Write(raw.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to compress tick blob: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress tick blob: %w", err)
+	}
+
+	return compressed.Bytes(), nil
+}
+
+// DecodePlayerTicks decodes a blob produced by EncodePlayerTicks. MatchID, PlayerID
+// and Team are taken from the blob row since they are not stored per sample.
+func DecodePlayerTicks(blob *types.PlayerTickBlob) ([]*types.PlayerTickData, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(blob.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress tick blob: %w", err)
+	}
+	defer reader.Close()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress tick blob: %w", err)
+	}
+	buffered := bytes.NewReader(raw)
+
+	version, err := buffered.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tick blob version: %w", err)
+	}
+	if version != tickBlobVersion {
+		return nil, fmt.Errorf("unsupported tick blob version %d", version)
+	}
+
+	count, err := binary.ReadUvarint(buffered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tick blob count: %w", errTickBlobCorrupt)
+	}
+	if blob.TickCount > 0 && int(count) != blob.TickCount {
+		return nil, fmt.Errorf("tick blob count mismatch (header %d, row %d): %w", count, blob.TickCount, errTickBlobCorrupt)
+	}
+	// The header is only trusted as far as the blob holds enough bytes for it
+	if count > uint64(buffered.Len()/tickBlobColumns) {
+		return nil, fmt.Errorf("tick blob count %d exceeds its %d data bytes: %w", count, buffered.Len(), errTickBlobCorrupt)
+	}
+
+	samples := make([]*types.PlayerTickData, count)
+	for i := range samples {
+		samples[i] = &types.PlayerTickData{
+			MatchID:  blob.MatchID,
+			PlayerID: blob.PlayerID,
+			Team:     blob.Team,
+		}
+	}
+
+	readColumn := func(assign func(*types.PlayerTickData, int64)) error {
+		var prev int64
+		for _, sample := range samples {
+			delta, err := binary.ReadVarint(buffered)
+			if err != nil {
+				if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+					return errTickBlobCorrupt
+				}
+				return err
+			}
+			prev += delta
+			assign(sample, prev)
+		}
+		return nil
+	}
+
+	columns := []func(*types.PlayerTickData, int64){
+		func(s *types.PlayerTickData, v int64) { s.Tick = v },
+		func(s *types.PlayerTickData, v int64) { s.PositionX = dequantize(v, positionQuantizeScale) },
+		func(s *types.PlayerTickData, v int64) { s.PositionY = dequantize(v, positionQuantizeScale) },
+		func(s *types.PlayerTickData, v int64) { s.PositionZ = dequantize(v, positionQuantizeScale) },
+		func(s *types.PlayerTickData, v int64) { s.AimX = dequantize(v, aimQuantizeScale) },
+		func(s *types.PlayerTickData, v int64) { s.AimY = dequantize(v, aimQuantizeScale) },
+	}
+	for _, assign := range columns {
+		if err := readColumn(assign); err != nil {
+			return nil, fmt.Errorf("failed to decode tick blob column: %w", err)
+		}
+	}
+
+	return samples, nil
+}
+
+func quantize(value, scale float64) int64 {
+	return int64(math.Round(value * scale))
+}
+
+func dequantize(value int64, scale float64) float64 {
+	return float64(value) / scale
+}
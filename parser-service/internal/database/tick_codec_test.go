@@ -0,0 +1,114 @@
+package database
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTickSamples(count int) []*types.PlayerTickData {
+	samples := make([]*types.PlayerTickData, 0, count)
+	for i := 0; i < count; i++ {
+		samples = append(samples, &types.PlayerTickData{
+			MatchID:   "test-match-123",
+			PlayerID:  "76561198000000001",
+			Team:      "A",
+			Tick:      int64(1000 + i*2),
+			PositionX: -1234.56 + float64(i)*1.25,
+			PositionY: 987.65 - float64(i)*0.5,
+			PositionZ: 64.03,
+			AimX:      float64(i%360) + 0.123,
+			AimY:      -12.5 + float64(i%10)*0.01,
+		})
+	}
+	return samples
+}
+
+func TestEncodeDecodePlayerTicks_RoundTrip(t *testing.T) {
+	samples := buildTickSamples(500)
+
+	data, err := EncodePlayerTicks(samples)
+	require.NoError(t, err)
+
+	blob := &types.PlayerTickBlob{
+		MatchID:   "test-match-123",
+		PlayerID:  "76561198000000001",
+		Team:      "A",
+		TickCount: len(samples),
+		Data:      data,
+	}
+
+	decoded, err := DecodePlayerTicks(blob)
+	require.NoError(t, err)
+	require.Len(t, decoded, len(samples))
+
+	for i, sample := range samples {
+		assert.Equal(t, sample.Tick, decoded[i].Tick)
+		assert.Equal(t, sample.MatchID, decoded[i].MatchID)
+		assert.Equal(t, sample.PlayerID, decoded[i].PlayerID)
+		assert.Equal(t, sample.Team, decoded[i].Team)
+		assert.InDelta(t, sample.PositionX, decoded[i].PositionX, 0.005)
+		assert.InDelta(t, sample.PositionY, decoded[i].PositionY, 0.005)
+		assert.InDelta(t, sample.PositionZ, decoded[i].PositionZ, 0.005)
+		assert.InDelta(t, sample.AimX, decoded[i].AimX, 0.0005)
+		assert.InDelta(t, sample.AimY, decoded[i].AimY, 0.0005)
+	}
+}
+
+func TestEncodePlayerTicks_Compact(t *testing.T) {
+	samples := buildTickSamples(3000)
+
+	data, err := EncodePlayerTicks(samples)
+	require.NoError(t, err)
+
+	// A row stores at least 8 numeric columns of 8 bytes each
+	rowBytes := len(samples) * 8 * 8
+	assert.Less(t, len(data)*10, rowBytes)
+}
+
+func TestEncodeDecodePlayerTicks_Empty(t *testing.T) {
+	data, err := EncodePlayerTicks(nil)
+	require.NoError(t, err)
+
+	decoded, err := DecodePlayerTicks(&types.PlayerTickBlob{Data: data})
+	require.NoError(t, err)
+	assert.Empty(t, decoded)
+}
+
+func TestDecodePlayerTicks_Corrupt(t *testing.T) {
+	_, err := DecodePlayerTicks(&types.PlayerTickBlob{Data: []byte("not a blob")})
+	assert.Error(t, err)
+
+	data, err := EncodePlayerTicks(buildTickSamples(10))
+	require.NoError(t, err)
+
+	_, err = DecodePlayerTicks(&types.PlayerTickBlob{Data: data, TickCount: 11})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "count mismatch")
+}
+
+func TestDecodePlayerTicks_CorruptCount(t *testing.T) {
+	// A valid blob with its header count rewritten to an absurd value
+	var raw bytes.Buffer
+	raw.WriteByte(tickBlobVersion)
+	scratch := make([]byte, binary.MaxVarintLen64)
+	raw.Write(scratch[:binary.PutUvarint(scratch, math.MaxUint32)])
+	raw.Write([]byte{0, 0, 0, 0, 0, 0})
+
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	_, err := writer.Write(raw.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	_, err = DecodePlayerTicks(&types.PlayerTickBlob{Data: compressed.Bytes()})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errTickBlobCorrupt)
+}
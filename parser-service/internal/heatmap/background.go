@@ -0,0 +1,49 @@
+package heatmap
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"parser-service/internal/radar"
+)
+
+// LoadRadarImage reads the radar image of a map level from dir. The default level
+// is <map>.png and other levels are <map>_<level name>.png, e.g. de_nuke_lower.png;
+// .jpg files are read too. It returns nil without an error when there is no image.
+func LoadRadarImage(dir string, overview radar.Overview, level int) (image.Image, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	name := strings.ToLower(overview.Map)
+	if level > 0 && level <= len(overview.Levels) {
+		name += "_" + strings.ToLower(overview.Levels[level-1].Name)
+	}
+
+	for _, extension := range []string{".png", ".jpg", ".jpeg"} {
+		path := filepath.Join(dir, name+extension)
+		file, err := os.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open radar image %s: %w", path, err)
+		}
+
+		img, _, err := image.Decode(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode radar image %s: %w", path, err)
+		}
+		return img, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,193 @@
+// Package heatmap renders positions as heatmaps over a map's radar image. It uses
+// only the standard image packages so it runs on headless servers.
+package heatmap
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"parser-service/internal/radar"
+	"parser-service/internal/types"
+)
+
+// Size limits of a rendered heatmap in pixels
+const (
+	DefaultSize = 1024
+	MinSize     = 64
+	MaxSize     = 2048
+)
+
+// backgroundColor fills the image when there is no radar image for the map
+var backgroundColor = color.RGBA{R: 0x1e, G: 0x1e, B: 0x1e, A: 0xff}
+
+// gradient maps the heat of a pixel, from cold to hot, to a color
+var gradient = []color.RGBA{
+	{R: 0x00, G: 0x00, B: 0xff, A: 0xff},
+	{R: 0x00, G: 0xff, B: 0xff, A: 0xff},
+	{R: 0x00, G: 0xff, B: 0x00, A: 0xff},
+	{R: 0xff, G: 0xff, B: 0x00, A: 0xff},
+	{R: 0xff, G: 0x00, B: 0x00, A: 0xff},
+}
+
+// Options controls how a heatmap is drawn
+type Options struct {
+	Size       int         // Width and height in pixels
+	Radius     float64     // How far a single position spreads, in pixels at DefaultSize
+	Level      int         // Map level to draw, positions on other levels are left out
+	Background image.Image // Radar image of the level, scaled to Size; nil draws a plain background
+}
+
+// DefaultOptions returns the options used when a caller sets none
+func DefaultOptions() Options {
+	return Options{
+		Size:   DefaultSize,
+		Radius: 16,
+	}
+}
+
+// Render draws positions as a heatmap over the map's radar image
+func Render(overview radar.Overview, positions []types.Position, options Options) *image.RGBA {
+	size := options.Size
+	if size < MinSize {
+		size = MinSize
+	}
+	if size > MaxSize {
+		size = MaxSize
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	drawBackground(img, options.Background)
+
+	// Count positions per pixel, then spread the counts with a blur. This keeps the
+	// cost independent of the number of positions, which runs into the millions
+	// for tick data.
+	heat := make([]float64, size*size)
+	for _, position := range positions {
+		projected := overview.Project(position)
+		if projected.Level != options.Level {
+			continue
+		}
+		x := int(projected.X * float64(size))
+		y := int(projected.Y * float64(size))
+		if x < 0 || y < 0 || x >= size || y >= size {
+			continue
+		}
+		heat[y*size+x]++
+	}
+
+	radius := options.Radius
+	if radius <= 0 {
+		radius = DefaultOptions().Radius
+	}
+	blur(heat, size, radius*float64(size)/DefaultSize)
+
+	peak := 0.0
+	for _, value := range heat {
+		peak = math.Max(peak, value)
+	}
+	if peak == 0 {
+		return img
+	}
+
+	for i, value := range heat {
+		intensity := value / peak
+		if intensity < 0.01 {
+			continue
+		}
+		x, y := i%size, i/size
+		img.SetRGBA(x, y, blend(img.RGBAAt(x, y), heatColor(intensity), heatAlpha(intensity)))
+	}
+
+	return img
+}
+
+// drawBackground scales the radar image onto img, or fills it when there is none
+func drawBackground(img *image.RGBA, background image.Image) {
+	size := img.Bounds().Dx()
+	if background == nil || background.Bounds().Empty() {
+		for i := 0; i < len(img.Pix); i += 4 {
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = backgroundColor.R, backgroundColor.G, backgroundColor.B, backgroundColor.A
+		}
+		return
+	}
+
+	// Nearest neighbour scaling; radar images are drawn at the size they are served in
+	bounds := background.Bounds()
+	for y := 0; y < size; y++ {
+		sourceY := bounds.Min.Y + y*bounds.Dy()/size
+		for x := 0; x < size; x++ {
+			sourceX := bounds.Min.X + x*bounds.Dx()/size
+			r, g, b, _ := background.At(sourceX, sourceY).RGBA()
+			img.SetRGBA(x, y, color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 0xff})
+		}
+	}
+}
+
+// blur spreads every value of a size by size grid with a gaussian of the given
+// radius, as two one dimensional passes
+func blur(values []float64, size int, radius float64) {
+	sigma := math.Max(radius/2, 0.5)
+	reach := int(math.Ceil(radius))
+	kernel := make([]float64, 2*reach+1)
+	for i := range kernel {
+		d := float64(i - reach)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+	}
+
+	pass := func(at func(line, i int) int) {
+		buffer := make([]float64, size)
+		for line := 0; line < size; line++ {
+			for i := range buffer {
+				buffer[i] = 0
+			}
+			for i := 0; i < size; i++ {
+				value := values[at(line, i)]
+				if value == 0 {
+					continue
+				}
+				for k, weight := range kernel {
+					j := i + k - reach
+					if j >= 0 && j < size {
+						buffer[j] += value * weight
+					}
+				}
+			}
+			for i, value := range buffer {
+				values[at(line, i)] = value
+			}
+		}
+	}
+
+	pass(func(row, i int) int { return row*size + i })
+	pass(func(column, i int) int { return i*size + column })
+}
+
+// heatColor interpolates the gradient at an intensity between 0 and 1
+func heatColor(intensity float64) color.RGBA {
+	position := intensity * float64(len(gradient)-1)
+	index := int(position)
+	if index >= len(gradient)-1 {
+		return gradient[len(gradient)-1]
+	}
+
+	fraction := position - float64(index)
+	from, to := gradient[index], gradient[index+1]
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*fraction)
+	}
+	return color.RGBA{R: mix(from.R, to.R), G: mix(from.G, to.G), B: mix(from.B, to.B), A: 0xff}
+}
+
+// heatAlpha fades cold areas out so the radar stays readable underneath
+func heatAlpha(intensity float64) float64 {
+	return math.Min(0.35+intensity*0.5, 0.85)
+}
+
+// blend draws over on top of base with the given opacity
+func blend(base, over color.RGBA, alpha float64) color.RGBA {
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a)*(1-alpha) + float64(b)*alpha)
+	}
+	return color.RGBA{R: mix(base.R, over.R), G: mix(base.G, over.G), B: mix(base.B, over.B), A: 0xff}
+}
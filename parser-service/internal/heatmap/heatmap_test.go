@@ -0,0 +1,143 @@
+package heatmap
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"parser-service/internal/radar"
+	"parser-service/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOverview maps world coordinates 0 to 1024 onto the radar image one to one
+var testOverview = radar.Overview{Map: "de_test", PosX: 0, PosY: 1024, Scale: 1}
+
+func TestRender_DrawsHeatAroundPositions(t *testing.T) {
+	positions := []types.Position{
+		{X: 256, Y: 768},
+		{X: 256, Y: 768},
+		{X: 768, Y: 256},
+		{X: 5000, Y: 5000}, // Off the radar
+	}
+
+	img := Render(testOverview, positions, Options{Size: 256, Radius: 16})
+
+	require.Equal(t, image.Rect(0, 0, 256, 256), img.Bounds())
+	assert.Equal(t, backgroundColor, img.RGBAAt(200, 10), "pixels away from any position keep the background")
+
+	hottest := img.RGBAAt(64, 64)
+	warm := img.RGBAAt(192, 192)
+	assert.NotEqual(t, backgroundColor, hottest)
+	assert.NotEqual(t, backgroundColor, warm)
+	assert.Greater(t, hottest.R, warm.R, "the position seen twice is drawn hotter")
+}
+
+func TestRender_SkipsOtherLevels(t *testing.T) {
+	lowerMax := -100.0
+	overview := testOverview
+	overview.Levels = []radar.Level{{Name: "lower", AltitudeMax: &lowerMax}}
+
+	positions := []types.Position{{X: 512, Y: 512, Z: -500}}
+
+	upper := Render(overview, positions, Options{Size: 128})
+	assert.Equal(t, backgroundColor, upper.RGBAAt(64, 64))
+
+	lower := Render(overview, positions, Options{Size: 128, Level: 1})
+	assert.NotEqual(t, backgroundColor, lower.RGBAAt(64, 64))
+}
+
+func TestRender_ScalesBackground(t *testing.T) {
+	background := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	background.Set(0, 0, color.RGBA{R: 0xff, A: 0xff})
+	background.Set(1, 1, color.RGBA{B: 0xff, A: 0xff})
+
+	img := Render(testOverview, nil, Options{Size: MinSize, Background: background})
+
+	assert.Equal(t, color.RGBA{R: 0xff, A: 0xff}, img.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{B: 0xff, A: 0xff}, img.RGBAAt(MinSize-1, MinSize-1))
+}
+
+func TestPositions(t *testing.T) {
+	final := types.Position{X: 3}
+	data := &types.ParsedDemoData{
+		GunfightEvents: []types.GunfightEvent{
+			{RoundNumber: 1, Player1SteamID: "1", Player2SteamID: "2", Player1Position: types.Position{X: 1}, Player2Position: types.Position{X: 2}},
+			{RoundNumber: 2, Player1SteamID: "2", Player2SteamID: "1", Player1Position: types.Position{X: 4}, Player2Position: types.Position{X: 5}},
+		},
+		GrenadeEvents: []types.GrenadeEvent{
+			{RoundNumber: 1, PlayerSteamID: "1", GrenadeFinalPosition: &final},
+			{RoundNumber: 1, PlayerSteamID: "1"},
+		},
+		RoundReplays: []types.RoundReplay{{
+			RoundNumber: 2,
+			Players: []types.ReplayTrack{
+				{PlayerSteamID: "1", Frames: []types.ReplayFrame{{Position: types.Position{X: 6}}, {Position: types.Position{X: 7}}}},
+				{PlayerSteamID: "2", Frames: []types.ReplayFrame{{Position: types.Position{X: 8}}}},
+			},
+		}},
+	}
+
+	xs := func(filter Filter) []float64 {
+		positions, err := Positions(data, filter)
+		require.NoError(t, err)
+		result := make([]float64, 0, len(positions))
+		for _, position := range positions {
+			result = append(result, position.X)
+		}
+		return result
+	}
+
+	assert.Equal(t, []float64{5}, xs(Filter{Layer: LayerDeaths, PlayerID: "1"}))
+	assert.Equal(t, []float64{1, 4}, xs(Filter{Layer: LayerKills}))
+	assert.Equal(t, []float64{4}, xs(Filter{Layer: LayerKills, RoundNumber: 2}))
+	assert.Equal(t, []float64{3}, xs(Filter{Layer: LayerGrenades}))
+	assert.Equal(t, []float64{6, 7}, xs(Filter{Layer: LayerDensity, PlayerID: "1"}))
+
+	_, err := Positions(data, Filter{Layer: "footsteps"})
+	assert.Error(t, err)
+}
+
+func TestPoints(t *testing.T) {
+	data := &types.ParsedDemoData{
+		Match: types.Match{MatchID: "match-1"},
+		GunfightEvents: []types.GunfightEvent{
+			{RoundNumber: 3, Player1SteamID: "1", Player2SteamID: "2", Player1Position: types.Position{X: 1, Y: 2, Z: 3}},
+		},
+	}
+
+	points, err := Points(data, LayerKills)
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	assert.Equal(t, types.HeatmapPoint{MatchID: "match-1", Layer: LayerKills, RoundNumber: 3, PlayerID: "1", PositionX: 1, PositionY: 2, PositionZ: 3}, *points[0])
+	assert.Equal(t, []types.Position{{X: 1, Y: 2, Z: 3}}, PointPositions(points))
+}
+
+func TestLoadRadarImage(t *testing.T) {
+	dir := t.TempDir()
+	lowerMax := -100.0
+	overview := radar.Overview{Map: "de_Test", Levels: []radar.Level{{Name: "lower", AltitudeMax: &lowerMax}}}
+
+	file, err := os.Create(filepath.Join(dir, "de_test_lower.png"))
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(file, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+	require.NoError(t, file.Close())
+
+	img, err := LoadRadarImage(dir, overview, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, img, "a missing image is not an error")
+
+	img, err = LoadRadarImage(dir, overview, 1)
+	require.NoError(t, err)
+	require.NotNil(t, img)
+	assert.Equal(t, 4, img.Bounds().Dx())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "de_test.png"), []byte("not a png"), 0o644))
+	_, err = LoadRadarImage(dir, overview, 0)
+	assert.Error(t, err)
+}
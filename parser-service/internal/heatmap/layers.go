@@ -0,0 +1,127 @@
+package heatmap
+
+import (
+	"fmt"
+
+	"parser-service/internal/types"
+)
+
+// Heatmap layers
+const (
+	LayerDeaths   = "deaths"   // Where players died
+	LayerKills    = "kills"    // Where players stood when they got a kill
+	LayerDensity  = "density"  // Where players spent their time, from tick data
+	LayerGrenades = "grenades" // Where grenades landed
+)
+
+// Filter selects the positions of a layer. An empty PlayerID matches every player
+// and a RoundNumber of 0 every round.
+type Filter struct {
+	Layer       string
+	PlayerID    string
+	RoundNumber int
+}
+
+// ValidLayer reports whether layer is one of the heatmap layers
+func ValidLayer(layer string) bool {
+	switch layer {
+	case LayerDeaths, LayerKills, LayerDensity, LayerGrenades:
+		return true
+	default:
+		return false
+	}
+}
+
+// EventLayers are the layers read from the events of a match, which are stored per
+// match. The density layer is read from the stored tick data instead.
+var EventLayers = []string{LayerDeaths, LayerKills, LayerGrenades}
+
+// Points returns the points of a layer of a parsed demo with the player and round
+// each belongs to. The density layer is read from the round replays, so it is empty
+// unless replays were built.
+func Points(data *types.ParsedDemoData, layer string) ([]*types.HeatmapPoint, error) {
+	points := make([]*types.HeatmapPoint, 0)
+	add := func(round int, playerID string, position types.Position) {
+		points = append(points, &types.HeatmapPoint{
+			MatchID:     data.Match.MatchID,
+			Layer:       layer,
+			RoundNumber: round,
+			PlayerID:    playerID,
+			PositionX:   position.X,
+			PositionY:   position.Y,
+			PositionZ:   position.Z,
+		})
+	}
+
+	switch layer {
+	case LayerDeaths:
+		for _, gunfight := range data.GunfightEvents {
+			add(gunfight.RoundNumber, gunfight.Player2SteamID, gunfight.Player2Position)
+		}
+
+	case LayerKills:
+		for _, gunfight := range data.GunfightEvents {
+			add(gunfight.RoundNumber, gunfight.Player1SteamID, gunfight.Player1Position)
+		}
+
+	case LayerGrenades:
+		for _, grenade := range data.GrenadeEvents {
+			if grenade.GrenadeFinalPosition != nil {
+				add(grenade.RoundNumber, grenade.PlayerSteamID, *grenade.GrenadeFinalPosition)
+			}
+		}
+
+	case LayerDensity:
+		for _, replay := range data.RoundReplays {
+			for _, track := range replay.Players {
+				for _, frame := range track.Frames {
+					add(replay.RoundNumber, track.PlayerSteamID, frame.Position)
+				}
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown heatmap layer %q", layer)
+	}
+
+	return points, nil
+}
+
+// Positions returns the positions of a layer of a parsed demo that match the filter
+func Positions(data *types.ParsedDemoData, filter Filter) ([]types.Position, error) {
+	points, err := Points(data, filter.Layer)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := points[:0]
+	for _, point := range points {
+		if (filter.RoundNumber == 0 || point.RoundNumber == filter.RoundNumber) &&
+			(filter.PlayerID == "" || point.PlayerID == filter.PlayerID) {
+			selected = append(selected, point)
+		}
+	}
+	return PointPositions(selected), nil
+}
+
+// PointPositions returns the positions of heatmap points
+func PointPositions(points []*types.HeatmapPoint) []types.Position {
+	positions := make([]types.Position, 0, len(points))
+	for _, point := range points {
+		positions = append(positions, types.Position{X: point.PositionX, Y: point.PositionY, Z: point.PositionZ})
+	}
+	return positions
+}
+
+// TickPositions returns the positions of stored tick data, limited to one player
+// when playerID is set
+func TickPositions(samples []*types.PlayerTickData, playerID string) []types.Position {
+	positions := make([]types.Position, 0, len(samples))
+	for _, sample := range samples {
+		if sample == nil || (playerID != "" && sample.PlayerID != playerID) {
+			continue
+		}
+		positions = append(positions, types.Position{X: sample.PositionX, Y: sample.PositionY, Z: sample.PositionZ})
+	}
+	return positions
+}
@@ -136,11 +136,7 @@ func calculateFraggerScore(pme types.PlayerMatchEvent) float64 {
 		return 0
 	}
 
-	// Calculate number of rounds played (estimate from deaths + 1)
-	roundsPlayed := float64(pme.Deaths + 1)
-	if roundsPlayed == 0 {
-		roundsPlayed = 1
-	}
+	roundsPlayed := max(float64(pme.RoundsPlayed), 1)
 
 	scores := []struct {
 		score  float64
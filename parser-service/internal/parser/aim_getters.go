@@ -12,6 +12,11 @@ func (ep *EventProcessor) GetAimEvents() []types.AimAnalysisResult {
 	return aggregated
 }
 
+// GetRoundAimEvents returns the collected aim tracking events per player and round
+func (ep *EventProcessor) GetRoundAimEvents() []types.AimAnalysisResult {
+	return ep.aimEvents
+}
+
 // aggregateAimEvents aggregates per-round aim data into match-level data
 func (ep *EventProcessor) aggregateAimEvents(roundEvents []types.AimAnalysisResult) []types.AimAnalysisResult {
 	// Group by player
@@ -92,6 +97,12 @@ func (ep *EventProcessor) GetAimWeaponEvents() []types.WeaponAimAnalysisResult {
 	return aggregated
 }
 
+// GetRoundAimWeaponEvents returns the collected weapon-specific aim tracking events
+// per player, weapon and round
+func (ep *EventProcessor) GetRoundAimWeaponEvents() []types.WeaponAimAnalysisResult {
+	return ep.aimWeaponEvents
+}
+
 // aggregateWeaponAimEvents aggregates per-round weapon aim data into match-level data
 func (ep *EventProcessor) aggregateWeaponAimEvents(roundEvents []types.WeaponAimAnalysisResult) []types.WeaponAimAnalysisResult {
 	// Debug: Log input
@@ -15,6 +15,10 @@ type AimTrackingHandler struct {
 	shootingData []types.PlayerShootingData
 }
 
+// SprayWindowSeconds is the longest gap between shots of one spray
+// (600 RPM = 10 shots/sec, so 0.2 seconds covers one missed shot)
+const SprayWindowSeconds = 0.2
+
 // NewAimTrackingHandler creates a new aim tracking handler
 func NewAimTrackingHandler(processor *EventProcessor, logger *logrus.Logger) *AimTrackingHandler {
 	return &AimTrackingHandler{
@@ -138,8 +142,8 @@ func (ath *AimTrackingHandler) analyzeWeaponSprayPattern(shots []*types.PlayerSh
 	}
 
 	// Detect spray sequences using a sliding window approach
-	sprayWindowTicks := int64(13) // ~0.2 seconds at 64 tick rate (600 RPM = 10 shots/sec = 6.4 ticks/shot)
-	minSprayShots := 2            // Lower minimum for better detection
+	sprayWindowTicks := ath.processor.Timing().SecondsToTicks(SprayWindowSeconds)
+	minSprayShots := 2 // Lower minimum for better detection
 
 	for i := 0; i < len(shots); i++ {
 		sprayShots := []*types.PlayerShootingData{shots[i]}
@@ -167,6 +171,30 @@ func (ath *AimTrackingHandler) GetShootingData() []types.PlayerShootingData {
 	return ath.shootingData
 }
 
+// GetShootingDataForRound returns copies of the shots fired in a round
+func (ath *AimTrackingHandler) GetShootingDataForRound(roundNumber int) []*types.PlayerShootingData {
+	var roundShots []*types.PlayerShootingData
+	for _, shot := range ath.shootingData {
+		if shot.RoundNumber == roundNumber {
+			shotCopy := shot
+			roundShots = append(roundShots, &shotCopy)
+		}
+	}
+	return roundShots
+}
+
+// RenumberShootingData rewrites the round number of every shot. mapRound returns
+// the new number of a round, or 0 to drop its shots.
+func (ath *AimTrackingHandler) RenumberShootingData(mapRound func(int) int) {
+	shootingData := ath.shootingData[:0]
+	for _, shot := range ath.shootingData {
+		if shot.RoundNumber = mapRound(shot.RoundNumber); shot.RoundNumber > 0 {
+			shootingData = append(shootingData, shot)
+		}
+	}
+	ath.shootingData = shootingData
+}
+
 // ClearShootingData clears the shooting data for a new round
 func (ath *AimTrackingHandler) ClearShootingData() {
 	ath.shootingData = make([]types.PlayerShootingData, 0)
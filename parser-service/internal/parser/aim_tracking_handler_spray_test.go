@@ -215,3 +215,45 @@ func TestAnalyzeWeaponSprayPattern_InsufficientShots(t *testing.T) {
 		}
 	}
 }
+
+func TestAnalyzeWeaponSprayPattern_UsesDemoTickRate(t *testing.T) {
+	newShots := func() []*types.PlayerShootingData {
+		// 20 ticks apart is ~0.16s at 128 tick but ~0.31s at 64 tick
+		shots := make([]*types.PlayerShootingData, 0, 4)
+		for i := int64(0); i < 4; i++ {
+			shots = append(shots, &types.PlayerShootingData{
+				PlayerID:   "player1",
+				WeaponName: "ak47",
+				Tick:       1000 + i*20,
+			})
+		}
+		return shots
+	}
+
+	countSpraying := func(shots []*types.PlayerShootingData) int {
+		count := 0
+		for _, shot := range shots {
+			if shot.IsSpraying {
+				count++
+			}
+		}
+		return count
+	}
+
+	logger := logrus.New()
+	processor := NewEventProcessor(&types.MatchState{CurrentRound: 1, Players: make(map[string]*types.Player)}, logger, nil, nil)
+	handler := NewAimTrackingHandler(processor, logger)
+
+	shots := newShots()
+	handler.analyzeWeaponSprayPattern(shots)
+	if got := countSpraying(shots); got != 0 {
+		t.Errorf("Expected no spraying shots at 64 tick, got %d", got)
+	}
+
+	processor.SetTickRate(128)
+	shots = newShots()
+	handler.analyzeWeaponSprayPattern(shots)
+	if got := countSpraying(shots); got != 4 {
+		t.Errorf("Expected 4 spraying shots at 128 tick, got %d", got)
+	}
+}
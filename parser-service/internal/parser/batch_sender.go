@@ -129,10 +129,19 @@ func (bs *BatchSender) SendGunfightEvents(ctx context.Context, jobID string, com
 				"player_2_x":               event.Player2Position.X,
 				"player_2_y":               event.Player2Position.Y,
 				"player_2_z":               event.Player2Position.Z,
+				"player_1_place":           event.Player1Place,
+				"player_2_place":           event.Player2Place,
 				"distance":                 event.Distance,
 				"headshot":                 event.Headshot,
 				"wallbang":                 event.Wallbang,
 				"penetrated_objects":       event.PenetratedObjects,
+				"through_smoke":            event.ThroughSmoke,
+				"no_scope":                 event.NoScope,
+				"attacker_blind":           event.AttackerBlind,
+				"attacker_in_air":          event.AttackerInAir,
+				"victim_blind_duration":    event.VictimBlindDuration,
+				"assisted_flash":           event.AssistedFlash,
+				"distance_bucket":          event.DistanceBucket,
 				"victor_steam_id":          event.VictorSteamID,
 				"damage_dealt":             event.DamageDealt,
 				"is_first_kill":            event.IsFirstKill,
@@ -148,6 +157,8 @@ func (bs *BatchSender) SendGunfightEvents(ctx context.Context, jobID string, com
 				"assister_impact":        event.AssisterImpact,
 				"flash_assister_impact":  event.FlashAssisterImpact,
 			}
+			addRadarFields(flatEvents[j], "player_1_", event.Player1Position)
+			addRadarFields(flatEvents[j], "player_2_", event.Player2Position)
 		}
 
 		payload := map[string]interface{}{
@@ -223,6 +234,7 @@ func (bs *BatchSender) SendGrenadeEvents(ctx context.Context, jobID string, comp
 				"player_x":             event.PlayerPosition.X,
 				"player_y":             event.PlayerPosition.Y,
 				"player_z":             event.PlayerPosition.Z,
+				"player_place":         event.PlayerPlace,
 				"player_aim_x":         event.PlayerAim.X,
 				"player_aim_y":         event.PlayerAim.Y,
 				"player_aim_z":         event.PlayerAim.Z,
@@ -235,6 +247,8 @@ func (bs *BatchSender) SendGrenadeEvents(ctx context.Context, jobID string, comp
 				flatEvent["grenade_final_x"] = event.GrenadeFinalPosition.X
 				flatEvent["grenade_final_y"] = event.GrenadeFinalPosition.Y
 				flatEvent["grenade_final_z"] = event.GrenadeFinalPosition.Z
+				flatEvent["grenade_final_place"] = event.GrenadeFinalPlace
+				addRadarFields(flatEvent, "grenade_final_", *event.GrenadeFinalPosition)
 			}
 			if event.FlashDuration != nil {
 				flatEvent["flash_duration"] = *event.FlashDuration
@@ -250,6 +264,17 @@ func (bs *BatchSender) SendGrenadeEvents(ctx context.Context, jobID string, comp
 			flatEvent["flash_leads_to_kill"] = event.FlashLeadsToKill
 			flatEvent["flash_leads_to_death"] = event.FlashLeadsToDeath
 			flatEvent["smoke_blocking_duration"] = event.SmokeBlockingDuration
+			flatEvent["burn_duration_seconds"] = event.BurnDurationSeconds
+			flatEvent["fire_area_covered"] = event.FireAreaCovered
+			flatEvent["enemies_displaced"] = event.EnemiesDisplaced
+			flatEvent["kills_during_burn"] = event.KillsDuringBurn
+			flatEvent["extinguished_by_smoke"] = event.ExtinguishedBySmoke
+			flatEvent["explosion_tick"] = event.ExplosionTick
+			flatEvent["bounce_count"] = event.BounceCount
+			flatEvent["airtime_seconds"] = event.AirtimeSeconds
+			if len(event.Trajectory) > 0 {
+				flatEvent["trajectory"] = event.Trajectory
+			}
 
 			// Log smoke grenade events with blocking duration
 			if event.GrenadeType == "Smoke Grenade" {
@@ -258,6 +283,7 @@ func (bs *BatchSender) SendGrenadeEvents(ctx context.Context, jobID string, comp
 			if len(event.AffectedPlayers) > 0 {
 				flatEvent["affected_players"] = event.AffectedPlayers
 			}
+			addRadarFields(flatEvent, "player_", event.PlayerPosition)
 
 			flatEvents[j] = flatEvent
 		}
@@ -324,6 +350,8 @@ func (bs *BatchSender) SendDamageEvents(ctx context.Context, jobID string, compl
 				"tick_timestamp":    event.TickTimestamp,
 				"attacker_steam_id": event.AttackerSteamID,
 				"victim_steam_id":   event.VictimSteamID,
+				"attacker_place":    event.AttackerPlace,
+				"victim_place":      event.VictimPlace,
 				"damage":            event.Damage,
 				"armor_damage":      event.ArmorDamage,
 				"health_damage":     event.HealthDamage,
@@ -585,6 +613,7 @@ func (bs *BatchSender) SendPlayerMatchEvents(ctx context.Context, jobID string,
 		for j, event := range batch {
 			flatEvent := map[string]interface{}{
 				"player_steam_id":               event.PlayerSteamID,
+				"rounds_played":                 event.RoundsPlayed,
 				"kills":                         event.Kills,
 				"assists":                       event.Assists,
 				"deaths":                        event.Deaths,
@@ -859,6 +888,484 @@ func (bs *BatchSender) SendAchievements(ctx context.Context, jobID string, compl
 	return nil
 }
 
+func (bs *BatchSender) SendBombEvents(ctx context.Context, jobID string, completionURL string, events []types.BombEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Extract base URL from completion URL
+	baseURL, err := bs.extractBaseURL(completionURL)
+	if err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send bomb events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("completion_url", completionURL)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+	bs.baseURL = baseURL
+
+	flatEvents := make([]map[string]interface{}, len(events))
+	for i, event := range events {
+		flatEvent := map[string]interface{}{
+			"round_number":   event.RoundNumber,
+			"round_time":     event.RoundTime,
+			"tick_timestamp": event.TickTimestamp,
+			"event_type":     event.EventType,
+			"site":           event.Site,
+			"place":          event.Place,
+			"x":              event.Position.X,
+			"y":              event.Position.Y,
+			"z":              event.Position.Z,
+			"has_kit":        event.HasKit,
+			"time_remaining": event.TimeRemaining,
+		}
+
+		if event.PlayerSteamID != nil {
+			flatEvent["player_steam_id"] = *event.PlayerSteamID
+			flatEvent["player_side"] = event.PlayerSide
+		}
+		addRadarFields(flatEvent, "", event.Position)
+
+		flatEvents[i] = flatEvent
+	}
+
+	payload := map[string]interface{}{
+		"data": flatEvents,
+	}
+
+	url := bs.baseURL + fmt.Sprintf(api.JobEventEndpoint, jobID, api.EventTypeBomb)
+	if err := bs.sendRequestWithRetry(ctx, url, payload); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send bomb events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("url", url)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+
+	return nil
+}
+
+func (bs *BatchSender) SendTeamEconomyEvents(ctx context.Context, jobID string, completionURL string, events []types.TeamEconomyEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Extract base URL from completion URL
+	baseURL, err := bs.extractBaseURL(completionURL)
+	if err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send team economy events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("completion_url", completionURL)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+	bs.baseURL = baseURL
+
+	flatEvents := make([]map[string]interface{}, len(events))
+	for i, event := range events {
+		flatEvents[i] = map[string]interface{}{
+			"round_number":               event.RoundNumber,
+			"team":                       event.Team,
+			"side":                       event.Side,
+			"players":                    event.Players,
+			"start_money":                event.StartMoney,
+			"money_spent":                event.MoneySpent,
+			"saved_equipment_value":      event.SavedEquipmentValue,
+			"freeze_end_equipment_value": event.FreezeEndEquipmentValue,
+			"loss_bonus_level":           event.LossBonusLevel,
+			"loss_bonus":                 event.LossBonus,
+			"buy_type":                   event.BuyType,
+		}
+	}
+
+	payload := map[string]interface{}{
+		"data": flatEvents,
+	}
+
+	url := bs.baseURL + fmt.Sprintf(api.JobEventEndpoint, jobID, api.EventTypeTeamEconomy)
+	if err := bs.sendRequestWithRetry(ctx, url, payload); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send team economy events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("url", url)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+
+	return nil
+}
+
+func (bs *BatchSender) SendItemEvents(ctx context.Context, jobID string, completionURL string, events []types.ItemEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Extract base URL from completion URL
+	baseURL, err := bs.extractBaseURL(completionURL)
+	if err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send item events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("completion_url", completionURL)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+	bs.baseURL = baseURL
+
+	flatEvents := make([]map[string]interface{}, len(events))
+	for i, event := range events {
+		flatEvent := map[string]interface{}{
+			"round_number":    event.RoundNumber,
+			"round_time":      event.RoundTime,
+			"tick_timestamp":  event.TickTimestamp,
+			"event_type":      event.EventType,
+			"player_steam_id": event.PlayerSteamID,
+			"player_side":     event.PlayerSide,
+			"item":            event.Item,
+			"price":           event.Price,
+			"x":               event.Position.X,
+			"y":               event.Position.Y,
+			"z":               event.Position.Z,
+		}
+
+		if event.RecipientSteamID != nil {
+			flatEvent["recipient_steam_id"] = *event.RecipientSteamID
+		}
+		if event.DroppedBySteamID != nil {
+			flatEvent["dropped_by_steam_id"] = *event.DroppedBySteamID
+		}
+		addRadarFields(flatEvent, "", event.Position)
+
+		flatEvents[i] = flatEvent
+	}
+
+	payload := map[string]interface{}{
+		"data": flatEvents,
+	}
+
+	url := bs.baseURL + fmt.Sprintf(api.JobEventEndpoint, jobID, api.EventTypeItem)
+	if err := bs.sendRequestWithRetry(ctx, url, payload); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send item events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("url", url)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+
+	return nil
+}
+
+func (bs *BatchSender) SendLoadoutEvents(ctx context.Context, jobID string, completionURL string, events []types.LoadoutEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Extract base URL from completion URL
+	baseURL, err := bs.extractBaseURL(completionURL)
+	if err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send loadout events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("completion_url", completionURL)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+	bs.baseURL = baseURL
+
+	flatEvents := make([]map[string]interface{}, len(events))
+	for i, event := range events {
+		flatEvents[i] = map[string]interface{}{
+			"round_number":    event.RoundNumber,
+			"tick_timestamp":  event.TickTimestamp,
+			"player_steam_id": event.PlayerSteamID,
+			"player_side":     event.PlayerSide,
+			"primary":         event.Primary,
+			"secondary":       event.Secondary,
+			"grenades":        event.Grenades,
+			"grenade_value":   event.GrenadeValue,
+			"armor":           event.Armor,
+			"has_helmet":      event.HasHelmet,
+			"has_defuse_kit":  event.HasDefuseKit,
+			"has_zeus":        event.HasZeus,
+			"equipment_value": event.EquipmentValue,
+			"money_left":      event.MoneyLeft,
+		}
+	}
+
+	payload := map[string]interface{}{
+		"data": flatEvents,
+	}
+
+	url := bs.baseURL + fmt.Sprintf(api.JobEventEndpoint, jobID, api.EventTypeLoadout)
+	if err := bs.sendRequestWithRetry(ctx, url, payload); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send loadout events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("url", url)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+
+	return nil
+}
+
+func (bs *BatchSender) SendTradeEvents(ctx context.Context, jobID string, completionURL string, events []types.TradeEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Extract base URL from completion URL
+	baseURL, err := bs.extractBaseURL(completionURL)
+	if err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send trade events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("completion_url", completionURL)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+	bs.baseURL = baseURL
+
+	flatEvents := make([]map[string]interface{}, len(events))
+	for i, event := range events {
+		flatEvents[i] = map[string]interface{}{
+			"round_number":           event.RoundNumber,
+			"kill_tick":              event.KillTick,
+			"traded_player_steam_id": event.TradedPlayerSteamID,
+			"traded_player_side":     event.TradedPlayerSide,
+			"killer_steam_id":        event.KillerSteamID,
+			"opportunity_steam_ids":  event.OpportunitySteamIDs,
+			"traded":                 event.Traded,
+			"trader_steam_id":        event.TraderSteamID,
+			"trade_tick":             event.TradeTick,
+			"time_gap_seconds":       event.TimeGapSeconds,
+		}
+	}
+
+	payload := map[string]interface{}{
+		"data": flatEvents,
+	}
+
+	url := bs.baseURL + fmt.Sprintf(api.JobEventEndpoint, jobID, api.EventTypeTrade)
+	if err := bs.sendRequestWithRetry(ctx, url, payload); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send trade events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("url", url)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+
+	return nil
+}
+
+// SendRoundReplays sends one request per round, since a replay holds every frame of
+// the round and is far larger than any other event
+func (bs *BatchSender) SendRoundReplays(ctx context.Context, jobID string, completionURL string, replays []types.RoundReplay) error {
+	if len(replays) == 0 {
+		return nil
+	}
+
+	// Extract base URL from completion URL
+	baseURL, err := bs.extractBaseURL(completionURL)
+	if err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send round replays", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("completion_url", completionURL)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+	bs.baseURL = baseURL
+
+	url := bs.baseURL + fmt.Sprintf(api.JobEventEndpoint, jobID, api.EventTypeRoundReplay)
+	for _, replay := range replays {
+		payload := map[string]interface{}{
+			"job_id": jobID,
+			"data":   replay,
+		}
+
+		if err := bs.sendRequestWithRetry(ctx, url, payload); err != nil {
+			parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send round replay", err)
+			parseError = parseError.WithContext("job_id", jobID)
+			parseError = parseError.WithContext("url", url)
+			parseError = parseError.WithContext("round", replay.RoundNumber)
+			bs.progressManager.ReportParseError(parseError)
+			return parseError
+		}
+	}
+
+	return nil
+}
+
+func (bs *BatchSender) SendPauseEvents(ctx context.Context, jobID string, completionURL string, events []types.PauseEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Extract base URL from completion URL
+	baseURL, err := bs.extractBaseURL(completionURL)
+	if err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send pause events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("completion_url", completionURL)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+	bs.baseURL = baseURL
+
+	flatEvents := make([]map[string]interface{}, len(events))
+	for i, event := range events {
+		flatEvent := map[string]interface{}{
+			"round_number": event.RoundNumber,
+			"pause_type":   event.PauseType,
+			"start_tick":   event.StartTick,
+			"end_tick":     event.EndTick,
+			"duration":     event.Duration,
+		}
+
+		if event.Side != nil {
+			flatEvent["side"] = *event.Side
+		}
+		if event.Team != nil {
+			flatEvent["team"] = *event.Team
+		}
+
+		flatEvents[i] = flatEvent
+	}
+
+	payload := map[string]interface{}{
+		"data": flatEvents,
+	}
+
+	url := bs.baseURL + fmt.Sprintf(api.JobEventEndpoint, jobID, api.EventTypePause)
+	if err := bs.sendRequestWithRetry(ctx, url, payload); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send pause events", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("url", url)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+
+	return nil
+}
+
+func (bs *BatchSender) SendSubstitutions(ctx context.Context, jobID string, completionURL string, substitutions []types.Substitution) error {
+	if len(substitutions) == 0 {
+		return nil
+	}
+
+	// Extract base URL from completion URL
+	baseURL, err := bs.extractBaseURL(completionURL)
+	if err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send substitutions", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("completion_url", completionURL)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+	bs.baseURL = baseURL
+
+	flatEvents := make([]map[string]interface{}, len(substitutions))
+	for i, substitution := range substitutions {
+		flatEvents[i] = map[string]interface{}{
+			"team":                  substitution.Team,
+			"player_out_steam_id":   substitution.PlayerOutSteamID,
+			"player_in_steam_id":    substitution.PlayerInSteamID,
+			"round_number":          substitution.RoundNumber,
+			"player_out_start_tick": substitution.PlayerOutStartTick,
+			"player_out_end_tick":   substitution.PlayerOutEndTick,
+			"player_in_start_tick":  substitution.PlayerInStartTick,
+			"player_in_end_tick":    substitution.PlayerInEndTick,
+		}
+	}
+
+	payload := map[string]interface{}{
+		"data": flatEvents,
+	}
+
+	url := bs.baseURL + fmt.Sprintf(api.JobEventEndpoint, jobID, api.EventTypeSubstitution)
+	if err := bs.sendRequestWithRetry(ctx, url, payload); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send substitutions", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("url", url)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+
+	return nil
+}
+
+// SendSeriesSummary sends the series totals. mapJobIDs holds the job each map's
+// data was sent under, in the same order as series.Maps.
+func (bs *BatchSender) SendSeriesSummary(ctx context.Context, jobID string, completionURL string, series *types.SeriesResult, mapJobIDs []string) error {
+	// Extract base URL from completion URL
+	baseURL, err := bs.extractBaseURL(completionURL)
+	if err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send series summary", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("completion_url", completionURL)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+	bs.baseURL = baseURL
+
+	maps := make([]map[string]interface{}, len(series.Maps))
+	for i, data := range series.Maps {
+		mapJobID := ""
+		if i < len(mapJobIDs) {
+			mapJobID = mapJobIDs[i]
+		}
+
+		maps[i] = map[string]interface{}{
+			"map_number":         i + 1,
+			"job_id":             mapJobID,
+			"match_id":           data.Match.MatchID,
+			"map":                data.Match.Map,
+			"winning_team":       data.Match.WinningTeam,
+			"winning_team_score": data.Match.WinningTeamScore,
+			"losing_team_score":  data.Match.LosingTeamScore,
+			"total_rounds":       data.Match.TotalRounds,
+			"partial":            data.Partial,
+		}
+	}
+
+	players := make([]map[string]interface{}, len(series.Players))
+	for i, player := range series.Players {
+		players[i] = map[string]interface{}{
+			"player_steam_id": player.PlayerSteamID,
+			"name":            player.Name,
+			"team":            player.Team,
+			"maps_played":     player.MapsPlayed,
+			"rounds_played":   player.RoundsPlayed,
+			"kills":           player.Kills,
+			"assists":         player.Assists,
+			"deaths":          player.Deaths,
+			"damage":          player.Damage,
+			"adr":             player.ADR,
+			"headshots":       player.Headshots,
+			"first_kills":     player.FirstKills,
+			"first_deaths":    player.FirstDeaths,
+			"total_impact":    player.TotalImpact,
+			"average_impact":  player.AverageImpact,
+		}
+	}
+
+	payload := map[string]interface{}{
+		"data": map[string]interface{}{
+			"team_a_map_wins": series.TeamAMapWins,
+			"team_b_map_wins": series.TeamBMapWins,
+			"winning_team":    series.WinningTeam,
+			"maps":            maps,
+			"players":         players,
+		},
+	}
+
+	url := bs.baseURL + fmt.Sprintf(api.JobEventEndpoint, jobID, api.EventTypeSeries)
+	if err := bs.sendRequestWithRetry(ctx, url, payload); err != nil {
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeNetwork, types.ErrorSeverityError, "failed to send series summary", err)
+		parseError = parseError.WithContext("job_id", jobID)
+		parseError = parseError.WithContext("url", url)
+		bs.progressManager.ReportParseError(parseError)
+		return parseError
+	}
+
+	return nil
+}
+
 func (bs *BatchSender) SendCompletion(ctx context.Context, jobID string, completionURL string) error {
 	// Sending completion signal
 
@@ -990,3 +1497,15 @@ func (bs *BatchSender) sendRequestWithRetry(ctx context.Context, url string, pay
 
 	return parseError
 }
+
+// addRadarFields adds the radar coordinates of a position, when it has them, next to
+// its world coordinates
+func addRadarFields(flatEvent map[string]interface{}, prefix string, position types.Position) {
+	if position.Radar == nil {
+		return
+	}
+
+	flatEvent[prefix+"radar_x"] = position.Radar.X
+	flatEvent[prefix+"radar_y"] = position.Radar.Y
+	flatEvent[prefix+"level"] = position.Radar.Level
+}
@@ -500,6 +500,393 @@ func TestBatchSender_SendRoundEvents(t *testing.T) {
 	}
 }
 
+func TestBatchSender_SendPauseEvents(t *testing.T) {
+	var received map[string][]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/event/pause") {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": true}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Batch: config.BatchConfig{
+			HTTPTimeout:   30 * time.Second,
+			RetryAttempts: 1,
+			RetryDelay:    time.Millisecond,
+		},
+	}
+	sender := NewBatchSender(cfg, logrus.New(), createTestProgressManager())
+
+	events := []types.PauseEvent{
+		{RoundNumber: 4, PauseType: types.PauseTypeTacticalTimeout, Side: stringPtr("CT"), Team: stringPtr("A"), StartTick: 100, EndTick: 2020, Duration: 30},
+		{RoundNumber: 9, PauseType: types.PauseTypeTechnical, StartTick: 5000, EndTick: 5640, Duration: 10},
+	}
+
+	if err := sender.SendPauseEvents(context.Background(), "test-job-123", server.URL, events); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(received["data"]) != 2 {
+		t.Fatalf("Expected 2 pause events, got %d", len(received["data"]))
+	}
+	if received["data"][0]["team"] != "A" || received["data"][0]["pause_type"] != types.PauseTypeTacticalTimeout {
+		t.Errorf("Unexpected tactical timeout payload: %v", received["data"][0])
+	}
+	if _, hasSide := received["data"][1]["side"]; hasSide {
+		t.Errorf("Technical pause should not have a side: %v", received["data"][1])
+	}
+}
+
+func TestBatchSender_SendBombEvents(t *testing.T) {
+	var received map[string][]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/event/bomb") {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": true}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Batch: config.BatchConfig{
+			HTTPTimeout:   30 * time.Second,
+			RetryAttempts: 1,
+			RetryDelay:    time.Millisecond,
+		},
+	}
+	sender := NewBatchSender(cfg, logrus.New(), createTestProgressManager())
+
+	bombEvents := []types.BombEvent{
+		{RoundNumber: 4, EventType: types.BombEventPlanted, PlayerSteamID: stringPtr("1"), PlayerSide: "T", Site: "A", TimeRemaining: 40,
+			Position: types.Position{X: 100, Y: 200, Radar: &types.RadarPosition{X: 0.25, Y: 0.5, Level: 1}}},
+		{RoundNumber: 4, EventType: types.BombEventExploded, Site: "A"},
+	}
+
+	if err := sender.SendBombEvents(context.Background(), "test-job-123", server.URL, bombEvents); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(received["data"]) != 2 {
+		t.Fatalf("Expected 2 bomb events, got %d", len(received["data"]))
+	}
+	if received["data"][0]["player_steam_id"] != "1" || received["data"][0]["time_remaining"] != float64(40) {
+		t.Errorf("Unexpected bomb event payload: %v", received["data"][0])
+	}
+	if _, exists := received["data"][1]["player_steam_id"]; exists {
+		t.Errorf("Expected no player on the explosion, got %v", received["data"][1])
+	}
+	if received["data"][0]["radar_x"] != 0.25 || received["data"][0]["radar_y"] != 0.5 || received["data"][0]["level"] != float64(1) {
+		t.Errorf("Expected radar coordinates on the plant, got %v", received["data"][0])
+	}
+	if _, exists := received["data"][1]["radar_x"]; exists {
+		t.Errorf("Expected no radar coordinates without an overview, got %v", received["data"][1])
+	}
+}
+
+func TestBatchSender_SendTeamEconomyEvents(t *testing.T) {
+	var received map[string][]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/event/team-economy") {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": true}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Batch: config.BatchConfig{
+			HTTPTimeout:   30 * time.Second,
+			RetryAttempts: 1,
+			RetryDelay:    time.Millisecond,
+		},
+	}
+	sender := NewBatchSender(cfg, logrus.New(), createTestProgressManager())
+
+	economyEvents := []types.TeamEconomyEvent{
+		{RoundNumber: 2, Team: "A", Side: "CT", Players: 5, StartMoney: 12000, MoneySpent: 3000, LossBonusLevel: 1, LossBonus: 1900, BuyType: types.TeamBuyTypeEco},
+	}
+
+	if err := sender.SendTeamEconomyEvents(context.Background(), "test-job-123", server.URL, economyEvents); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(received["data"]) != 1 {
+		t.Fatalf("Expected 1 team economy event, got %d", len(received["data"]))
+	}
+	if received["data"][0]["buy_type"] != types.TeamBuyTypeEco || received["data"][0]["loss_bonus"] != float64(1900) {
+		t.Errorf("Unexpected team economy payload: %v", received["data"][0])
+	}
+}
+
+func TestBatchSender_SendItemEvents(t *testing.T) {
+	var received map[string][]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/event/item") {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": true}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Batch: config.BatchConfig{
+			HTTPTimeout:   30 * time.Second,
+			RetryAttempts: 1,
+			RetryDelay:    time.Millisecond,
+		},
+	}
+	sender := NewBatchSender(cfg, logrus.New(), createTestProgressManager())
+
+	itemEvents := []types.ItemEvent{
+		{RoundNumber: 2, EventType: types.ItemEventDrop, PlayerSteamID: "1", PlayerSide: "T", Item: "AK-47", Price: 2700, RecipientSteamID: stringPtr("2")},
+		{RoundNumber: 2, EventType: types.ItemEventPickup, PlayerSteamID: "2", PlayerSide: "T", Item: "AK-47", Price: 2700, DroppedBySteamID: stringPtr("1")},
+	}
+
+	if err := sender.SendItemEvents(context.Background(), "test-job-123", server.URL, itemEvents); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(received["data"]) != 2 {
+		t.Fatalf("Expected 2 item events, got %d", len(received["data"]))
+	}
+	if received["data"][0]["recipient_steam_id"] != "2" || received["data"][1]["dropped_by_steam_id"] != "1" {
+		t.Errorf("Unexpected item event payload: %v", received["data"])
+	}
+}
+
+func TestBatchSender_SendLoadoutEvents(t *testing.T) {
+	var received map[string][]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/event/loadout") {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": true}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Batch: config.BatchConfig{
+			HTTPTimeout:   30 * time.Second,
+			RetryAttempts: 1,
+			RetryDelay:    time.Millisecond,
+		},
+	}
+	sender := NewBatchSender(cfg, logrus.New(), createTestProgressManager())
+
+	loadoutEvents := []types.LoadoutEvent{
+		{RoundNumber: 3, PlayerSteamID: "1", PlayerSide: "CT", Primary: "M4A4", Grenades: map[string]int{"Flashbang": 2}, HasDefuseKit: true, MoneyLeft: 450},
+	}
+
+	if err := sender.SendLoadoutEvents(context.Background(), "test-job-123", server.URL, loadoutEvents); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(received["data"]) != 1 {
+		t.Fatalf("Expected 1 loadout event, got %d", len(received["data"]))
+	}
+	grenades, ok := received["data"][0]["grenades"].(map[string]interface{})
+	if !ok || grenades["Flashbang"] != float64(2) || received["data"][0]["has_defuse_kit"] != true {
+		t.Errorf("Unexpected loadout payload: %v", received["data"][0])
+	}
+}
+
+func TestBatchSender_SendTradeEvents(t *testing.T) {
+	var received map[string][]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/event/trade") {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": true}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Batch: config.BatchConfig{
+			HTTPTimeout:   30 * time.Second,
+			RetryAttempts: 1,
+			RetryDelay:    time.Millisecond,
+		},
+	}
+	sender := NewBatchSender(cfg, logrus.New(), createTestProgressManager())
+
+	trader := "3"
+	tradeTick := int64(1128)
+	timeGap := 2.0
+	tradeEvents := []types.TradeEvent{
+		{RoundNumber: 4, KillTick: 1000, TradedPlayerSteamID: "1", TradedPlayerSide: "CT", KillerSteamID: "2", OpportunitySteamIDs: []string{"3"}, Traded: true, TraderSteamID: &trader, TradeTick: &tradeTick, TimeGapSeconds: &timeGap},
+		{RoundNumber: 4, KillTick: 2000, TradedPlayerSteamID: "3", TradedPlayerSide: "CT", KillerSteamID: "4", OpportunitySteamIDs: []string{"5"}},
+	}
+
+	if err := sender.SendTradeEvents(context.Background(), "test-job-123", server.URL, tradeEvents); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(received["data"]) != 2 {
+		t.Fatalf("Expected 2 trade events, got %d", len(received["data"]))
+	}
+	if received["data"][0]["trader_steam_id"] != "3" || received["data"][0]["time_gap_seconds"] != 2.0 {
+		t.Errorf("Unexpected trade payload: %v", received["data"][0])
+	}
+	if received["data"][1]["traded"] != false || received["data"][1]["trader_steam_id"] != nil {
+		t.Errorf("Expected an untraded death without a trader, got %v", received["data"][1])
+	}
+}
+
+func TestBatchSender_SendRoundReplays(t *testing.T) {
+	var rounds []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/event/round-replay") {
+			var received struct {
+				Data types.RoundReplay `json:"data"`
+			}
+			json.NewDecoder(r.Body).Decode(&received)
+			rounds = append(rounds, received.Data.RoundNumber)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": true}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Batch: config.BatchConfig{
+			HTTPTimeout:   30 * time.Second,
+			RetryAttempts: 1,
+			RetryDelay:    time.Millisecond,
+		},
+	}
+	sender := NewBatchSender(cfg, logrus.New(), createTestProgressManager())
+
+	replays := []types.RoundReplay{
+		{RoundNumber: 1, Players: []types.ReplayTrack{{PlayerSteamID: "1", Frames: []types.ReplayFrame{{Tick: 100, Health: 100}}}}},
+		{RoundNumber: 2},
+	}
+
+	if err := sender.SendRoundReplays(context.Background(), "test-job-123", server.URL, replays); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(rounds) != 2 || rounds[0] != 1 || rounds[1] != 2 {
+		t.Errorf("Expected one request per round, got rounds %v", rounds)
+	}
+}
+
+func TestBatchSender_SendSubstitutions(t *testing.T) {
+	var received map[string][]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/event/substitution") {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": true}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Batch: config.BatchConfig{
+			HTTPTimeout:   30 * time.Second,
+			RetryAttempts: 1,
+			RetryDelay:    time.Millisecond,
+		},
+	}
+	sender := NewBatchSender(cfg, logrus.New(), createTestProgressManager())
+
+	substitutions := []types.Substitution{
+		{Team: "B", PlayerOutSteamID: "1", PlayerInSteamID: "2", RoundNumber: 7, PlayerOutStartTick: 100, PlayerOutEndTick: 40000, PlayerInStartTick: 41000, PlayerInEndTick: 90000},
+	}
+
+	if err := sender.SendSubstitutions(context.Background(), "test-job-123", server.URL, substitutions); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(received["data"]) != 1 {
+		t.Fatalf("Expected 1 substitution, got %d", len(received["data"]))
+	}
+	if received["data"][0]["player_in_steam_id"] != "2" || received["data"][0]["round_number"] != float64(7) {
+		t.Errorf("Unexpected substitution payload: %v", received["data"][0])
+	}
+}
+
+func TestBatchSender_SendSeriesSummary(t *testing.T) {
+	var received map[string]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/event/series") {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": true}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Batch: config.BatchConfig{
+			HTTPTimeout:   30 * time.Second,
+			RetryAttempts: 1,
+			RetryDelay:    time.Millisecond,
+		},
+	}
+	sender := NewBatchSender(cfg, logrus.New(), createTestProgressManager())
+
+	series := &types.SeriesResult{
+		Maps: []*types.ParsedDemoData{
+			{Match: types.Match{Map: "de_inferno", WinningTeam: "A", WinningTeamScore: 13, LosingTeamScore: 9}},
+			{Match: types.Match{Map: "de_nuke", WinningTeam: "A", WinningTeamScore: 13, LosingTeamScore: 4}},
+		},
+		TeamAMapWins: 2,
+		WinningTeam:  "A",
+		Players: []types.SeriesPlayerSummary{
+			{PlayerSteamID: "1", Team: "A", MapsPlayed: 2, Kills: 40},
+		},
+	}
+
+	if err := sender.SendSeriesSummary(context.Background(), "test-job-123", server.URL, series, []string{"test-job-123-map-1", "test-job-123-map-2"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data := received["data"]
+	if data["winning_team"] != "A" || data["team_a_map_wins"] != float64(2) {
+		t.Errorf("Unexpected series payload: %v", data)
+	}
+
+	maps, ok := data["maps"].([]interface{})
+	if !ok || len(maps) != 2 {
+		t.Fatalf("Expected 2 maps, got %v", data["maps"])
+	}
+	if maps[1].(map[string]interface{})["job_id"] != "test-job-123-map-2" {
+		t.Errorf("Unexpected map payload: %v", maps[1])
+	}
+
+	players, ok := data["players"].([]interface{})
+	if !ok || len(players) != 1 || players[0].(map[string]interface{})["kills"] != float64(40) {
+		t.Errorf("Unexpected players payload: %v", data["players"])
+	}
+}
+
 func TestBatchSender_SendCompletion(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"math"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/sirupsen/logrus"
+)
+
+// BombHandler records everything that happens to the bomb
+type BombHandler struct {
+	processor *EventProcessor
+	logger    *logrus.Logger
+
+	plantSite     string         // Site of the latest plant attempt
+	plantPosition types.Position // Where the bomb was planted
+	plantPlace    string         // Callout name of the plant position
+	defuseHasKit  bool           // Whether the latest defuse attempt used a kit
+}
+
+// NewBombHandler creates a new bomb handler
+func NewBombHandler(processor *EventProcessor, logger *logrus.Logger) *BombHandler {
+	return &BombHandler{
+		processor: processor,
+		logger:    logger,
+	}
+}
+
+// HandleBombPlantBegin handles the start of a plant
+func (bh *BombHandler) HandleBombPlantBegin(e events.BombPlantBegin) error {
+	bh.plantSite = bombSiteString(e.Site)
+	bh.recordBombEvent(types.BombEventPlantStart, e.Player, bh.plantSite, bh.processor.getPlayerPosition(e.Player), false)
+	return nil
+}
+
+// HandleBombPlantAborted handles a plant being cancelled
+func (bh *BombHandler) HandleBombPlantAborted(e events.BombPlantAborted) error {
+	bh.recordBombEvent(types.BombEventPlantAbort, e.Player, bh.plantSite, bh.processor.getPlayerPosition(e.Player), false)
+	return nil
+}
+
+// HandleBombPlanted handles the bomb being planted
+func (bh *BombHandler) HandleBombPlanted(e events.BombPlanted) error {
+	if site := bombSiteString(e.Site); site != "" {
+		bh.plantSite = site
+	}
+	bh.plantPosition = bh.processor.getPlayerPosition(e.Player)
+	bh.plantPlace = bh.processor.getPlayerPlace(e.Player)
+	bh.recordBombEvent(types.BombEventPlanted, e.Player, bh.plantSite, bh.plantPosition, false)
+	return nil
+}
+
+// HandleBombDefuseStart handles the start of a defuse
+func (bh *BombHandler) HandleBombDefuseStart(e events.BombDefuseStart) error {
+	bh.defuseHasKit = e.HasKit
+	bh.recordBombEvent(types.BombEventDefuseStart, e.Player, bh.plantedSite(), bh.processor.getPlayerPosition(e.Player), e.HasKit)
+	return nil
+}
+
+// HandleBombDefuseAborted handles a defuse being cancelled
+func (bh *BombHandler) HandleBombDefuseAborted(e events.BombDefuseAborted) error {
+	bh.recordBombEvent(types.BombEventDefuseAbort, e.Player, bh.plantedSite(), bh.processor.getPlayerPosition(e.Player), bh.defuseHasKit)
+	return nil
+}
+
+// HandleBombDefused handles the bomb being defused
+func (bh *BombHandler) HandleBombDefused(e events.BombDefused) error {
+	site := bombSiteString(e.Site)
+	if site == "" {
+		site = bh.plantedSite()
+	}
+	bh.recordBombEvent(types.BombEventDefused, e.Player, site, bh.processor.getPlayerPosition(e.Player), bh.defuseHasKit)
+	return nil
+}
+
+// HandleBombExplode handles the bomb exploding
+func (bh *BombHandler) HandleBombExplode(e events.BombExplode) error {
+	site := bombSiteString(e.Site)
+	if site == "" {
+		site = bh.plantedSite()
+	}
+	bh.recordBombEvent(types.BombEventExploded, nil, site, bh.plantPosition, false)
+	return nil
+}
+
+// HandleBombDropped handles the bomb being dropped to the ground
+func (bh *BombHandler) HandleBombDropped(e events.BombDropped) error {
+	bh.recordBombEvent(types.BombEventDropped, e.Player, "", bh.processor.getPlayerPosition(e.Player), false)
+	return nil
+}
+
+// HandleBombPickup handles the bomb being picked up
+func (bh *BombHandler) HandleBombPickup(e events.BombPickup) error {
+	bh.recordBombEvent(types.BombEventPickedUp, e.Player, "", bh.processor.getPlayerPosition(e.Player), false)
+	return nil
+}
+
+// recordBombEvent stores a bomb event of the round in progress. What a bot does is
+// credited to the human controlling it.
+func (bh *BombHandler) recordBombEvent(eventType string, player *common.Player, site string, position types.Position, hasKit bool) {
+	ep := bh.processor
+	if ep.matchState == nil {
+		return
+	}
+
+	event := types.BombEvent{
+		RoundNumber:   ep.matchState.CurrentRound,
+		RoundTime:     ep.getCurrentRoundTime(),
+		TickTimestamp: ep.currentTick,
+		EventType:     eventType,
+		Site:          site,
+		Position:      position,
+		Place:         ep.getPlayerPlace(player),
+		HasKit:        hasKit,
+		TimeRemaining: bh.timeRemaining(),
+	}
+	if eventType == types.BombEventExploded {
+		event.Place = bh.plantPlace
+	}
+
+	if actor := ep.actingPlayer(player); actor != nil {
+		steamID := types.SteamIDToString(actor.SteamID64)
+		event.PlayerSteamID = &steamID
+		event.PlayerSide = ep.getTeamString(actor.Team)
+	}
+
+	ep.matchState.BombEvents = append(ep.matchState.BombEvents, event)
+
+	bh.logger.WithFields(logrus.Fields{
+		"round":          event.RoundNumber,
+		"event_type":     eventType,
+		"site":           site,
+		"time_remaining": event.TimeRemaining,
+	}).Debug("Bomb event")
+}
+
+// plantedSite returns the site the bomb was planted at this round
+func (bh *BombHandler) plantedSite() string {
+	if site := bh.processor.matchState.RoundBombSite; site != "" {
+		return site
+	}
+	return bh.plantSite
+}
+
+// timeRemaining returns the seconds left on the bomb timer once the bomb is planted,
+// or on the round clock before that
+func (bh *BombHandler) timeRemaining() float64 {
+	ep := bh.processor
+	ms := ep.matchState
+
+	var remaining float64
+	switch {
+	case ms.RoundBombPlantTick > 0:
+		remaining = bh.bombTime() - ep.timing.TicksToSeconds(ep.currentTick-ms.RoundBombPlantTick)
+	case ms.RoundFreezeEndTick > 0:
+		remaining = bh.roundTime() - ep.timing.TicksToSeconds(ep.currentTick-ms.RoundFreezeEndTick)
+	default:
+		// The round clock does not run during freeze time
+		remaining = bh.roundTime()
+	}
+
+	return math.Max(remaining, 0)
+}
+
+// roundTime returns the round length in seconds, excluding freeze time
+func (bh *BombHandler) roundTime() float64 {
+	if ep := bh.processor; ep.demoParser != nil {
+		if gameState := ep.demoParser.GameState(); gameState != nil {
+			if roundTime, err := gameState.Rules().RoundTime(); err == nil && roundTime > 0 {
+				return roundTime.Seconds()
+			}
+		}
+	}
+	return types.CS2RoundTime
+}
+
+// bombTime returns the bomb timer in seconds
+func (bh *BombHandler) bombTime() float64 {
+	if ep := bh.processor; ep.demoParser != nil {
+		if gameState := ep.demoParser.GameState(); gameState != nil {
+			if bombTime, err := gameState.Rules().BombTime(); err == nil && bombTime > 0 {
+				return bombTime.Seconds()
+			}
+		}
+	}
+	return types.CS2BombTime
+}
+
+// bombSiteString returns the site letter, or an empty string when the site is unknown
+func bombSiteString(site events.Bombsite) string {
+	if site == events.BomsiteUnknown {
+		return ""
+	}
+	return string(rune(site))
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBombTestProcessor() *EventProcessor {
+	matchState := &types.MatchState{
+		Players:      make(map[string]*types.Player),
+		CurrentRound: 3,
+		BombEvents:   make([]types.BombEvent, 0),
+	}
+	return NewEventProcessor(matchState, logrus.New(), nil, nil)
+}
+
+func TestBombHandler_PlantAndDefuse(t *testing.T) {
+	processor := newBombTestProcessor()
+	handler := processor.bombHandler
+
+	planter := &common.Player{SteamID64: 76561198000000001, Team: common.TeamTerrorists}
+	defuser := &common.Player{SteamID64: 76561198000000002, Team: common.TeamCounterTerrorists}
+
+	// Round went live at tick 1000; 64 ticks per second
+	processor.matchState.RoundFreezeEndTick = 1000
+
+	processor.currentTick = 1000 + 64*50
+	require.NoError(t, handler.HandleBombPlantBegin(events.BombPlantBegin{BombEvent: events.BombEvent{Player: planter, Site: events.BombsiteB}}))
+	require.NoError(t, handler.HandleBombPlantAborted(events.BombPlantAborted{Player: planter}))
+
+	processor.currentTick = 1000 + 64*60
+	require.NoError(t, processor.matchHandler.HandleBombPlanted(events.BombPlanted{BombEvent: events.BombEvent{Player: planter, Site: events.BombsiteB}}))
+	require.NoError(t, handler.HandleBombPlanted(events.BombPlanted{BombEvent: events.BombEvent{Player: planter, Site: events.BombsiteB}}))
+
+	processor.currentTick = 1000 + 64*90
+	require.NoError(t, handler.HandleBombDefuseStart(events.BombDefuseStart{Player: defuser, HasKit: true}))
+
+	processor.currentTick = 1000 + 64*95
+	require.NoError(t, handler.HandleBombDefused(events.BombDefused{BombEvent: events.BombEvent{Player: defuser}}))
+
+	bombEvents := processor.matchState.BombEvents
+	require.Len(t, bombEvents, 5)
+
+	assert.Equal(t, types.BombEventPlantStart, bombEvents[0].EventType)
+	assert.Equal(t, "B", bombEvents[0].Site)
+	assert.Equal(t, 3, bombEvents[0].RoundNumber)
+	assert.InDelta(t, types.CS2RoundTime-50, bombEvents[0].TimeRemaining, 0.01)
+	require.NotNil(t, bombEvents[0].PlayerSteamID)
+	assert.Equal(t, "76561198000000001", *bombEvents[0].PlayerSteamID)
+	assert.Equal(t, "T", bombEvents[0].PlayerSide)
+
+	assert.Equal(t, types.BombEventPlantAbort, bombEvents[1].EventType)
+	assert.Equal(t, "B", bombEvents[1].Site)
+
+	assert.Equal(t, types.BombEventPlanted, bombEvents[2].EventType)
+	assert.InDelta(t, types.CS2BombTime, bombEvents[2].TimeRemaining, 0.01)
+
+	assert.Equal(t, types.BombEventDefuseStart, bombEvents[3].EventType)
+	assert.True(t, bombEvents[3].HasKit)
+	assert.Equal(t, "B", bombEvents[3].Site)
+	assert.InDelta(t, types.CS2BombTime-30, bombEvents[3].TimeRemaining, 0.01)
+
+	assert.Equal(t, types.BombEventDefused, bombEvents[4].EventType)
+	assert.True(t, bombEvents[4].HasKit)
+	assert.Equal(t, "B", bombEvents[4].Site)
+	assert.Equal(t, "CT", bombEvents[4].PlayerSide)
+}
+
+func TestBombHandler_ExplodeAndCarrier(t *testing.T) {
+	processor := newBombTestProcessor()
+	handler := processor.bombHandler
+
+	carrier := &common.Player{SteamID64: 76561198000000001, Team: common.TeamTerrorists}
+
+	// Dropped during freeze time, the round clock has not started
+	require.NoError(t, handler.HandleBombDropped(events.BombDropped{Player: carrier}))
+	require.NoError(t, handler.HandleBombPickup(events.BombPickup{Player: carrier}))
+
+	processor.matchState.RoundFreezeEndTick = 1000
+	processor.currentTick = 1000 + 64*100
+	require.NoError(t, processor.matchHandler.HandleBombPlanted(events.BombPlanted{BombEvent: events.BombEvent{Player: carrier, Site: events.BombsiteA}}))
+	require.NoError(t, handler.HandleBombPlanted(events.BombPlanted{BombEvent: events.BombEvent{Player: carrier, Site: events.BombsiteA}}))
+
+	processor.currentTick += 64 * types.CS2BombTime
+	require.NoError(t, handler.HandleBombExplode(events.BombExplode{}))
+
+	bombEvents := processor.matchState.BombEvents
+	require.Len(t, bombEvents, 4)
+
+	assert.Equal(t, types.BombEventDropped, bombEvents[0].EventType)
+	assert.Empty(t, bombEvents[0].Site)
+	assert.InDelta(t, types.CS2RoundTime, bombEvents[0].TimeRemaining, 0.01)
+	assert.Equal(t, types.BombEventPickedUp, bombEvents[1].EventType)
+
+	assert.Equal(t, types.BombEventExploded, bombEvents[3].EventType)
+	assert.Equal(t, "A", bombEvents[3].Site)
+	assert.Nil(t, bombEvents[3].PlayerSteamID)
+	assert.InDelta(t, 0, bombEvents[3].TimeRemaining, 0.01)
+}
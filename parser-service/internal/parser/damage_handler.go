@@ -72,6 +72,8 @@ func (dh *DamageHandler) HandlePlayerHurt(e events.PlayerHurt) error {
 		TickTimestamp:   dh.processor.currentTick,
 		AttackerSteamID: types.SteamIDToString(e.Attacker.SteamID64),
 		VictimSteamID:   types.SteamIDToString(e.Player.SteamID64),
+		AttackerPlace:   dh.processor.getPlayerPlace(e.Attacker),
+		VictimPlace:     dh.processor.getPlayerPlace(e.Player),
 		Damage:          actualHealthDamage,
 		ArmorDamage:     e.ArmorDamage,
 		HealthDamage:    actualHealthDamage,
@@ -2,14 +2,16 @@ package parser
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"parser-service/internal/config"
 	"parser-service/internal/database"
+	"parser-service/internal/heatmap"
+	"parser-service/internal/radar"
 	"parser-service/internal/types"
 	"parser-service/internal/utils"
 
@@ -26,8 +28,10 @@ type DemoParser struct {
 	perfLogger        *utils.PerformanceLogger
 	progressManager   *ProgressManager
 	gameModeDetector  *GameModeDetector
+	matchTypeDetector *MatchTypeDetector
 	db                *database.Database
 	playerTickService *database.PlayerTickService
+	tickWriter        *database.AsyncTickWriter
 	matchID           string
 	ticksProcessed    int64 // Track number of ticks processed for sampling stats
 	ticksSkipped      int64 // Track number of ticks skipped due to sampling
@@ -48,11 +52,17 @@ func NewDemoParser(cfg *config.Config, logger *logrus.Logger, perfLogger *utils.
 	// Initialize player tick service
 	playerTickService := database.NewPlayerTickService(db.DB, logger)
 
+	if cfg.Database.CleanupOnFinish && cfg.Parser.Heatmaps {
+		logger.WithField("tick_data_retention", cfg.Database.TickDataRetention.String()).
+			Warn("parser.heatmaps keeps tick data after parsing despite database.cleanup_on_finish; set parser.heatmaps to false to delete it")
+	}
+
 	return &DemoParser{
 		config:            cfg,
 		logger:            logger,
 		perfLogger:        perfLogger,
 		gameModeDetector:  NewGameModeDetector(logger),
+		matchTypeDetector: NewMatchTypeDetector(logger, cfg.Parser.MatchTypeRules),
 		db:                db,
 		playerTickService: playerTickService,
 	}, nil
@@ -67,6 +77,18 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 	// Generate unique match ID for this parsing session
 	dp.matchID = uuid.New().String()
 
+	// Write tick data in the background so parsing never waits on the database
+	dp.tickWriter = database.NewAsyncTickWriter(dp.playerTickService, dp.logger, dp.matchID, database.TickWriterOptions{
+		Format:        dp.config.Database.TickStorage,
+		QueueSize:     dp.config.Database.TickWriterQueueSize,
+		BatchSize:     dp.config.Database.TickWriterBatchSize,
+		FlushInterval: dp.config.Database.TickWriterFlushInterval,
+	})
+	defer dp.tickWriter.Close(context.Background())
+
+	dp.purgeExpiredShootingData(ctx)
+	dp.purgeExpiredTickData(ctx)
+
 	// Reset tick counters for this parse
 	dp.ticksProcessed = 0
 	dp.ticksSkipped = 0
@@ -84,8 +106,8 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 			dp.progressManager.ReportError(errorMsg, "PARSING_PANIC")
 			dp.logger.WithField("panic", r).Error("Panic occurred during demo parsing")
 
-			// Cleanup match data on panic if configured
-			dp.cleanupMatchData(ctx, eventProcessor)
+			// Cleanup match data on panic
+			dp.cleanupMatchData(ctx, eventProcessor, true)
 		}
 	}()
 
@@ -94,8 +116,8 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 			WithContext("demo_path", demoPath)
 		dp.progressManager.ReportParseError(parseError)
 
-		// Cleanup match data on validation error if configured
-		dp.cleanupMatchData(ctx, eventProcessor)
+		// Cleanup match data on validation error
+		dp.cleanupMatchData(ctx, eventProcessor, true)
 		return nil, parseError
 	}
 
@@ -106,6 +128,13 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 		GrenadeEvents:     make([]types.GrenadeEvent, 0),
 		DamageEvents:      make([]types.DamageEvent, 0),
 		PlayerRoundEvents: make([]types.PlayerRoundEvent, 0),
+		BombEvents:        make([]types.BombEvent, 0),
+
+		TeamEconomyEvents: make([]types.TeamEconomyEvent, 0),
+		ItemEvents:        make([]types.ItemEvent, 0),
+		LoadoutEvents:     make([]types.LoadoutEvent, 0),
+		TradeEvents:       make([]types.TradeEvent, 0),
+		RoundReplays:      make([]types.RoundReplay, 0),
 	}
 
 	eventProcessor = NewEventProcessor(matchState, dp.logger, dp.config, dp.perfLogger)
@@ -136,6 +165,7 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 		eventProcessor.SetDemoParser(parser)
 		eventProcessor.SetPlayerTickService(dp.playerTickService)
 		eventProcessor.SetMatchID(dp.matchID)
+		eventProcessor.SetTickWriter(dp.tickWriter)
 
 		// Initialize round tick cache for performance optimization
 		eventProcessor.InitializeRoundTickCache(dp.matchID)
@@ -143,15 +173,19 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 		parser.RegisterNetMessageHandler(func(m *msg.CDemoFileHeader) {
 			mapName = m.GetMapName()
 			serverName = m.GetServerName()
+			eventProcessor.SetMapName(mapName)
+		})
 
-			// Detect FACEIT match early from server name
-			if serverName != "" {
-				serverNameUpper := strings.ToUpper(serverName)
-				if strings.Contains(serverNameUpper, "FACEIT.COM") {
-					eventProcessor.SetIsFaceitMatch(true)
-					dp.logger.WithField("server_name", serverName).Info("FACEIT match detected, will skip first round")
-				}
-			}
+		// Every time window is converted to ticks through the processor's timing context,
+		// so switch it to the demo's real tick rate as soon as the server reports it
+		parser.RegisterEventHandler(func(e events.TickRateInfoAvailable) {
+			eventProcessor.SetTickRate(e.TickRate)
+			dp.logger.WithField("tick_rate", eventProcessor.Timing().TickRate).Info("Demo tick rate detected")
+		})
+
+		// Keep the halftime and overtime schedule in line with the server's convars
+		parser.RegisterEventHandler(func(e events.ConVarsUpdated) {
+			dp.refreshMatchRules(parser, eventProcessor)
 		})
 
 		dp.registerEventHandlers(parser, eventProcessor)
@@ -159,8 +193,19 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 		parser.RegisterEventHandler(func(e events.FrameDone) {
 			eventProcessor.UpdateCurrentTickAndPlayers(int64(parser.GameState().IngameTick()), parser.GameState())
 
+			// Timeouts and technical pauses only show up in the game rules flags
+			if rules := parser.GameState().Rules(); rules != nil {
+				if pauseState, ok := readPauseState(rules.Entity()); ok {
+					eventProcessor.UpdatePauseState(pauseState)
+				}
+			}
+
 			// Track player positions and aim for each tick
 			dp.trackPlayerTickData(ctx, parser, eventProcessor)
+
+			// Sample grenades in flight for their trajectories and fires for their spread
+			eventProcessor.SampleGrenadeFlights(parser.GameState().GrenadeProjectiles())
+			eventProcessor.SampleInfernos(parser.GameState().Infernos(), parser.GameState().Participants().Playing())
 		})
 
 		gameState := parser.GameState()
@@ -173,14 +218,19 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 		return nil
 	})
 
+	var diagnostics []types.ParseDiagnostic
 	if err != nil {
-		parseError := types.NewParseError(types.ErrorTypeParsing, "failed to parse demo", err).
-			WithContext("demo_path", demoPath)
-		dp.progressManager.ReportParseError(parseError)
-
-		// Cleanup match data on parsing error if configured
-		dp.cleanupMatchData(ctx, eventProcessor)
-		return nil, parseError
+		diagnostic, recovered := dp.recoverPartialParse(err, eventProcessor)
+		if !recovered {
+			parseError := types.NewParseError(types.ErrorTypeParsing, "failed to parse demo", err).
+				WithContext("demo_path", demoPath)
+			dp.progressManager.ReportParseError(parseError)
+
+			// Cleanup match data on parsing error
+			dp.cleanupMatchData(ctx, eventProcessor, true)
+			return nil, parseError
+		}
+		diagnostics = append(diagnostics, diagnostic)
 	}
 
 	// Check if critical error occurred during parsing
@@ -190,8 +240,18 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 			WithContext("demo_path", demoPath).
 			WithContext("error_code", errorCode)
 
-		// Cleanup match data on critical error if configured
-		dp.cleanupMatchData(ctx, eventProcessor)
+		// Cleanup match data on critical error
+		dp.cleanupMatchData(ctx, eventProcessor, true)
+		return nil, parseError
+	}
+
+	// Persist tick data still queued after the last round end
+	if err := dp.tickWriter.Close(ctx); err != nil {
+		parseError := types.NewParseError(types.ErrorTypeEventProcessing, "failed to write player tick data", err).
+			WithContext("match_id", dp.matchID)
+		dp.progressManager.ReportParseError(parseError)
+
+		dp.cleanupMatchData(ctx, eventProcessor, true)
 		return nil, parseError
 	}
 
@@ -200,6 +260,10 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 		playbackTicks = demoParser.CurrentFrame()
 	}
 
+	// A demo can stop while the match is still paused
+	eventProcessor.FinishPauses()
+	eventProcessor.FinishRoster()
+
 	dp.progressManager.UpdateProgress(types.ProgressUpdate{
 		Status:         types.StatusProcessingEvents,
 		Progress:       85,
@@ -233,6 +297,11 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 	buildStart := time.Now()
 	parsedData := dp.buildParsedData(matchState, mapName, serverName, playbackTicks, eventProcessor, demoParser)
 	buildElapsed := time.Since(buildStart)
+	if len(diagnostics) > 0 {
+		parsedData.Partial = true
+		parsedData.LastCompleteRound = matchState.CurrentRound
+		parsedData.Diagnostics = diagnostics
+	}
 	dp.logger.WithFields(logrus.Fields{
 		"label":       "match_aggregation",
 		"start_time":  buildStart,
@@ -254,11 +323,47 @@ func (dp *DemoParser) ParseDemoFromFile(ctx context.Context, demoPath string, pr
 	})
 
 	// Cleanup match data on successful completion if configured
-	dp.cleanupMatchData(ctx, eventProcessor)
+	dp.cleanupMatchData(ctx, eventProcessor, false)
 
 	return parsedData, nil
 }
 
+// recoverPartialParse decides whether a demo that failed to parse still yields a
+// result. Crashed servers leave demos that end mid-round, so everything up to the
+// last complete round is kept and the round in progress is dropped.
+func (dp *DemoParser) recoverPartialParse(parseErr error, eventProcessor *EventProcessor) (types.ParseDiagnostic, bool) {
+	if !dp.config.Parser.TolerantParsing || eventProcessor == nil {
+		return types.ParseDiagnostic{}, false
+	}
+
+	// Errors raised by our own handlers and cancelled parses are not demo problems
+	if errors.Is(parseErr, demoinfocs.ErrCancelled) || dp.progressManager.HasError() {
+		return types.ParseDiagnostic{}, false
+	}
+
+	tick := eventProcessor.currentTick
+	lastCompleteRound := eventProcessor.TruncateIncompleteRound()
+	if lastCompleteRound == 0 {
+		return types.ParseDiagnostic{}, false
+	}
+
+	code := "DEMO_CORRUPT"
+	if errors.Is(parseErr, demoinfocs.ErrUnexpectedEndOfDemo) {
+		code = "DEMO_TRUNCATED"
+	}
+
+	dp.logger.WithError(parseErr).WithFields(logrus.Fields{
+		"tick":                tick,
+		"last_complete_round": lastCompleteRound,
+	}).Warn("Demo ended unexpectedly, returning completed rounds only")
+
+	return types.ParseDiagnostic{
+		Code:    code,
+		Message: parseErr.Error(),
+		Tick:    tick,
+	}, true
+}
+
 func (dp *DemoParser) postProcessGrenadeMovement(eventProcessor *EventProcessor) {
 	// Starting grenade movement post-processing
 
@@ -341,12 +446,26 @@ func (dp *DemoParser) validateDemoFile(demoPath string) error {
 	return nil
 }
 
+// refreshMatchRules re-derives the side switch schedule from the game mode and convars
+// seen so far. Detection problems are reported once the match is built, not here.
+func (dp *DemoParser) refreshMatchRules(parser demoinfocs.Parser, eventProcessor *EventProcessor) {
+	gameState := parser.GameState()
+	if gameState == nil || gameState.Rules() == nil {
+		return
+	}
+
+	gameMode, _ := dp.gameModeDetector.DetectGameMode(parser)
+	eventProcessor.UpdateMatchRules(gameMode, gameState.Rules().ConVars())
+}
+
 func (dp *DemoParser) registerEventHandlers(parser demoinfocs.Parser, eventProcessor *EventProcessor) {
 	parser.RegisterEventHandler(func(e events.RoundStart) {
 		if dp.progressManager.HasError() {
 			return
 		}
 
+		dp.refreshMatchRules(parser, eventProcessor)
+
 		if err := eventProcessor.HandleRoundStart(e); err != nil {
 			if parseErr, ok := err.(*types.ParseError); ok {
 				dp.progressManager.ReportParseError(parseErr)
@@ -448,6 +567,51 @@ func (dp *DemoParser) registerEventHandlers(parser demoinfocs.Parser, eventProce
 		}
 	})
 
+	parser.RegisterEventHandler(func(e events.GrenadeProjectileBounce) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleGrenadeProjectileBounce(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "GRENADE_BOUNCE_FAILED")
+			}
+			return
+		}
+	})
+
+	parser.RegisterEventHandler(func(e events.InfernoStart) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleInfernoStart(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "INFERNO_START_FAILED")
+			}
+			return
+		}
+	})
+
+	parser.RegisterEventHandler(func(e events.InfernoExpired) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleInfernoExpired(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "INFERNO_EXPIRED_FAILED")
+			}
+			return
+		}
+	})
+
 	parser.RegisterEventHandler(func(e events.FlashExplode) {
 		if dp.progressManager.HasError() {
 			return
@@ -510,6 +674,66 @@ func (dp *DemoParser) registerEventHandlers(parser demoinfocs.Parser, eventProce
 		}
 	})
 
+	parser.RegisterEventHandler(func(e events.RoundFreezetimeEnd) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleRoundFreezetimeEnd(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "ROUND_FREEZETIME_END_FAILED")
+			}
+			return
+		}
+	})
+
+	parser.RegisterEventHandler(func(e events.MatchStart) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleMatchStart(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "MATCH_START_FAILED")
+			}
+			return
+		}
+	})
+
+	parser.RegisterEventHandler(func(e events.BombPlantBegin) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleBombPlantBegin(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "BOMB_PLANT_BEGIN_FAILED")
+			}
+			return
+		}
+	})
+
+	parser.RegisterEventHandler(func(e events.BombPlantAborted) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleBombPlantAborted(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "BOMB_PLANT_ABORTED_FAILED")
+			}
+			return
+		}
+	})
+
 	parser.RegisterEventHandler(func(e events.BombPlanted) {
 		if dp.progressManager.HasError() {
 			return
@@ -525,6 +749,36 @@ func (dp *DemoParser) registerEventHandlers(parser demoinfocs.Parser, eventProce
 		}
 	})
 
+	parser.RegisterEventHandler(func(e events.BombDefuseStart) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleBombDefuseStart(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "BOMB_DEFUSE_START_FAILED")
+			}
+			return
+		}
+	})
+
+	parser.RegisterEventHandler(func(e events.BombDefuseAborted) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleBombDefuseAborted(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "BOMB_DEFUSE_ABORTED_FAILED")
+			}
+			return
+		}
+	})
+
 	parser.RegisterEventHandler(func(e events.BombDefused) {
 		if dp.progressManager.HasError() {
 			return
@@ -555,6 +809,81 @@ func (dp *DemoParser) registerEventHandlers(parser demoinfocs.Parser, eventProce
 		}
 	})
 
+	parser.RegisterEventHandler(func(e events.BombDropped) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleBombDropped(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "BOMB_DROPPED_FAILED")
+			}
+			return
+		}
+	})
+
+	parser.RegisterEventHandler(func(e events.BombPickup) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleBombPickup(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "BOMB_PICKUP_FAILED")
+			}
+			return
+		}
+	})
+
+	parser.RegisterEventHandler(func(e events.ItemPickup) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleItemPickup(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "ITEM_PICKUP_FAILED")
+			}
+			return
+		}
+	})
+
+	parser.RegisterEventHandler(func(e events.ItemDrop) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleItemDrop(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "ITEM_DROP_FAILED")
+			}
+			return
+		}
+	})
+
+	parser.RegisterEventHandler(func(e events.ItemRefund) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleItemRefund(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "ITEM_REFUND_FAILED")
+			}
+			return
+		}
+	})
+
 	parser.RegisterEventHandler(func(e events.PlayerConnect) {
 		if dp.progressManager.HasError() {
 			return
@@ -570,6 +899,21 @@ func (dp *DemoParser) registerEventHandlers(parser demoinfocs.Parser, eventProce
 		}
 	})
 
+	parser.RegisterEventHandler(func(e events.BotTakenOver) {
+		if dp.progressManager.HasError() {
+			return
+		}
+
+		if err := eventProcessor.HandleBotTakenOver(e); err != nil {
+			if parseErr, ok := err.(*types.ParseError); ok {
+				dp.progressManager.ReportParseError(parseErr)
+			} else {
+				dp.progressManager.ReportError(err.Error(), "BOT_TAKEN_OVER_FAILED")
+			}
+			return
+		}
+	})
+
 	parser.RegisterEventHandler(func(e events.PlayerDisconnected) {
 		if dp.progressManager.HasError() {
 			return
@@ -646,24 +990,24 @@ func (dp *DemoParser) buildParsedData(matchState *types.MatchState, mapName stri
 		}
 	}
 
-	// Determine match type based on server name header
-	matchType := dp.detectMatchType(serverName, demoParser)
+	// Determine match type from the server name, plugin convars and ranks
+	matchTypeDetection := dp.detectMatchType(serverName, demoParser)
 
 	match := types.Match{
+		MatchID:          dp.matchID,
 		Map:              mapName,
 		WinningTeam:      winningTeam,
 		WinningTeamScore: winningTeamScore,
 		LosingTeamScore:  losingTeamScore,
-		MatchType:        matchType,
+		MatchType:        matchTypeDetection.MatchType,
 		GameMode:         gameMode,
 		StartTimestamp:   nil,
 		EndTimestamp:     nil,
 		TotalRounds:      totalRounds,
 		PlaybackTicks:    playbackTicks,
 	}
-
-	now := time.Now()
-	match.EndTimestamp = &now
+	match.MatchTypeDetection = matchTypeDetection
+	dp.applyMatchTiming(&match, matchState, eventProcessor.Timing(), time.Now())
 
 	// Aggregate player match events before logging
 	eventProcessor.playerMatchHandler.aggregatePlayerMatchEvent()
@@ -683,10 +1027,7 @@ func (dp *DemoParser) buildParsedData(matchState *types.MatchState, mapName stri
 
 	// Match data built with event counts
 
-	aimEvents := eventProcessor.GetAimEvents()
-	achievements := calculateAchievements(matchState.PlayerMatchEvents, aimEvents)
-
-	return &types.ParsedDemoData{
+	parsedData := &types.ParsedDemoData{
 		Match:             match,
 		Players:           players,
 		GunfightEvents:    matchState.GunfightEvents,
@@ -695,87 +1036,88 @@ func (dp *DemoParser) buildParsedData(matchState *types.MatchState, mapName stri
 		DamageEvents:      matchState.DamageEvents,
 		PlayerRoundEvents: matchState.PlayerRoundEvents,
 		PlayerMatchEvents: matchState.PlayerMatchEvents,
-		AimEvents:         aimEvents,
+		AimEvents:         eventProcessor.GetAimEvents(),
 		AimWeaponEvents:   eventProcessor.GetAimWeaponEvents(),
-		Achievements:      achievements,
+		PauseEvents:       matchState.PauseEvents,
+		DiscardedRounds:   eventProcessor.roundValidity.Discarded(),
+		Substitutions:     matchState.Substitutions,
+		BombEvents:        matchState.BombEvents,
+
+		TeamEconomyEvents: matchState.TeamEconomyEvents,
+		ItemEvents:        matchState.ItemEvents,
+		LoadoutEvents:     matchState.LoadoutEvents,
+		TradeEvents:       matchState.TradeEvents,
+		RoundReplays:      matchState.RoundReplays,
+
+		RoundAimEvents:       eventProcessor.GetRoundAimEvents(),
+		RoundAimWeaponEvents: eventProcessor.GetRoundAimWeaponEvents(),
 	}
-}
 
-// detectMatchType determines the match type based on server name header
-func (dp *DemoParser) detectMatchType(serverName string, parser demoinfocs.Parser) string {
-	// Check server name header for match type
-	if serverName != "" {
-		serverNameUpper := strings.ToUpper(serverName)
-
-		// Check for FACEIT.com first (more specific)
-		if strings.Contains(serverNameUpper, "FACEIT.COM") {
-			dp.logger.WithField("server_name", serverName).Debug("Match type detected as FACEIT from server name")
-			return types.MatchTypeFaceit
-		}
-
-		// Check for Valve
-		if strings.Contains(serverNameUpper, "VALVE") {
-			dp.logger.WithField("server_name", serverName).Debug("Match type detected as Valve from server name")
-			return types.MatchTypeValve
-		}
-
-		// Server name exists but doesn't contain FACEIT.com or Valve
-		dp.logger.WithField("server_name", serverName).Debug("Match type detected as unknown from server name")
-		return types.MatchTypeUnknown
+	if !dp.config.Parser.IncludeBots {
+		removeBots(parsedData)
 	}
+	parsedData.Achievements = calculateAchievements(parsedData.PlayerMatchEvents, parsedData.AimEvents)
 
-	// If server name is empty, fall back to parser-based detection
-	if parser == nil {
-		dp.logger.Warn("Parser is nil and server name is empty, defaulting to 'unknown' match type")
-		return types.MatchTypeUnknown
+	if dp.config.Parser.RadarCoordinates {
+		dp.addRadarCoordinates(parsedData)
 	}
 
-	gameState := parser.GameState()
-	if gameState == nil {
-		dp.logger.Warn("Game state is nil and server name is empty, defaulting to 'unknown' match type")
-		return types.MatchTypeUnknown
-	}
+	return parsedData
+}
 
-	players := gameState.Participants().All()
-	if len(players) == 0 {
-		dp.logger.Warn("No players found and server name is empty, defaulting to 'unknown' match type")
-		return types.MatchTypeUnknown
+// addRadarCoordinates projects every position onto the map's radar image. Maps
+// without overview data keep world coordinates only.
+func (dp *DemoParser) addRadarCoordinates(parsedData *types.ParsedDemoData) {
+	registry, err := radar.LoadRegistry(dp.config.Parser.RadarDir)
+	if err != nil {
+		dp.logger.WithError(err).Warn("Failed to load radar overviews, using the built in ones")
+		registry = radar.NewRegistry()
 	}
 
-	// Check if any player has a valid rank (not unranked) as fallback
-	hasValidRank := false
-	rankTypeCounts := make(map[int]int)
+	overview, exists := registry.Overview(parsedData.Match.Map)
+	if !exists {
+		dp.logger.WithField("map", parsedData.Match.Map).Debug("No radar overview for map")
+		return
+	}
 
-	for _, player := range players {
-		if player != nil {
-			rank := player.Rank()
-			rankType := player.RankType()
-			rankTypeCounts[rankType]++
+	radar.Annotate(parsedData, overview)
+}
 
-			// A valid rank is one that's not 0 (unranked) and has a valid rank type
-			if rank > 0 && rankType > 0 {
-				hasValidRank = true
-			}
-		}
+// applyMatchTiming sets the match start and end from demo time. Demos record no wall
+// clock time, so the match is taken to have ended when it was parsed and to have
+// started its duration before that.
+func (dp *DemoParser) applyMatchTiming(match *types.Match, matchState *types.MatchState, timing *types.TickTiming, parsedAt time.Time) {
+	match.StartTick = matchState.MatchStartTick
+	match.EndTick = matchState.MatchEndTick
+	match.StartTime = timing.TicksToSeconds(match.StartTick)
+	match.EndTime = timing.TicksToSeconds(match.EndTick)
+	if match.EndTick > match.StartTick {
+		match.DurationSeconds = timing.TicksToSeconds(match.EndTick - match.StartTick)
 	}
 
-	dp.logger.WithFields(logrus.Fields{
-		"rank_type_counts": rankTypeCounts,
-		"has_valid_rank":   hasValidRank,
-	}).Debug("Falling back to rank-based match type detection")
+	end := parsedAt
+	start := end.Add(-time.Duration(match.DurationSeconds * float64(time.Second)))
+	match.StartTimestamp = &start
+	match.EndTimestamp = &end
+}
 
-	// If any player has a valid rank, it's likely a Valve match
-	if hasValidRank {
-		return types.MatchTypeValve
+// detectMatchType determines the match type from the server name header, plugin
+// convars and player ranks
+func (dp *DemoParser) detectMatchType(serverName string, parser demoinfocs.Parser) *types.MatchTypeDetection {
+	if dp.matchTypeDetector == nil {
+		var configured []config.MatchTypeRuleConfig
+		if dp.config != nil {
+			configured = dp.config.Parser.MatchTypeRules
+		}
+		dp.matchTypeDetector = NewMatchTypeDetector(dp.logger, configured)
 	}
 
-	// No valid ranks found and no server name, default to unknown
-	return types.MatchTypeUnknown
+	return dp.matchTypeDetector.Detect(collectMatchTypeSignals(serverName, parser))
 }
 
 // trackPlayerTickData tracks player positions and aim for each tick
 func (dp *DemoParser) trackPlayerTickData(ctx context.Context, parser demoinfocs.Parser, eventProcessor *EventProcessor) {
-	// Skip tracking tick data during round 1 for FACEIT matches
+	// Warmup, knife and restarted rounds are not part of the match
 	if eventProcessor != nil && eventProcessor.shouldSkipCurrentRound() {
 		return
 	}
@@ -834,24 +1176,44 @@ func (dp *DemoParser) trackPlayerTickData(ctx context.Context, parser demoinfocs
 		}
 
 		tickData = append(tickData, playerTickData)
+
+		if eventProcessor != nil && eventProcessor.replayHandler != nil {
+			eventProcessor.replayHandler.RecordSample(currentTick, participant)
+		}
+	}
+
+	roundNumber := 0
+	if eventProcessor != nil {
+		roundNumber = eventProcessor.matchState.CurrentRound
 	}
 
-	// Save tick data in batch for performance
-	if len(tickData) > 0 {
-		if err := dp.playerTickService.SavePlayerTickDataBatch(ctx, tickData); err != nil {
-			dp.logger.WithFields(logrus.Fields{
-				"match_id":     dp.matchID,
-				"tick":         currentTick,
-				"player_count": len(tickData),
-				"error":        err,
-			}).Error("Failed to save player tick data")
+	// Queue tick data for the background writer; this blocks when the writer falls behind
+	if err := dp.tickWriter.Write(ctx, roundNumber, tickData); err != nil {
+		if dp.progressManager.HasError() {
+			return
 		}
+		dp.logger.WithFields(logrus.Fields{
+			"match_id":     dp.matchID,
+			"tick":         currentTick,
+			"player_count": len(tickData),
+			"error":        err,
+		}).Error("Failed to save player tick data")
+		parseError := types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityCritical, "failed to write player tick data", err).
+			WithContext("match_id", dp.matchID).
+			WithContext("tick", currentTick)
+		dp.progressManager.ReportParseError(parseError)
 	}
 }
 
-// cleanupMatchData deletes match data if cleanup is enabled in configuration
-func (dp *DemoParser) cleanupMatchData(ctx context.Context, eventProcessor *EventProcessor) {
-	if !dp.config.Database.CleanupOnFinish {
+// cleanupMatchData deletes match data if cleanup is enabled in configuration. The
+// tick data of a failed parse is always deleted, since nothing reads it afterwards.
+func (dp *DemoParser) cleanupMatchData(ctx context.Context, eventProcessor *EventProcessor, failed bool) {
+	// Stop the tick writer first so no queued writes land after the delete
+	if dp.tickWriter != nil {
+		_ = dp.tickWriter.Close(ctx)
+	}
+
+	if (!dp.config.Database.CleanupOnFinish && !failed) || dp.playerTickService == nil {
 		return
 	}
 
@@ -862,8 +1224,14 @@ func (dp *DemoParser) cleanupMatchData(ctx context.Context, eventProcessor *Even
 
 	// Cleaning up match data
 
-	// Clean up player tick data from database
-	if err := dp.playerTickService.DeletePlayerTickDataByMatch(ctx, dp.matchID); err != nil {
+	// Clean up player tick data from database, unless the heatmap density layer is
+	// still to be rendered from it
+	if dp.config.Parser.Heatmaps && !failed {
+		dp.logger.WithFields(logrus.Fields{
+			"match_id":  dp.matchID,
+			"retention": dp.config.Database.TickDataRetention.String(),
+		}).Info("Keeping player tick data for the heatmap density layer, parser.heatmaps overrides database.cleanup_on_finish")
+	} else if err := dp.playerTickService.DeletePlayerTickDataByMatch(ctx, dp.matchID); err != nil {
 		dp.logger.WithFields(logrus.Fields{
 			"match_id": dp.matchID,
 			"error":    err,
@@ -874,7 +1242,7 @@ func (dp *DemoParser) cleanupMatchData(ctx context.Context, eventProcessor *Even
 		}).Info("Successfully cleaned up player tick data")
 	}
 
-	// Clean up in-memory shooting data
+	// Clean up in-memory shooting data; persisted shots are kept until they expire
 	if eventProcessor != nil && eventProcessor.aimTrackingHandler != nil {
 		shootingDataCount := len(eventProcessor.aimTrackingHandler.GetShootingData())
 		eventProcessor.aimTrackingHandler.ClearShootingData()
@@ -884,3 +1252,104 @@ func (dp *DemoParser) cleanupMatchData(ctx context.Context, eventProcessor *Even
 		}).Info("Successfully cleaned up player shooting data")
 	}
 }
+
+// purgeExpiredTickData removes persisted tick data older than the configured retention
+func (dp *DemoParser) purgeExpiredTickData(ctx context.Context) {
+	retention := dp.config.Database.TickDataRetention
+	if retention <= 0 || dp.playerTickService == nil {
+		return
+	}
+
+	deleted, err := dp.playerTickService.DeletePlayerTickDataOlderThan(ctx, time.Now().Add(-retention))
+	if err != nil {
+		dp.logger.WithFields(logrus.Fields{
+			"retention": retention.String(),
+			"error":     err,
+		}).Error("Failed to purge expired tick data")
+		return
+	}
+
+	if deleted > 0 {
+		dp.logger.WithFields(logrus.Fields{
+			"retention":    retention.String(),
+			"rows_deleted": deleted,
+		}).Info("Purged expired tick data")
+	}
+}
+
+// purgeExpiredShootingData removes persisted shooting data older than the configured retention
+func (dp *DemoParser) purgeExpiredShootingData(ctx context.Context) {
+	retention := dp.config.Database.ShootingDataRetention
+	if retention <= 0 || dp.playerTickService == nil {
+		return
+	}
+
+	deleted, err := dp.playerTickService.DeletePlayerShootingDataOlderThan(ctx, time.Now().Add(-retention))
+	if err != nil {
+		dp.logger.WithFields(logrus.Fields{
+			"retention": retention.String(),
+			"error":     err,
+		}).Error("Failed to purge expired shooting data")
+		return
+	}
+
+	if deleted > 0 {
+		dp.logger.WithFields(logrus.Fields{
+			"retention":    retention.String(),
+			"rows_deleted": deleted,
+		}).Info("Purged expired shooting data")
+	}
+}
+
+// MergeStitchedMatchData moves the stored tick and shooting data of every later demo
+// of a stitched match under the match's ID, numbered by the match's rounds. Data of a
+// round that was replayed in the next demo is dropped.
+func (dp *DemoParser) MergeStitchedMatchData(ctx context.Context, stitched *types.ParsedDemoData) error {
+	if dp.playerTickService == nil || len(stitched.Segments) < 2 {
+		return nil
+	}
+
+	last := len(stitched.Segments) - 1
+	for i, segment := range stitched.Segments {
+		move := database.MatchDataMove{
+			FromMatchID: segment.MatchID,
+			ToMatchID:   stitched.Match.MatchID,
+			RoundOffset: segment.FirstRound - 1,
+		}
+		if i < last {
+			// The demo's own round numbers, which the offset has not been applied to yet
+			move.LastRound = segment.LastRound - segment.FirstRound + 1
+			move.LastTick = segment.EndTick
+		}
+		if err := dp.playerTickService.MoveMatchData(ctx, move); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveHeatmapPoints stores the positions of the event heatmap layers of a match, so
+// they can be rendered without the demo. Call it on the final result, after stitching.
+func (dp *DemoParser) SaveHeatmapPoints(ctx context.Context, data *types.ParsedDemoData) error {
+	if dp.playerTickService == nil || !dp.config.Parser.Heatmaps {
+		return nil
+	}
+
+	for _, layer := range heatmap.EventLayers {
+		points, err := heatmap.Points(data, layer)
+		if err != nil {
+			return err
+		}
+		if err := dp.playerTickService.SaveHeatmapPoints(ctx, points); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlayerTickService returns the storage service used for tick and shooting data
+func (dp *DemoParser) PlayerTickService() *database.PlayerTickService {
+	return dp.playerTickService
+}
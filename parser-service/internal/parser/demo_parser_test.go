@@ -2,16 +2,22 @@ package parser
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"parser-service/internal/config"
+	"parser-service/internal/database"
 	"parser-service/internal/types"
 
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
 	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 // createMockParser creates a mock demoinfocs.Parser for testing
@@ -308,6 +314,30 @@ func TestDemoParser_BuildParsedData(t *testing.T) {
 	if len(parsedData.RoundEvents) != 3 {
 		t.Errorf("Expected 3 round events, got %d", len(parsedData.RoundEvents))
 	}
+
+	if parsedData.Match.StartTimestamp == nil || parsedData.Match.EndTimestamp == nil {
+		t.Error("Expected match start and end timestamps to be set")
+	}
+}
+
+func TestDemoParser_ApplyMatchTiming(t *testing.T) {
+	parser := setupTestParser(&config.Config{}, logrus.New())
+	matchState := &types.MatchState{MatchStartTick: 640, MatchEndTick: 640 + 64*1800}
+	parsedAt := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+
+	var match types.Match
+	parser.applyMatchTiming(&match, matchState, types.NewTickTiming(64), parsedAt)
+
+	if match.DurationSeconds != 1800 {
+		t.Errorf("Expected duration of 1800 seconds, got %v", match.DurationSeconds)
+	}
+	if match.EndTimestamp == nil || !match.EndTimestamp.Equal(parsedAt) {
+		t.Errorf("Expected end timestamp %v, got %v", parsedAt, match.EndTimestamp)
+	}
+	expectedStart := parsedAt.Add(-30 * time.Minute)
+	if match.StartTimestamp == nil || !match.StartTimestamp.Equal(expectedStart) {
+		t.Errorf("Expected start timestamp %v, got %v", expectedStart, match.StartTimestamp)
+	}
 }
 
 func TestDemoParser_BuildParsedData_NoRounds(t *testing.T) {
@@ -831,8 +861,13 @@ func TestDemoParser_DetectMatchType_ServerName(t *testing.T) {
 			expected:   types.MatchTypeValve,
 		},
 		{
-			name:       "Unknown server name",
+			name:       "ESL server name",
 			serverName: "ESL Server",
+			expected:   types.MatchTypeESL,
+		},
+		{
+			name:       "Unknown server name",
+			serverName: "My Practice Server",
 			expected:   types.MatchTypeUnknown,
 		},
 		{
@@ -854,10 +889,143 @@ func TestDemoParser_DetectMatchType_ServerName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parser.detectMatchType(tt.serverName, nil)
+			result := parser.detectMatchType(tt.serverName, nil).MatchType
 			if result != tt.expected {
 				t.Errorf("Expected match type %s for server name '%s', got %s", tt.expected, tt.serverName, result)
 			}
 		})
 	}
 }
+
+func TestDemoParser_RecoverPartialParse(t *testing.T) {
+	logger := logrus.New()
+	truncated := fmt.Errorf("parsing demo: %w", demoinfocs.ErrUnexpectedEndOfDemo)
+
+	newProcessor := func(completedRounds int) *EventProcessor {
+		matchState := &types.MatchState{
+			Players:     make(map[string]*types.Player),
+			RoundEvents: make([]types.RoundEvent, 0),
+		}
+		processor := NewEventProcessor(matchState, logger, nil, nil)
+		for round := 1; round <= completedRounds; round++ {
+			processor.currentTick = int64(round * 1000)
+			if err := processor.HandleRoundStart(events.RoundStart{}); err != nil {
+				t.Fatalf("Unexpected error starting round: %v", err)
+			}
+			processor.currentTick += 500
+			processor.roundValidity.RoundEnded(processor.currentTick, events.RoundEndReasonCTWin)
+		}
+		processor.currentTick = int64((completedRounds + 1) * 1000)
+		if err := processor.HandleRoundStart(events.RoundStart{}); err != nil {
+			t.Fatalf("Unexpected error starting round: %v", err)
+		}
+		processor.currentTick += 200
+		return processor
+	}
+
+	t.Run("Truncated demo keeps completed rounds", func(t *testing.T) {
+		parser := setupTestParser(&config.Config{Parser: config.ParserConfig{TolerantParsing: true}}, logger)
+		processor := newProcessor(3)
+
+		diagnostic, recovered := parser.recoverPartialParse(truncated, processor)
+		if !recovered {
+			t.Fatal("Expected the truncated demo to be recovered")
+		}
+		if diagnostic.Code != "DEMO_TRUNCATED" {
+			t.Errorf("Expected diagnostic code DEMO_TRUNCATED, got %s", diagnostic.Code)
+		}
+		if diagnostic.Tick != 4200 {
+			t.Errorf("Expected diagnostic tick 4200, got %d", diagnostic.Tick)
+		}
+		if processor.matchState.CurrentRound != 3 {
+			t.Errorf("Expected last complete round 3, got %d", processor.matchState.CurrentRound)
+		}
+	})
+
+	t.Run("Corrupt demo", func(t *testing.T) {
+		parser := setupTestParser(&config.Config{Parser: config.ParserConfig{TolerantParsing: true}}, logger)
+
+		diagnostic, recovered := parser.recoverPartialParse(errors.New("invalid packet"), newProcessor(1))
+		if !recovered || diagnostic.Code != "DEMO_CORRUPT" {
+			t.Errorf("Expected a DEMO_CORRUPT recovery, got %v (%s)", recovered, diagnostic.Code)
+		}
+	})
+
+	t.Run("No completed rounds", func(t *testing.T) {
+		parser := setupTestParser(&config.Config{Parser: config.ParserConfig{TolerantParsing: true}}, logger)
+
+		if _, recovered := parser.recoverPartialParse(truncated, newProcessor(0)); recovered {
+			t.Error("Expected a demo without completed rounds to fail")
+		}
+	})
+
+	t.Run("Tolerant parsing disabled", func(t *testing.T) {
+		parser := setupTestParser(&config.Config{}, logger)
+
+		if _, recovered := parser.recoverPartialParse(truncated, newProcessor(3)); recovered {
+			t.Error("Expected the error to be returned when tolerant parsing is disabled")
+		}
+	})
+
+	t.Run("Cancelled parse", func(t *testing.T) {
+		parser := setupTestParser(&config.Config{Parser: config.ParserConfig{TolerantParsing: true}}, logger)
+
+		if _, recovered := parser.recoverPartialParse(demoinfocs.ErrCancelled, newProcessor(3)); recovered {
+			t.Error("Expected a cancelled parse not to be recovered")
+		}
+	})
+}
+
+func TestDemoParser_CleanupMatchData(t *testing.T) {
+	tests := []struct {
+		name      string
+		cleanup   bool
+		heatmaps  bool
+		failed    bool
+		wantTicks bool
+	}{
+		{name: "kept without cleanup", wantTicks: true},
+		{name: "deleted with cleanup", cleanup: true},
+		{name: "kept for heatmaps despite cleanup", cleanup: true, heatmaps: true, wantTicks: true},
+		{name: "failed parse deleted without cleanup", failed: true},
+		{name: "failed parse deleted with heatmaps", cleanup: true, heatmaps: true, failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("Failed to open database: %v", err)
+			}
+			if err := db.AutoMigrate(&types.PlayerTickData{}, &types.PlayerTickBlob{}); err != nil {
+				t.Fatalf("Failed to migrate: %v", err)
+			}
+
+			logger := logrus.New()
+			service := database.NewPlayerTickService(db, logger)
+			ctx := context.Background()
+			if err := service.SavePlayerTickDataBatch(ctx, []*types.PlayerTickData{{MatchID: "match-1", PlayerID: "1", Tick: 100}}); err != nil {
+				t.Fatalf("Failed to save ticks: %v", err)
+			}
+
+			dp := &DemoParser{
+				config: &config.Config{
+					Parser:   config.ParserConfig{Heatmaps: tt.heatmaps},
+					Database: config.DatabaseConfig{CleanupOnFinish: tt.cleanup},
+				},
+				logger:            logger,
+				playerTickService: service,
+				matchID:           "match-1",
+			}
+			dp.cleanupMatchData(ctx, nil, tt.failed)
+
+			ticks, err := service.GetPlayerTickDataByMatch(ctx, "match-1")
+			if err != nil {
+				t.Fatalf("Failed to read ticks: %v", err)
+			}
+			if (len(ticks) > 0) != tt.wantTicks {
+				t.Errorf("Expected tick data kept to be %v, got %d ticks", tt.wantTicks, len(ticks))
+			}
+		})
+	}
+}
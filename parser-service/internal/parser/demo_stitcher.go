@@ -0,0 +1,375 @@
+package parser
+
+import (
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StitchParsedDemos joins demos that each hold part of one match, e.g. after a
+// server crash, into a single match. Parts must be in playing order and each part
+// is expected to resume with the round after the last round completed in the part
+// before it. Round numbers continue across parts, team letters follow the players
+// and per player totals are recalculated over the whole match. The parts are
+// modified in place.
+func StitchParsedDemos(logger *logrus.Logger, parts []*types.ParsedDemoData) (*types.ParsedDemoData, error) {
+	if len(parts) == 0 {
+		return nil, types.NewParseErrorWithSeverity(types.ErrorTypeValidation, types.ErrorSeverityError, "no demos to stitch", nil)
+	}
+
+	first := parts[0]
+	if len(parts) == 1 {
+		return first, nil
+	}
+
+	last := parts[len(parts)-1]
+
+	stitched := &types.ParsedDemoData{
+		Match:             first.Match,
+		Players:           make([]types.Player, 0),
+		GunfightEvents:    make([]types.GunfightEvent, 0),
+		GrenadeEvents:     make([]types.GrenadeEvent, 0),
+		RoundEvents:       make([]types.RoundEvent, 0),
+		DamageEvents:      make([]types.DamageEvent, 0),
+		PlayerRoundEvents: make([]types.PlayerRoundEvent, 0),
+		AimEvents:         make([]types.AimAnalysisResult, 0),
+		AimWeaponEvents:   make([]types.WeaponAimAnalysisResult, 0),
+		PauseEvents:       make([]types.PauseEvent, 0),
+		Substitutions:     make([]types.Substitution, 0),
+		BombEvents:        make([]types.BombEvent, 0),
+		TeamEconomyEvents: make([]types.TeamEconomyEvent, 0),
+		ItemEvents:        make([]types.ItemEvent, 0),
+		LoadoutEvents:     make([]types.LoadoutEvent, 0),
+		TradeEvents:       make([]types.TradeEvent, 0),
+		RoundReplays:      make([]types.RoundReplay, 0),
+		Segments:          make([]types.DemoSegment, 0, len(parts)),
+
+		RoundAimEvents:       make([]types.AimAnalysisResult, 0),
+		RoundAimWeaponEvents: make([]types.WeaponAimAnalysisResult, 0),
+	}
+
+	teams := make(map[string]string)
+	playerIndex := make(map[string]int)
+	teamAWins, teamBWins := 0, 0
+	playedRounds := 0
+
+	for i, part := range parts {
+		if part.Match.Map != first.Match.Map {
+			return nil, types.NewParseErrorWithSeverity(types.ErrorTypeValidation, types.ErrorSeverityError, "demos to stitch were recorded on different maps", nil).
+				WithContext("expected_map", first.Match.Map).
+				WithContext("map", part.Match.Map).
+				WithContext("demo_index", i)
+		}
+
+		if i > 0 && teamsSwapped(teams, part.Players) {
+			swapTeamLetters(part)
+		}
+
+		rounds := completedRounds(part)
+		keepRounds := rounds
+		if part == last {
+			// Events of a round still in progress only matter at the end of the match
+			keepRounds = -1
+		}
+		appendPartEvents(stitched, part, playedRounds, keepRounds)
+
+		stitched.Segments = append(stitched.Segments, types.DemoSegment{
+			Index:      i,
+			MatchID:    part.Match.MatchID,
+			FirstRound: playedRounds + 1,
+			LastRound:  playedRounds + rounds,
+			EndTick:    roundEndTick(part, rounds),
+			Partial:    part.Partial,
+		})
+
+		a, b := teamRoundWins(part.Match)
+		teamAWins += a
+		teamBWins += b
+
+		for _, player := range part.Players {
+			if index, seen := playerIndex[player.SteamID]; seen {
+				// Keep the latest name and team of a player seen in an earlier part
+				stitched.Players[index].Name = player.Name
+				stitched.Players[index].Team = player.Team
+			} else {
+				playerIndex[player.SteamID] = len(stitched.Players)
+				stitched.Players = append(stitched.Players, player)
+			}
+			teams[player.SteamID] = player.Team
+		}
+
+		stitched.DiscardedRounds = append(stitched.DiscardedRounds, part.DiscardedRounds...)
+		stitched.Diagnostics = append(stitched.Diagnostics, part.Diagnostics...)
+
+		if i > 0 {
+			stitched.Match.DurationSeconds += part.Match.DurationSeconds
+			stitched.Match.PlaybackTicks += part.Match.PlaybackTicks
+		}
+
+		playedRounds += rounds
+	}
+
+	stitched.Match.TotalRounds = playedRounds
+	stitched.Match.EndTimestamp = last.Match.EndTimestamp
+	stitched.Match.EndTick = last.Match.EndTick
+	stitched.Match.EndTime = last.Match.EndTime
+
+	stitched.Match.WinningTeam = "A"
+	stitched.Match.WinningTeamScore = teamAWins
+	stitched.Match.LosingTeamScore = teamBWins
+	if teamBWins > teamAWins {
+		stitched.Match.WinningTeam = "B"
+		stitched.Match.WinningTeamScore = teamBWins
+		stitched.Match.LosingTeamScore = teamAWins
+	}
+
+	if last.Partial {
+		stitched.Partial = true
+		stitched.LastCompleteRound = playedRounds
+	}
+
+	recalculateMatchTotals(logger, stitched)
+
+	logger.WithFields(logrus.Fields{
+		"demos":        len(parts),
+		"map":          stitched.Match.Map,
+		"total_rounds": stitched.Match.TotalRounds,
+		"winning_team": stitched.Match.WinningTeam,
+		"partial":      stitched.Partial,
+	}).Info("Stitched demos into one match")
+
+	return stitched, nil
+}
+
+// completedRounds returns the number of rounds a part contributes to the match
+func completedRounds(part *types.ParsedDemoData) int {
+	if part.Partial {
+		return part.LastCompleteRound
+	}
+	return part.Match.TotalRounds
+}
+
+// roundEndTick returns the tick a round of a part ended, or 0 when the part has no
+// end event for it
+func roundEndTick(part *types.ParsedDemoData, roundNumber int) int64 {
+	for _, event := range part.RoundEvents {
+		if event.EventType == "end" && event.RoundNumber == roundNumber {
+			return event.TickTimestamp
+		}
+	}
+	return 0
+}
+
+// teamRoundWins returns the rounds won by team A and team B in a part
+func teamRoundWins(match types.Match) (int, int) {
+	if match.WinningTeam == "B" {
+		return match.LosingTeamScore, match.WinningTeamScore
+	}
+	return match.WinningTeamScore, match.LosingTeamScore
+}
+
+// teamsSwapped reports whether most players already known play under the other team
+// letter in players. Letters are assigned per demo from whoever starts as CT, so a
+// part recorded after the half has them reversed.
+func teamsSwapped(teams map[string]string, players []types.Player) bool {
+	same, swapped := 0, 0
+	for _, player := range players {
+		team, known := teams[player.SteamID]
+		if !known {
+			continue
+		}
+
+		if team == player.Team {
+			same++
+		} else if team == otherTeam(player.Team) {
+			swapped++
+		}
+	}
+	return swapped > same
+}
+
+// otherTeam returns the opposing team letter
+func otherTeam(team string) string {
+	switch team {
+	case "A":
+		return "B"
+	case "B":
+		return "A"
+	default:
+		return team
+	}
+}
+
+// swapTeamLetters exchanges team A and team B throughout a part
+func swapTeamLetters(part *types.ParsedDemoData) {
+	for i := range part.Players {
+		part.Players[i].Team = otherTeam(part.Players[i].Team)
+	}
+
+	part.Match.WinningTeam = otherTeam(part.Match.WinningTeam)
+
+	for i := range part.PauseEvents {
+		if part.PauseEvents[i].Team != nil {
+			team := otherTeam(*part.PauseEvents[i].Team)
+			part.PauseEvents[i].Team = &team
+		}
+	}
+
+	for i := range part.Substitutions {
+		part.Substitutions[i].Team = otherTeam(part.Substitutions[i].Team)
+	}
+
+	for i := range part.TeamEconomyEvents {
+		part.TeamEconomyEvents[i].Team = otherTeam(part.TeamEconomyEvents[i].Team)
+	}
+
+	for i := range part.RoundReplays {
+		for j := range part.RoundReplays[i].Players {
+			part.RoundReplays[i].Players[j].Team = otherTeam(part.RoundReplays[i].Players[j].Team)
+		}
+	}
+}
+
+// appendPartEvents renumbers the rounds of a part to follow the rounds already played
+// and appends its events to the stitched match. Events after round keepRounds are
+// dropped, since the round was replayed in the next part; -1 keeps every round.
+func appendPartEvents(stitched *types.ParsedDemoData, part *types.ParsedDemoData, offset int, keepRounds int) {
+	keep := func(round int) bool {
+		return keepRounds < 0 || round <= keepRounds
+	}
+
+	for _, event := range part.GunfightEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.GunfightEvents = append(stitched.GunfightEvents, event)
+		}
+	}
+
+	for _, event := range part.GrenadeEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.GrenadeEvents = append(stitched.GrenadeEvents, event)
+		}
+	}
+
+	for _, event := range part.RoundEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.RoundEvents = append(stitched.RoundEvents, event)
+		}
+	}
+
+	for _, event := range part.DamageEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.DamageEvents = append(stitched.DamageEvents, event)
+		}
+	}
+
+	for _, event := range part.BombEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.BombEvents = append(stitched.BombEvents, event)
+		}
+	}
+
+	for _, event := range part.TeamEconomyEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.TeamEconomyEvents = append(stitched.TeamEconomyEvents, event)
+		}
+	}
+
+	for _, event := range part.ItemEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.ItemEvents = append(stitched.ItemEvents, event)
+		}
+	}
+
+	for _, event := range part.LoadoutEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.LoadoutEvents = append(stitched.LoadoutEvents, event)
+		}
+	}
+
+	for _, event := range part.TradeEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.TradeEvents = append(stitched.TradeEvents, event)
+		}
+	}
+
+	for _, replay := range part.RoundReplays {
+		if keep(replay.RoundNumber) {
+			replay.RoundNumber += offset
+			stitched.RoundReplays = append(stitched.RoundReplays, replay)
+		}
+	}
+
+	for _, event := range part.PlayerRoundEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.PlayerRoundEvents = append(stitched.PlayerRoundEvents, event)
+		}
+	}
+
+	for _, event := range part.PauseEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.PauseEvents = append(stitched.PauseEvents, event)
+		}
+	}
+
+	for _, substitution := range part.Substitutions {
+		if keep(substitution.RoundNumber) {
+			substitution.RoundNumber += offset
+			stitched.Substitutions = append(stitched.Substitutions, substitution)
+		}
+	}
+
+	// The match level aim results of the part still include dropped rounds, so the
+	// stitched ones are aggregated again from the per round results
+	for _, event := range part.RoundAimEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.RoundAimEvents = append(stitched.RoundAimEvents, event)
+		}
+	}
+
+	for _, event := range part.RoundAimWeaponEvents {
+		if keep(event.RoundNumber) {
+			event.RoundNumber += offset
+			stitched.RoundAimWeaponEvents = append(stitched.RoundAimWeaponEvents, event)
+		}
+	}
+}
+
+// recalculateMatchTotals rebuilds the per player match totals, aim results and
+// achievements of a stitched match from its combined events
+func recalculateMatchTotals(logger *logrus.Logger, stitched *types.ParsedDemoData) {
+	matchState := &types.MatchState{
+		CurrentRound:      stitched.Match.TotalRounds,
+		TotalRounds:       stitched.Match.TotalRounds,
+		MapName:           stitched.Match.Map,
+		Players:           make(map[string]*types.Player, len(stitched.Players)),
+		RoundEvents:       stitched.RoundEvents,
+		GunfightEvents:    stitched.GunfightEvents,
+		GrenadeEvents:     stitched.GrenadeEvents,
+		DamageEvents:      stitched.DamageEvents,
+		PlayerRoundEvents: stitched.PlayerRoundEvents,
+		PlayerMatchEvents: make([]types.PlayerMatchEvent, 0),
+	}
+	for i := range stitched.Players {
+		matchState.Players[stitched.Players[i].SteamID] = &stitched.Players[i]
+	}
+
+	processor := NewEventProcessor(matchState, logger, nil, nil)
+	processor.playerMatchHandler.aggregatePlayerMatchEvent()
+
+	stitched.PlayerMatchEvents = matchState.PlayerMatchEvents
+	stitched.AimEvents = processor.aggregateAimEvents(stitched.RoundAimEvents)
+	stitched.AimWeaponEvents = processor.aggregateWeaponAimEvents(stitched.RoundAimWeaponEvents)
+	stitched.Achievements = calculateAchievements(stitched.PlayerMatchEvents, stitched.AimEvents)
+}
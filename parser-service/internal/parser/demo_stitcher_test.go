@@ -0,0 +1,225 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stitchTestPart builds a part where players 1 and 2 are on firstTeam and players
+// 3 and 4 on the other team, with player 1 killing player 3 in every round
+func stitchTestPart(matchID string, firstTeam string, rounds int, winningTeam string, winningScore int, losingScore int) *types.ParsedDemoData {
+	part := &types.ParsedDemoData{
+		Match: types.Match{
+			MatchID:          matchID,
+			Map:              "de_inferno",
+			WinningTeam:      winningTeam,
+			WinningTeamScore: winningScore,
+			LosingTeamScore:  losingScore,
+			TotalRounds:      rounds,
+			DurationSeconds:  100,
+			PlaybackTicks:    1000,
+		},
+		Players: []types.Player{
+			{SteamID: "1", Name: "one", Team: firstTeam},
+			{SteamID: "2", Name: "two", Team: firstTeam},
+			{SteamID: "3", Name: "three", Team: otherTeam(firstTeam)},
+			{SteamID: "4", Name: "four", Team: otherTeam(firstTeam)},
+		},
+	}
+
+	for round := 1; round <= rounds; round++ {
+		part.RoundEvents = append(part.RoundEvents,
+			types.RoundEvent{RoundNumber: round, EventType: "start", TickTimestamp: int64(round*100 - 90)},
+			types.RoundEvent{RoundNumber: round, EventType: "end", TickTimestamp: int64(round * 100)})
+		part.GunfightEvents = append(part.GunfightEvents, types.GunfightEvent{
+			RoundNumber:    round,
+			Player1SteamID: "1",
+			Player2SteamID: "3",
+			VictorSteamID:  stringPtr("1"),
+			Player1HPStart: 100,
+			Player2HPStart: 100,
+			DamageDealt:    100,
+		})
+		for _, player := range part.Players {
+			event := types.PlayerRoundEvent{PlayerSteamID: player.SteamID, RoundNumber: round}
+			if player.SteamID == "1" {
+				event.Kills = 1
+				event.Damage = 100
+			}
+			if player.SteamID == "3" {
+				event.Died = true
+			}
+			part.PlayerRoundEvents = append(part.PlayerRoundEvents, event)
+		}
+	}
+
+	return part
+}
+
+func TestStitchParsedDemos(t *testing.T) {
+	// The server crashed after round 5; the second demo starts after the half, so
+	// letters were assigned the other way round
+	first := stitchTestPart("match-1", "A", 5, "A", 3, 2)
+	second := stitchTestPart("match-2", "B", 4, "A", 3, 1)
+	pauseTeam := "A"
+	second.PauseEvents = []types.PauseEvent{{RoundNumber: 2, PauseType: "tactical", Team: &pauseTeam}}
+
+	stitched, err := StitchParsedDemos(logrus.New(), []*types.ParsedDemoData{first, second})
+	require.NoError(t, err)
+
+	assert.Equal(t, 9, stitched.Match.TotalRounds)
+	assert.Equal(t, "match-1", stitched.Match.MatchID)
+	assert.Equal(t, 200.0, stitched.Match.DurationSeconds)
+	assert.Equal(t, 2000, stitched.Match.PlaybackTicks)
+
+	// Team A of the first demo won 3 rounds there and lost 3 in the second
+	assert.Equal(t, "B", stitched.Match.WinningTeam)
+	assert.Equal(t, 5, stitched.Match.WinningTeamScore)
+	assert.Equal(t, 4, stitched.Match.LosingTeamScore)
+
+	require.Len(t, stitched.Players, 4)
+	for _, player := range stitched.Players {
+		if player.SteamID == "1" || player.SteamID == "2" {
+			assert.Equal(t, "A", player.Team)
+		} else {
+			assert.Equal(t, "B", player.Team)
+		}
+	}
+
+	require.Len(t, stitched.PauseEvents, 1)
+	assert.Equal(t, 7, stitched.PauseEvents[0].RoundNumber)
+	assert.Equal(t, "B", *stitched.PauseEvents[0].Team)
+
+	require.Len(t, stitched.GunfightEvents, 9)
+	for i, gunfight := range stitched.GunfightEvents {
+		assert.Equal(t, i+1, gunfight.RoundNumber)
+	}
+
+	require.Len(t, stitched.Segments, 2)
+	assert.Equal(t, types.DemoSegment{Index: 0, MatchID: "match-1", FirstRound: 1, LastRound: 5, EndTick: 500}, stitched.Segments[0])
+	assert.Equal(t, types.DemoSegment{Index: 1, MatchID: "match-2", FirstRound: 6, LastRound: 9, EndTick: 400}, stitched.Segments[1])
+
+	var killer *types.PlayerMatchEvent
+	for i := range stitched.PlayerMatchEvents {
+		if stitched.PlayerMatchEvents[i].PlayerSteamID == "1" {
+			killer = &stitched.PlayerMatchEvents[i]
+		}
+	}
+	require.NotNil(t, killer)
+	assert.Equal(t, 9, killer.Kills)
+	assert.Equal(t, 9, killer.RoundsPlayed)
+}
+
+func TestStitchParsedDemos_DropsReplayedRound(t *testing.T) {
+	// The first demo ended with round 4 in progress, which is replayed in the second
+	first := stitchTestPart("match-1", "A", 4, "A", 2, 2)
+	first.Match.TotalRounds = 3
+	first.Partial = true
+	first.LastCompleteRound = 3
+
+	second := stitchTestPart("match-2", "A", 2, "A", 2, 0)
+	second.Partial = true
+	second.LastCompleteRound = 2
+
+	stitched, err := StitchParsedDemos(logrus.New(), []*types.ParsedDemoData{first, second})
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, stitched.Match.TotalRounds)
+	assert.Len(t, stitched.GunfightEvents, 5)
+	assert.True(t, stitched.Partial)
+	assert.Equal(t, 5, stitched.LastCompleteRound)
+	assert.True(t, stitched.Segments[0].Partial)
+	// Stored data of the replayed round is cut at the end of round 3
+	assert.Equal(t, int64(300), stitched.Segments[0].EndTick)
+}
+
+func TestStitchParsedDemos_AimLeavesOutReplayedRound(t *testing.T) {
+	// The first demo ended with round 3 in progress, which is replayed in the second
+	first := stitchTestPart("match-1", "A", 3, "A", 1, 1)
+	first.Match.TotalRounds = 2
+	first.Partial = true
+	first.LastCompleteRound = 2
+	first.RoundAimEvents = []types.AimAnalysisResult{
+		{PlayerSteamID: "1", RoundNumber: 1, ShotsFired: 10, ShotsHit: 5, AverageCrosshairPlacementX: 2},
+		{PlayerSteamID: "1", RoundNumber: 2, ShotsFired: 10, ShotsHit: 5, AverageCrosshairPlacementX: 2},
+		{PlayerSteamID: "1", RoundNumber: 3, ShotsFired: 20, ShotsHit: 0, AverageCrosshairPlacementX: 50},
+	}
+	first.RoundAimWeaponEvents = []types.WeaponAimAnalysisResult{
+		{PlayerSteamID: "1", RoundNumber: 2, WeaponName: "ak47", ShotsFired: 10, ShotsHit: 5},
+		{PlayerSteamID: "1", RoundNumber: 3, WeaponName: "ak47", ShotsFired: 20, ShotsHit: 0},
+	}
+	// The match level results of the part already include the replayed round
+	first.AimEvents = []types.AimAnalysisResult{{PlayerSteamID: "1", ShotsFired: 40, ShotsHit: 10}}
+
+	second := stitchTestPart("match-2", "A", 1, "A", 1, 0)
+	second.RoundAimEvents = []types.AimAnalysisResult{
+		{PlayerSteamID: "1", RoundNumber: 1, ShotsFired: 20, ShotsHit: 10, AverageCrosshairPlacementX: 5},
+	}
+	second.RoundAimWeaponEvents = []types.WeaponAimAnalysisResult{
+		{PlayerSteamID: "1", RoundNumber: 1, WeaponName: "ak47", ShotsFired: 20, ShotsHit: 10},
+	}
+
+	stitched, err := StitchParsedDemos(logrus.New(), []*types.ParsedDemoData{first, second})
+	require.NoError(t, err)
+
+	require.Len(t, stitched.RoundAimEvents, 3)
+	assert.Equal(t, 3, stitched.RoundAimEvents[2].RoundNumber)
+
+	require.Len(t, stitched.AimEvents, 1)
+	assert.Equal(t, 40, stitched.AimEvents[0].ShotsFired)
+	assert.Equal(t, 20, stitched.AimEvents[0].ShotsHit)
+	assert.InDelta(t, 50.0, stitched.AimEvents[0].AccuracyAllShots, 0.001)
+	// Weighted by shots fired: (10*2 + 10*2 + 20*5) / 40
+	assert.InDelta(t, 3.5, stitched.AimEvents[0].AverageCrosshairPlacementX, 0.001)
+
+	require.Len(t, stitched.AimWeaponEvents, 1)
+	assert.Equal(t, 30, stitched.AimWeaponEvents[0].ShotsFired)
+	assert.Equal(t, 15, stitched.AimWeaponEvents[0].ShotsHit)
+}
+
+func TestStitchParsedDemos_DifferentMaps(t *testing.T) {
+	first := stitchTestPart("match-1", "A", 3, "A", 2, 1)
+	second := stitchTestPart("match-2", "A", 3, "A", 2, 1)
+	second.Match.Map = "de_nuke"
+
+	_, err := StitchParsedDemos(logrus.New(), []*types.ParsedDemoData{first, second})
+	assert.Error(t, err)
+
+	_, err = StitchParsedDemos(logrus.New(), nil)
+	assert.Error(t, err)
+}
+
+func TestAggregateSeries(t *testing.T) {
+	// Map two has the letters reversed; team "A" of map one wins both maps
+	mapOne := stitchTestPart("map-1", "A", 3, "A", 2, 1)
+	mapOne.PlayerMatchEvents = []types.PlayerMatchEvent{
+		{PlayerSteamID: "1", RoundsPlayed: 3, Kills: 3, Damage: 300, TotalImpact: 30},
+		{PlayerSteamID: "3", RoundsPlayed: 3, Deaths: 3},
+	}
+	mapTwo := stitchTestPart("map-2", "B", 2, "B", 2, 0)
+	mapTwo.PlayerMatchEvents = []types.PlayerMatchEvent{
+		{PlayerSteamID: "1", RoundsPlayed: 2, Kills: 2, Damage: 200, TotalImpact: 20},
+	}
+
+	series := AggregateSeries([]*types.ParsedDemoData{mapOne, mapTwo})
+
+	assert.Equal(t, 2, series.TeamAMapWins)
+	assert.Equal(t, 0, series.TeamBMapWins)
+	assert.Equal(t, "A", series.WinningTeam)
+	assert.Equal(t, "A", mapTwo.Match.WinningTeam, "later maps are aligned to the first")
+	require.Len(t, series.Players, 4)
+
+	player := series.Players[0]
+	assert.Equal(t, "1", player.PlayerSteamID)
+	assert.Equal(t, "A", player.Team)
+	assert.Equal(t, 2, player.MapsPlayed)
+	assert.Equal(t, 5, player.RoundsPlayed)
+	assert.Equal(t, 5, player.Kills)
+	assert.Equal(t, 100.0, player.ADR)
+	assert.Equal(t, 10.0, player.AverageImpact)
+}
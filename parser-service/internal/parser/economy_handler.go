@@ -0,0 +1,257 @@
+package parser
+
+import (
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/sirupsen/logrus"
+)
+
+// Game rule properties holding the losses counted towards each side's loss bonus
+var lossBonusProperties = map[string]string{
+	"CT": "m_pGameRules.m_iNumConsecutiveCTLoses",
+	"T":  "m_pGameRules.m_iNumConsecutiveTerroristLoses",
+}
+
+// playerEconomy is a player's money and equipment as reported by the game
+type playerEconomy struct {
+	steamID                 string
+	side                    string
+	money                   int
+	moneySpent              int
+	roundStartEquipment     int
+	freezeEndEquipmentValue int
+}
+
+// EconomyHandler records each team's economy per round. A snapshot is taken when
+// the buy phase ends and completed with the round's total spend when it ends.
+type EconomyHandler struct {
+	processor *EventProcessor
+	logger    *logrus.Logger
+
+	pending    map[string]*types.TeamEconomyEvent // Round in progress by side
+	lossLevels map[string]int                     // Tracked loss bonus level by side, used when the game rules do not report it
+}
+
+// NewEconomyHandler creates a new economy handler
+func NewEconomyHandler(processor *EventProcessor, logger *logrus.Logger) *EconomyHandler {
+	return &EconomyHandler{
+		processor:  processor,
+		logger:     logger,
+		lossLevels: make(map[string]int),
+	}
+}
+
+// HandleFreezetimeEnd snapshots the money and equipment of both teams after buying
+func (eh *EconomyHandler) HandleFreezetimeEnd() {
+	eh.recordFreezeEnd(eh.playingEconomies(), eh.reportedLossLevels())
+}
+
+// ProcessRoundEnd completes the round's economy with purchases made after freeze time
+func (eh *EconomyHandler) ProcessRoundEnd(winnerSide string) {
+	eh.recordRoundEnd(eh.playingEconomies(), winnerSide)
+}
+
+// recordFreezeEnd builds the team economy of the round in progress. reported holds
+// the loss bonus levels read from the game, by side.
+func (eh *EconomyHandler) recordFreezeEnd(players []playerEconomy, reported map[string]int) {
+	ep := eh.processor
+	round := ep.matchState.CurrentRound
+
+	if ep.matchRules.IsEconomyResetRound(round) {
+		eh.lossLevels = make(map[string]int)
+	}
+
+	eh.pending = make(map[string]*types.TeamEconomyEvent)
+	teamVotes := make(map[string]map[string]int)
+
+	for _, player := range players {
+		if player.side != "CT" && player.side != "T" {
+			continue
+		}
+
+		event, exists := eh.pending[player.side]
+		if !exists {
+			event = &types.TeamEconomyEvent{RoundNumber: round, Side: player.side}
+			eh.pending[player.side] = event
+			teamVotes[player.side] = make(map[string]int)
+		}
+
+		// Money spent so far is added back to get the money before buying
+		event.Players++
+		event.StartMoney += player.money + player.moneySpent
+		event.MoneySpent += player.moneySpent
+		event.SavedEquipmentValue += player.roundStartEquipment
+		event.FreezeEndEquipmentValue += player.freezeEndEquipmentValue
+
+		if team, assigned := ep.teamAssignments[player.steamID]; assigned {
+			teamVotes[player.side][team]++
+		}
+	}
+
+	for side, event := range eh.pending {
+		event.Team = majorityTeam(teamVotes[side])
+
+		level, reportedLevel := reported[side]
+		if !reportedLevel {
+			level = eh.lossLevels[side]
+		}
+		event.LossBonusLevel = clampLossBonusLevel(level)
+		event.LossBonus = types.BaseLossBonus + types.LossBonusStep*event.LossBonusLevel
+	}
+}
+
+// recordRoundEnd stores the team economies of the round that ended
+func (eh *EconomyHandler) recordRoundEnd(players []playerEconomy, winnerSide string) {
+	ep := eh.processor
+	if len(eh.pending) == 0 {
+		return
+	}
+
+	// The buy time outlasts freeze time, so the spend is read again at the end
+	spent := make(map[string]int)
+	for _, player := range players {
+		spent[player.side] += player.moneySpent
+	}
+
+	for _, side := range []string{"CT", "T"} {
+		event, exists := eh.pending[side]
+		if !exists {
+			continue
+		}
+
+		if spent[side] > event.MoneySpent {
+			event.MoneySpent = spent[side]
+		}
+		event.BuyType = eh.classifyBuy(event)
+
+		ep.matchState.TeamEconomyEvents = append(ep.matchState.TeamEconomyEvents, *event)
+
+		eh.logger.WithFields(logrus.Fields{
+			"round":       event.RoundNumber,
+			"side":        side,
+			"start_money": event.StartMoney,
+			"spent":       event.MoneySpent,
+			"buy_type":    event.BuyType,
+		}).Debug("Team economy recorded")
+
+		// A win lowers the loss bonus by one step, a loss raises it
+		if side == winnerSide {
+			eh.lossLevels[side] = clampLossBonusLevel(event.LossBonusLevel - 1)
+		} else if winnerSide == "CT" || winnerSide == "T" {
+			eh.lossLevels[side] = clampLossBonusLevel(event.LossBonusLevel + 1)
+		}
+	}
+
+	eh.pending = nil
+}
+
+// classifyBuy determines the team buy type from the average equipment value per
+// player after buying and the money the team kept back
+func (eh *EconomyHandler) classifyBuy(event *types.TeamEconomyEvent) string {
+	if eh.processor.matchRules.IsPistolRound(event.RoundNumber) {
+		return types.TeamBuyTypePistol
+	}
+
+	if event.Players == 0 {
+		return types.TeamBuyTypeEco
+	}
+
+	averageEquipment := event.FreezeEndEquipmentValue / event.Players
+	averageMoneyLeft := (event.StartMoney - event.MoneySpent) / event.Players
+
+	switch {
+	case averageEquipment <= types.EcoThreshold:
+		return types.TeamBuyTypeEco
+	case averageEquipment > types.ForceBuyThreshold:
+		return types.TeamBuyTypeFullBuy
+	case averageMoneyLeft >= types.HalfBuyMoneyLeftThreshold:
+		return types.TeamBuyTypeHalfBuy
+	default:
+		return types.TeamBuyTypeForce
+	}
+}
+
+// playingEconomies reads the money and equipment of every player on a team
+func (eh *EconomyHandler) playingEconomies() []playerEconomy {
+	ep := eh.processor
+	if ep.demoParser == nil {
+		return nil
+	}
+
+	gameState := ep.demoParser.GameState()
+	if gameState == nil {
+		return nil
+	}
+
+	playing := gameState.Participants().Playing()
+	economies := make([]playerEconomy, 0, len(playing))
+	for _, player := range playing {
+		if player == nil {
+			continue
+		}
+		economies = append(economies, eh.readPlayerEconomy(player))
+	}
+	return economies
+}
+
+func (eh *EconomyHandler) readPlayerEconomy(player *common.Player) playerEconomy {
+	// The freeze time value is not always set when the buy phase ends
+	freezeEndValue := player.EquipmentValueFreezeTimeEnd()
+	if freezeEndValue == 0 {
+		freezeEndValue = player.EquipmentValueCurrent()
+	}
+
+	return playerEconomy{
+		steamID:                 types.SteamIDToString(player.SteamID64),
+		side:                    eh.processor.getTeamString(player.Team),
+		money:                   player.Money(),
+		moneySpent:              player.MoneySpentThisRound(),
+		roundStartEquipment:     player.EquipmentValueRoundStart(),
+		freezeEndEquipmentValue: freezeEndValue,
+	}
+}
+
+// reportedLossLevels reads the loss bonus levels from the game rules, by side
+func (eh *EconomyHandler) reportedLossLevels() map[string]int {
+	levels := make(map[string]int)
+
+	ep := eh.processor
+	if ep.demoParser == nil {
+		return levels
+	}
+
+	gameState := ep.demoParser.GameState()
+	if gameState == nil || gameState.Rules() == nil || gameState.Rules().Entity() == nil {
+		return levels
+	}
+
+	entity := gameState.Rules().Entity()
+	for side, property := range lossBonusProperties {
+		if value, exists := entity.PropertyValue(property); exists && value.Any != nil {
+			levels[side] = value.Int()
+		}
+	}
+	return levels
+}
+
+// majorityTeam returns the team letter most players on a side are assigned to
+func majorityTeam(votes map[string]int) string {
+	team, most := "", 0
+	for _, candidate := range []string{"A", "B"} {
+		if votes[candidate] > most {
+			team, most = candidate, votes[candidate]
+		}
+	}
+	return team
+}
+
+func clampLossBonusLevel(level int) int {
+	if level < 0 {
+		return 0
+	}
+	if level > types.MaxLossBonusLevel {
+		return types.MaxLossBonusLevel
+	}
+	return level
+}
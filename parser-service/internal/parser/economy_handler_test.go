@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEconomyTestProcessor(round int) *EventProcessor {
+	matchState := &types.MatchState{
+		Players:           make(map[string]*types.Player),
+		CurrentRound:      round,
+		TeamEconomyEvents: make([]types.TeamEconomyEvent, 0),
+	}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+	for _, steamID := range []string{"ct1", "ct2"} {
+		processor.teamAssignments[steamID] = "A"
+	}
+	for _, steamID := range []string{"t1", "t2"} {
+		processor.teamAssignments[steamID] = "B"
+	}
+	return processor
+}
+
+func TestEconomyHandler_RecordsTeamEconomy(t *testing.T) {
+	processor := newEconomyTestProcessor(2)
+	handler := processor.economyHandler
+
+	handler.recordFreezeEnd([]playerEconomy{
+		{steamID: "ct1", side: "CT", money: 3000, moneySpent: 800, roundStartEquipment: 200, freezeEndEquipmentValue: 1000},
+		{steamID: "ct2", side: "CT", money: 2900, moneySpent: 700, roundStartEquipment: 200, freezeEndEquipmentValue: 900},
+		{steamID: "t1", side: "T", money: 200, moneySpent: 4700, roundStartEquipment: 0, freezeEndEquipmentValue: 4700},
+		{steamID: "t2", side: "T", money: 100, moneySpent: 4800, roundStartEquipment: 0, freezeEndEquipmentValue: 4800},
+	}, map[string]int{})
+
+	// The terrorists buy a grenade once freeze time is over
+	handler.recordRoundEnd([]playerEconomy{
+		{steamID: "ct1", side: "CT", moneySpent: 800},
+		{steamID: "ct2", side: "CT", moneySpent: 700},
+		{steamID: "t1", side: "T", moneySpent: 5000},
+		{steamID: "t2", side: "T", moneySpent: 4800},
+	}, "T")
+
+	economies := processor.matchState.TeamEconomyEvents
+	require.Len(t, economies, 2)
+
+	ct := economies[0]
+	assert.Equal(t, "CT", ct.Side)
+	assert.Equal(t, "A", ct.Team)
+	assert.Equal(t, 2, ct.Players)
+	assert.Equal(t, 7400, ct.StartMoney)
+	assert.Equal(t, 1500, ct.MoneySpent)
+	assert.Equal(t, 400, ct.SavedEquipmentValue)
+	assert.Equal(t, 1900, ct.FreezeEndEquipmentValue)
+	assert.Equal(t, types.TeamBuyTypeEco, ct.BuyType)
+	assert.Equal(t, 0, ct.LossBonusLevel)
+	assert.Equal(t, types.BaseLossBonus, ct.LossBonus)
+
+	tSide := economies[1]
+	assert.Equal(t, "B", tSide.Team)
+	assert.Equal(t, 9800, tSide.MoneySpent)
+	assert.Equal(t, types.TeamBuyTypeFullBuy, tSide.BuyType)
+
+	// The losing side moves up a loss bonus step for the next round
+	processor.matchState.CurrentRound = 3
+	handler.recordFreezeEnd([]playerEconomy{
+		{steamID: "ct1", side: "CT", money: 2000, freezeEndEquipmentValue: 2500},
+		{steamID: "t1", side: "T", money: 2000, freezeEndEquipmentValue: 2500},
+	}, map[string]int{})
+	handler.recordRoundEnd(nil, "CT")
+
+	economies = processor.matchState.TeamEconomyEvents
+	require.Len(t, economies, 4)
+	assert.Equal(t, 1, economies[2].LossBonusLevel)
+	assert.Equal(t, types.BaseLossBonus+types.LossBonusStep, economies[2].LossBonus)
+	assert.Equal(t, 0, economies[3].LossBonusLevel)
+}
+
+func TestEconomyHandler_PrefersReportedLossBonus(t *testing.T) {
+	processor := newEconomyTestProcessor(5)
+	handler := processor.economyHandler
+
+	handler.recordFreezeEnd([]playerEconomy{
+		{steamID: "ct1", side: "CT", freezeEndEquipmentValue: 4000},
+		{steamID: "t1", side: "T", freezeEndEquipmentValue: 4000},
+	}, map[string]int{"CT": 3, "T": 7})
+	handler.recordRoundEnd(nil, "T")
+
+	economies := processor.matchState.TeamEconomyEvents
+	require.Len(t, economies, 2)
+	assert.Equal(t, 3, economies[0].LossBonusLevel)
+	assert.Equal(t, types.BaseLossBonus+3*types.LossBonusStep, economies[0].LossBonus)
+	assert.Equal(t, types.MaxLossBonusLevel, economies[1].LossBonusLevel)
+}
+
+func TestEconomyHandler_ResetsLossBonusOnPistolRound(t *testing.T) {
+	processor := newEconomyTestProcessor(12)
+	handler := processor.economyHandler
+	handler.lossLevels["CT"] = 3
+
+	processor.matchState.CurrentRound = 13
+	handler.recordFreezeEnd([]playerEconomy{
+		{steamID: "ct1", side: "CT", money: 800, freezeEndEquipmentValue: 1000},
+	}, map[string]int{})
+	handler.recordRoundEnd(nil, "CT")
+
+	economies := processor.matchState.TeamEconomyEvents
+	require.Len(t, economies, 1)
+	assert.Equal(t, 0, economies[0].LossBonusLevel)
+	assert.Equal(t, types.TeamBuyTypePistol, economies[0].BuyType)
+}
+
+func TestEconomyHandler_ClassifyBuy(t *testing.T) {
+	handler := newEconomyTestProcessor(5).economyHandler
+
+	tests := []struct {
+		name     string
+		event    types.TeamEconomyEvent
+		expected string
+	}{
+		{"eco", types.TeamEconomyEvent{RoundNumber: 5, Players: 5, StartMoney: 10000, MoneySpent: 2000, FreezeEndEquipmentValue: 5000}, types.TeamBuyTypeEco},
+		{"force", types.TeamEconomyEvent{RoundNumber: 5, Players: 5, StartMoney: 15000, MoneySpent: 14000, FreezeEndEquipmentValue: 15000}, types.TeamBuyTypeForce},
+		{"half buy", types.TeamEconomyEvent{RoundNumber: 5, Players: 5, StartMoney: 25000, MoneySpent: 12000, FreezeEndEquipmentValue: 15000}, types.TeamBuyTypeHalfBuy},
+		{"full buy", types.TeamEconomyEvent{RoundNumber: 5, Players: 5, StartMoney: 30000, MoneySpent: 24000, FreezeEndEquipmentValue: 25000}, types.TeamBuyTypeFullBuy},
+		{"pistol", types.TeamEconomyEvent{RoundNumber: 1, Players: 5, StartMoney: 4000, MoneySpent: 3000, FreezeEndEquipmentValue: 4000}, types.TeamBuyTypePistol},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, handler.classifyBuy(&tt.event))
+		})
+	}
+}
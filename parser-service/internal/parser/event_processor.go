@@ -22,26 +22,48 @@ type EventProcessor struct {
 	playerStates map[uint64]*types.PlayerState
 	perfLogger   *utils.PerformanceLogger
 
-	teamAssignments    map[string]string
-	teamAWins          int
-	teamBWins          int
-	teamAStartedAs     string
-	teamBStartedAs     string
-	teamACurrentSide   string
-	teamBCurrentSide   string
-	assignmentComplete bool
-	currentRound       int
-	currentTick        int64
+	teamAssignments  map[string]string
+	teamAWins        int
+	teamBWins        int
+	roundWinners     map[int]string // Team letter that won each round
+	teamAStartedAs   string
+	teamBStartedAs   string
+	teamACurrentSide string
+	teamBCurrentSide string
+	matchRules       MatchRules
+	roundValidity    *RoundValidityTracker
+	pauseTracker     *PauseTracker
+	roster           *RosterTracker
+	currentRound     int
+	currentTick      int64
+
+	// timing is shared with every handler and updated in place once the demo
+	// reports its tick interval
+	timing *types.TickTiming
 
 	demoParser demoinfocs.Parser
 
+	// Map of the demo and the zones used to name positions on it
+	mapName string
+	places  *PlaceResolver
+
 	grenadeThrows map[int]*types.GrenadeThrowInfo
 
 	activeFlashEffects map[int]*FlashEffect
 
+	// Players on a team during the round in progress, and the humans controlling bots
+	roundParticipants map[string]bool
+	botControllers    map[*common.Player]*common.Player
+
 	grenadeHandler     *GrenadeHandler
 	gunfightHandler    *GunfightHandler
 	damageHandler      *DamageHandler
+	bombHandler        *BombHandler
+	economyHandler     *EconomyHandler
+	itemHandler        *ItemHandler
+	loadoutHandler     *LoadoutHandler
+	tradeHandler       *TradeHandler
+	replayHandler      *ReplayHandler
 	matchHandler       *MatchHandler
 	roundHandler       *RoundHandler
 	playerMatchHandler *PlayerMatchHandler
@@ -49,8 +71,8 @@ type EventProcessor struct {
 	rankExtractor      *RankExtractor
 	playerTickService  *database.PlayerTickService
 	roundTickCache     *RoundTickCache
+	tickWriter         *database.AsyncTickWriter
 	matchID            string
-	isFaceitMatch      bool // Track if this is a FACEIT match to skip first round
 
 	// Aim tracking results storage
 	aimEvents       []types.AimAnalysisResult
@@ -85,25 +107,40 @@ func NewEventProcessor(matchState *types.MatchState, logger *logrus.Logger, cfg
 		perfLogger:   perfLogger,
 		playerStates: make(map[uint64]*types.PlayerState),
 
-		teamAssignments:    make(map[string]string),
-		teamAWins:          0,
-		teamBWins:          0,
-		teamAStartedAs:     "",
-		teamBStartedAs:     "",
-		teamACurrentSide:   "",
-		teamBCurrentSide:   "",
-		assignmentComplete: false,
-		currentRound:       0,
-		currentTick:        0,
+		teamAssignments:  make(map[string]string),
+		teamAWins:        0,
+		teamBWins:        0,
+		roundWinners:     make(map[int]string),
+		teamAStartedAs:   "",
+		teamBStartedAs:   "",
+		teamACurrentSide: "",
+		teamBCurrentSide: "",
+		matchRules:       DefaultMatchRules(),
+		roundValidity:    NewRoundValidityTracker(),
+		pauseTracker:     NewPauseTracker(),
+		roster:           NewRosterTracker(),
+		currentRound:     0,
+		currentTick:      0,
+
+		timing: types.NewTickTiming(types.DefaultTickRate),
 
 		grenadeThrows: make(map[int]*types.GrenadeThrowInfo),
 
 		activeFlashEffects: make(map[int]*FlashEffect),
+
+		roundParticipants: make(map[string]bool),
+		botControllers:    make(map[*common.Player]*common.Player),
 	}
 
 	ep.grenadeHandler = NewGrenadeHandler(ep, logger)
 	ep.gunfightHandler = NewGunfightHandler(ep, logger)
 	ep.damageHandler = NewDamageHandler(ep, logger)
+	ep.bombHandler = NewBombHandler(ep, logger)
+	ep.economyHandler = NewEconomyHandler(ep, logger)
+	ep.itemHandler = NewItemHandler(ep, logger)
+	ep.loadoutHandler = NewLoadoutHandler(ep, logger)
+	ep.tradeHandler = NewTradeHandler(ep, logger)
+	ep.replayHandler = NewReplayHandler(ep, logger)
 	ep.matchHandler = NewMatchHandler(ep, logger)
 	ep.roundHandler = NewRoundHandler(ep, logger)
 	ep.playerMatchHandler = NewPlayerMatchHandler(ep, logger)
@@ -117,13 +154,34 @@ func (ep *EventProcessor) SetDemoParser(parser demoinfocs.Parser) {
 	ep.demoParser = parser
 }
 
+// SetTickRate updates the timing context shared by all handlers
+func (ep *EventProcessor) SetTickRate(tickRate float64) {
+	*ep.timing = *types.NewTickTiming(tickRate)
+}
+
+// Timing returns the tick timing context of the demo being parsed
+func (ep *EventProcessor) Timing() *types.TickTiming {
+	if ep == nil {
+		return nil
+	}
+	return ep.timing
+}
+
 func (ep *EventProcessor) SetPlayerTickService(service *database.PlayerTickService) {
 	ep.playerTickService = service
 }
 
+func (ep *EventProcessor) SetTickWriter(writer *database.AsyncTickWriter) {
+	ep.tickWriter = writer
+}
+
 func (ep *EventProcessor) InitializeRoundTickCache(matchID string) {
 	if ep.playerTickService != nil {
 		ep.roundTickCache = NewRoundTickCache(ep.playerTickService, ep.logger, matchID)
+		ep.roundTickCache.SetTickTiming(ep.timing)
+		if ep.tickWriter != nil {
+			ep.roundTickCache.SetTickWriter(ep.tickWriter)
+		}
 	}
 }
 
@@ -131,41 +189,72 @@ func (ep *EventProcessor) SetMatchID(matchID string) {
 	ep.matchID = matchID
 }
 
-func (ep *EventProcessor) SetIsFaceitMatch(isFaceit bool) {
-	ep.isFaceitMatch = isFaceit
-}
-
-// shouldSkipCurrentRound returns true if we should skip processing events for the current round
-// This is used to skip the first round for FACEIT matches
+// shouldSkipCurrentRound returns true while the round in progress does not count
+// towards the match: warmup, knife rounds and rounds cut short by a restart
 func (ep *EventProcessor) shouldSkipCurrentRound() bool {
-	return ep.isFaceitMatch && ep.currentRound == 1
+	if ep.roundValidity != nil && !ep.roundValidity.IsLive() {
+		return true
+	}
+	return ep.isWarmupPeriod()
 }
 
 func (ep *EventProcessor) HandleRoundStart(e events.RoundStart) error {
-	// Skip first round for FACEIT matches
-	if ep.isFaceitMatch && ep.currentRound == 0 {
-		// Increment both currentRound and matchState.CurrentRound to keep them in sync
-		// but skip all processing for this round
-		ep.currentRound++
-		if ep.matchState != nil {
-			ep.matchState.CurrentRound++
+	if ep.roundValidity != nil {
+		decision := ep.roundValidity.RoundStarted(ep.currentTick, ep.isWarmupPeriod(), ep.totalTeamScore())
+		if decision.DiscardPrevious {
+			ep.logger.WithField("round", ep.currentRound).Info("Discarding round that was interrupted before it ended")
+			ep.discardCurrentRound()
+		}
+		if decision.Restart {
+			ep.logger.WithField("rounds", ep.currentRound).Info("Scores were reset, restarting match")
+			ep.restartMatch(false)
+		}
+		if decision.SupersededRounds > 0 {
+			ep.logger.WithFields(logrus.Fields{
+				"rounds":     decision.SupersededRounds,
+				"last_round": ep.currentRound,
+			}).Info("Scores rolled back by a backup restore, discarding replayed rounds")
+			ep.supersedeRounds(decision.SupersededRounds)
+		}
+		if decision.Validity != types.RoundValidityLive {
+			ep.logger.WithFields(logrus.Fields{
+				"tick":     ep.currentTick,
+				"validity": decision.Validity,
+			}).Debug("Skipping non-competitive round")
+			return nil
 		}
-		ep.logger.WithField("round", ep.currentRound).Info("Skipping first round for FACEIT match")
-		return nil
 	}
 
 	if ep.matchHandler == nil {
 		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityCritical, "match handler is nil", nil).
 			WithContext("event", "RoundStart")
 	}
+	ep.startRoundParticipants()
+	if ep.itemHandler != nil {
+		ep.itemHandler.ResetRound()
+	}
+	if ep.replayHandler != nil {
+		ep.replayHandler.ResetRound()
+	}
 	return ep.matchHandler.HandleRoundStart(e)
 }
 
 func (ep *EventProcessor) HandleRoundEnd(e events.RoundEnd) error {
-	// Skip first round for FACEIT matches
-	if ep.isFaceitMatch && ep.currentRound == 1 {
-		ep.logger.WithField("round", ep.currentRound).Info("Skipping first round end for FACEIT match")
-		return nil
+	if ep.roundValidity != nil {
+		decision := ep.roundValidity.RoundEnded(ep.currentTick, e.Reason)
+		if !decision.Live {
+			if decision.DiscardCurrent {
+				ep.discardCurrentRound()
+			}
+			if decision.Restart {
+				ep.restartMatch(false)
+			}
+			ep.logger.WithFields(logrus.Fields{
+				"tick":   ep.currentTick,
+				"reason": e.Reason,
+			}).Debug("Skipping end of non-competitive round")
+			return nil
+		}
 	}
 
 	if ep.matchHandler == nil {
@@ -178,9 +267,15 @@ func (ep *EventProcessor) HandleRoundEnd(e events.RoundEnd) error {
 			WithContext("event", "RoundEnd").
 			WithContext("round", ep.matchState.CurrentRound)
 	}
+	if ep.economyHandler != nil {
+		ep.economyHandler.ProcessRoundEnd(ep.getTeamString(e.Winner))
+	}
 	if ep.grenadeHandler != nil {
 		ep.grenadeHandler.CleanupDuplicateFlashGrenades()
 
+		// Fires go onto their grenades before the damage is aggregated and rated
+		ep.grenadeHandler.ProcessInfernos()
+
 		// Performance tracking for AggregateAllGrenadeDamage
 		if ep.perfLogger != nil {
 			timer := ep.perfLogger.StartTimer("AggregateAllGrenadeDamage").
@@ -201,8 +296,12 @@ func (ep *EventProcessor) HandleRoundEnd(e events.RoundEnd) error {
 			ep.grenadeHandler.PopulateFlashGrenadeEffectiveness()
 		}
 
-		// Use the new post-processing method for smoke blocking duration
+		// Use the new post-processing method for smoke blocking duration. The round's
+		// ticks are loaded first, so buffered and columnar tick data is seen too.
 		if ep.playerTickService != nil {
+			if err := ep.loadRoundTicks(); err != nil {
+				ep.logger.WithError(err).Warn("Failed to load round tick data for smoke blocking")
+			}
 			_ = ep.grenadeHandler.ProcessSmokeBlockingDurationPostProcess(ep.matchID)
 		}
 	}
@@ -210,6 +309,11 @@ func (ep *EventProcessor) HandleRoundEnd(e events.RoundEnd) error {
 		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityCritical, "round handler is nil", nil).
 			WithContext("event", "RoundEnd")
 	}
+
+	// Trades go in before the player round events that count them
+	if ep.tradeHandler != nil {
+		ep.tradeHandler.ProcessRoundEnd()
+	}
 	if err := ep.roundHandler.ProcessRoundEnd(); err != nil {
 		ep.logger.WithError(err).Error("Failed to process round end")
 		return types.NewParseError(types.ErrorTypeEventProcessing, "failed to process round end", err).
@@ -229,6 +333,16 @@ func (ep *EventProcessor) HandleRoundEnd(e events.RoundEnd) error {
 			ep.aimTrackingHandler.DetectSprayingPatternsForRound(ep.matchState.CurrentRound)
 		}
 
+		// Persist the round's shots now that spraying has been determined
+		if ep.tickWriter != nil {
+			roundShots := ep.aimTrackingHandler.GetShootingDataForRound(ep.matchState.CurrentRound)
+			if err := ep.tickWriter.WriteShots(context.Background(), roundShots); err != nil {
+				return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityCritical, "failed to write shooting data", err).
+					WithContext("event", "RoundEnd").
+					WithContext("round", ep.matchState.CurrentRound)
+			}
+		}
+
 		// Performance tracking for processAimTrackingForRound
 		if ep.perfLogger != nil {
 			timer := ep.perfLogger.StartTimer("processAimTrackingForRound").
@@ -251,6 +365,11 @@ func (ep *EventProcessor) HandleRoundEnd(e events.RoundEnd) error {
 		}
 	}
 
+	// Build the replay once the round's tick data is in the cache
+	if ep.replayHandler != nil {
+		ep.replayHandler.ProcessRoundEnd()
+	}
+
 	return nil
 }
 
@@ -258,6 +377,9 @@ func (ep *EventProcessor) HandlePlayerKilled(e events.Kill) error {
 	if ep.shouldSkipCurrentRound() {
 		return nil
 	}
+	e.Killer = ep.actingPlayer(e.Killer)
+	e.Victim = ep.actingPlayer(e.Victim)
+	e.Assister = ep.actingPlayer(e.Assister)
 	return ep.gunfightHandler.HandlePlayerKilled(e)
 }
 
@@ -265,6 +387,8 @@ func (ep *EventProcessor) HandlePlayerHurt(e events.PlayerHurt) error {
 	if ep.shouldSkipCurrentRound() {
 		return nil
 	}
+	e.Attacker = ep.actingPlayer(e.Attacker)
+	e.Player = ep.actingPlayer(e.Player)
 	return ep.damageHandler.HandlePlayerHurt(e)
 }
 
@@ -282,10 +406,48 @@ func (ep *EventProcessor) HandleGrenadeProjectileDestroy(e events.GrenadeProject
 	return ep.grenadeHandler.HandleGrenadeProjectileDestroy(e)
 }
 
+func (ep *EventProcessor) HandleGrenadeProjectileBounce(e events.GrenadeProjectileBounce) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.grenadeHandler.HandleGrenadeProjectileBounce(e)
+}
+
+// SampleGrenadeFlights records where the grenades in flight are at the current tick
+func (ep *EventProcessor) SampleGrenadeFlights(projectiles map[int]*common.GrenadeProjectile) {
+	if ep.shouldSkipCurrentRound() {
+		return
+	}
+	ep.grenadeHandler.SampleFlights(projectiles)
+}
+
+func (ep *EventProcessor) HandleInfernoStart(e events.InfernoStart) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.grenadeHandler.HandleInfernoStart(e)
+}
+
+func (ep *EventProcessor) HandleInfernoExpired(e events.InfernoExpired) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.grenadeHandler.HandleInfernoExpired(e)
+}
+
+// SampleInfernos records how far the burning fires spread and who is standing in them
+func (ep *EventProcessor) SampleInfernos(infernos map[int]*common.Inferno, players []*common.Player) {
+	if ep.shouldSkipCurrentRound() {
+		return
+	}
+	ep.grenadeHandler.SampleInfernos(infernos, players)
+}
+
 func (ep *EventProcessor) HandleFlashExplode(e events.FlashExplode) error {
 	if ep.shouldSkipCurrentRound() {
 		return nil
 	}
+	e.Thrower = ep.actingPlayer(e.Thrower)
 	return ep.grenadeHandler.HandleFlashExplode(e)
 }
 
@@ -300,6 +462,7 @@ func (ep *EventProcessor) HandleSmokeStart(e events.SmokeStart) error {
 	if ep.shouldSkipCurrentRound() {
 		return nil
 	}
+	e.Thrower = ep.actingPlayer(e.Thrower)
 	return ep.grenadeHandler.HandleSmokeStart(e)
 }
 
@@ -307,31 +470,154 @@ func (ep *EventProcessor) HandleWeaponFire(e events.WeaponFire) error {
 	if ep.shouldSkipCurrentRound() {
 		return nil
 	}
+	e.Shooter = ep.actingPlayer(e.Shooter)
 	if err := ep.matchHandler.HandleWeaponFire(e); err != nil {
 		return err
 	}
 	return ep.aimTrackingHandler.HandleWeaponFire(e)
 }
 
+func (ep *EventProcessor) HandleItemPickup(e events.ItemPickup) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.itemHandler.HandleItemPickup(e)
+}
+
+func (ep *EventProcessor) HandleItemDrop(e events.ItemDrop) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.itemHandler.HandleItemDrop(e)
+}
+
+func (ep *EventProcessor) HandleItemRefund(e events.ItemRefund) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.itemHandler.HandleItemRefund(e)
+}
+
+func (ep *EventProcessor) HandleBombPlantBegin(e events.BombPlantBegin) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.bombHandler.HandleBombPlantBegin(e)
+}
+
+func (ep *EventProcessor) HandleBombPlantAborted(e events.BombPlantAborted) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.bombHandler.HandleBombPlantAborted(e)
+}
+
 func (ep *EventProcessor) HandleBombPlanted(e events.BombPlanted) error {
 	if ep.shouldSkipCurrentRound() {
 		return nil
 	}
-	return ep.matchHandler.HandleBombPlanted(e)
+	if err := ep.matchHandler.HandleBombPlanted(e); err != nil {
+		return err
+	}
+	return ep.bombHandler.HandleBombPlanted(e)
+}
+
+func (ep *EventProcessor) HandleBombDefuseStart(e events.BombDefuseStart) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.bombHandler.HandleBombDefuseStart(e)
+}
+
+func (ep *EventProcessor) HandleBombDefuseAborted(e events.BombDefuseAborted) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.bombHandler.HandleBombDefuseAborted(e)
 }
 
 func (ep *EventProcessor) HandleBombDefused(e events.BombDefused) error {
 	if ep.shouldSkipCurrentRound() {
 		return nil
 	}
-	return ep.matchHandler.HandleBombDefused(e)
+	if err := ep.matchHandler.HandleBombDefused(e); err != nil {
+		return err
+	}
+	return ep.bombHandler.HandleBombDefused(e)
 }
 
 func (ep *EventProcessor) HandleBombExplode(e events.BombExplode) error {
 	if ep.shouldSkipCurrentRound() {
 		return nil
 	}
-	return ep.matchHandler.HandleBombExplode(e)
+	if err := ep.matchHandler.HandleBombExplode(e); err != nil {
+		return err
+	}
+	return ep.bombHandler.HandleBombExplode(e)
+}
+
+func (ep *EventProcessor) HandleBombDropped(e events.BombDropped) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.bombHandler.HandleBombDropped(e)
+}
+
+func (ep *EventProcessor) HandleBombPickup(e events.BombPickup) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+	return ep.bombHandler.HandleBombPickup(e)
+}
+
+func (ep *EventProcessor) HandleRoundFreezetimeEnd(e events.RoundFreezetimeEnd) error {
+	if ep.shouldSkipCurrentRound() {
+		return nil
+	}
+
+	// Knife rounds look like any other round until the buy phase is over
+	if ep.roundValidity != nil && ep.roundValidity.FreezetimeEnded(ep.currentTick, isKnifeOnlyLoadout(ep.aliveLoadouts())) {
+		ep.logger.WithField("round", ep.currentRound).Info("Knife round detected, discarding round")
+		ep.discardCurrentRound()
+		return nil
+	}
+
+	if err := ep.matchHandler.HandleRoundFreezetimeEnd(e); err != nil {
+		return err
+	}
+
+	if ep.economyHandler != nil {
+		ep.economyHandler.HandleFreezetimeEnd()
+	}
+	if ep.loadoutHandler != nil {
+		ep.loadoutHandler.HandleFreezetimeEnd()
+	}
+	return nil
+}
+
+func (ep *EventProcessor) HandleMatchStart(e events.MatchStart) error {
+	if err := ep.matchHandler.HandleMatchStart(e); err != nil {
+		return err
+	}
+
+	if ep.roundValidity == nil || ep.matchState == nil {
+		return nil
+	}
+
+	dropped := ep.roundValidity.MatchRestarted(ep.currentTick)
+	if dropped > 0 {
+		ep.logger.WithField("rounds", dropped).Info("Match restarted, discarding rounds played before the restart")
+	}
+
+	if ep.matchState.CurrentRound > 0 {
+		keepCurrent := ep.roundValidity.LiveRoundInProgress()
+		ep.restartMatch(keepCurrent)
+		if keepCurrent {
+			ep.matchState.MatchStartTick = ep.matchState.RoundStartTick
+		}
+	}
+
+	return nil
 }
 
 func (ep *EventProcessor) HandlePlayerConnect(e events.PlayerConnect) error {
@@ -421,7 +707,7 @@ func (ep *EventProcessor) ensurePlayerTracked(player *common.Player) error {
 	steamID := types.SteamIDToString(player.SteamID64)
 	side := ep.getTeamString(player.Team)
 
-	if err := ep.assignTeamBasedOnRound1To12(steamID, side); err != nil {
+	if err := ep.assignTeam(steamID, side); err != nil {
 		return err
 	}
 	assignedTeam := ep.getAssignedTeam(steamID)
@@ -437,6 +723,7 @@ func (ep *EventProcessor) ensurePlayerTracked(player *common.Player) error {
 			SteamID: steamID,
 			Name:    player.Name,
 			Team:    assignedTeam,
+			IsBot:   player.IsBot,
 		}
 
 		// Set rank fields if available
@@ -467,63 +754,133 @@ func (ep *EventProcessor) ensurePlayerTracked(player *common.Player) error {
 		}
 	}
 
+	ep.markRoundParticipant(player)
+
 	return nil
 }
 
-func (ep *EventProcessor) assignTeamBasedOnRound1To12(steamID string, side string) error {
+// assignTeam gives a player a stable team letter. The first players seen on CT form
+// team A; anyone joining later takes the letter of the team currently on their side.
+func (ep *EventProcessor) assignTeam(steamID string, side string) error {
 	if ep.logger == nil {
 		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityCritical, "logger is nil", nil).
-			WithContext("method", "assignTeamBasedOnRound1To12")
+			WithContext("method", "assignTeam")
 	}
 
 	if ep.teamAssignments == nil {
 		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityCritical, "team assignments is nil", nil).
-			WithContext("method", "assignTeamBasedOnRound1To12")
+			WithContext("method", "assignTeam")
 	}
 
-	// Attempting team assignment
-
-	if ep.currentRound > 12 {
-		// Skipping team assignment - round > 12
+	if _, assigned := ep.teamAssignments[steamID]; assigned {
 		return nil
 	}
 
-	if ep.assignmentComplete {
-		// Skipping team assignment - already complete
+	if side != "CT" && side != "T" {
 		return nil
 	}
 
-	if _, assigned := ep.teamAssignments[steamID]; assigned {
-		// Skipping team assignment - player already assigned
-		return nil
+	if ep.teamACurrentSide == "" {
+		ep.teamAStartedAs = "CT"
+		ep.teamBStartedAs = "T"
+		ep.teamACurrentSide = "CT"
+		ep.teamBCurrentSide = "T"
 	}
 
-	if side == "CT" {
+	if side == ep.teamACurrentSide {
 		ep.teamAssignments[steamID] = "A"
-		// Player assigned to team A (CT)
-		if ep.teamAStartedAs == "" {
-			ep.teamAStartedAs = "CT"
-			ep.teamBStartedAs = "T"
-			ep.teamACurrentSide = "CT"
-			ep.teamBCurrentSide = "T"
-		}
-	} else if side == "T" {
+	} else {
 		ep.teamAssignments[steamID] = "B"
-		// Player assigned to team B (T)
-		if ep.teamBStartedAs == "" {
-			ep.teamBStartedAs = "T"
-			ep.teamAStartedAs = "CT"
-			ep.teamACurrentSide = "CT"
-			ep.teamBCurrentSide = "T"
+	}
+
+	return nil
+}
+
+// UpdateMatchRules rebuilds the side switch schedule from the game mode and convars
+func (ep *EventProcessor) UpdateMatchRules(gameMode *types.GameMode, conVars map[string]string) {
+	ep.matchRules = NewMatchRules(gameMode, conVars)
+}
+
+// teamFromMembers returns the team letter most of the given players are assigned to,
+// or "" when none of them are known
+func (ep *EventProcessor) teamFromMembers(members []*common.Player) string {
+	teamACount, teamBCount := 0, 0
+	for _, member := range members {
+		if member == nil {
+			continue
+		}
+		switch ep.teamAssignments[types.SteamIDToString(member.SteamID64)] {
+		case "A":
+			teamACount++
+		case "B":
+			teamBCount++
 		}
 	}
 
-	if len(ep.teamAssignments) == 10 {
-		ep.assignmentComplete = true
-		// Team assignment complete
+	if teamACount > teamBCount {
+		return "A"
+	} else if teamBCount > teamACount {
+		return "B"
 	}
+	return ""
+}
 
-	return nil
+// teamOnSide returns the letter of the team currently playing side, or "" if unknown
+func (ep *EventProcessor) teamOnSide(side string) string {
+	switch side {
+	case ep.teamACurrentSide:
+		return "A"
+	case ep.teamBCurrentSide:
+		return "B"
+	default:
+		return ""
+	}
+}
+
+// setTeamSide records that team is currently playing side and the other team the opposite
+func (ep *EventProcessor) setTeamSide(team string, side string) bool {
+	opposite := "T"
+	if side == "T" {
+		opposite = "CT"
+	}
+
+	teamASide, teamBSide := side, opposite
+	if team == "B" {
+		teamASide, teamBSide = opposite, side
+	}
+
+	if ep.teamACurrentSide == teamASide && ep.teamBCurrentSide == teamBSide {
+		return false
+	}
+
+	ep.teamACurrentSide = teamASide
+	ep.teamBCurrentSide = teamBSide
+	return true
+}
+
+// syncTeamSidesFromGameState reads the current sides from the game's team entities.
+// It returns false when the entities do not identify a team, e.g. before players connect.
+func (ep *EventProcessor) syncTeamSidesFromGameState() (bool, bool) {
+	if ep.demoParser == nil {
+		return false, false
+	}
+
+	gameState := ep.demoParser.GameState()
+	if gameState == nil {
+		return false, false
+	}
+
+	ctState := gameState.Team(common.TeamCounterTerrorists)
+	if ctState == nil {
+		return false, false
+	}
+
+	team := ep.teamFromMembers(ctState.Members())
+	if team == "" {
+		return false, false
+	}
+
+	return true, ep.setTeamSide(team, "CT")
 }
 
 func (ep *EventProcessor) getAssignedTeam(steamID string) string {
@@ -595,7 +952,7 @@ func (ep *EventProcessor) getCurrentRoundTime() int {
 		return 0
 	}
 
-	timeSinceRoundStart := int((ep.currentTick - ep.matchState.RoundStartTick) / 64)
+	timeSinceRoundStart := int(ep.timing.TicksToSeconds(ep.currentTick - ep.matchState.RoundStartTick))
 
 	if timeSinceRoundStart < types.CS2FreezeTime {
 		// Calculated round time (still in freeze time)
@@ -678,6 +1035,20 @@ func (ep *EventProcessor) callMethod(obj interface{}, methodName string) interfa
 	return result.Interface()
 }
 
+// loadRoundTicks loads the tick data of the round that just ended into the round
+// tick cache. Loading flushes the tick writer and reads the columnar blobs.
+func (ep *EventProcessor) loadRoundTicks() error {
+	if ep.roundTickCache == nil {
+		return nil
+	}
+
+	roundEndTick := ep.matchState.RoundEndTick
+	if roundEndTick == 0 {
+		roundEndTick = ep.currentTick
+	}
+	return ep.roundTickCache.LoadRound(context.Background(), ep.matchState.CurrentRound, ep.matchState.RoundStartTick, roundEndTick)
+}
+
 // processAimTrackingForRound processes aim tracking data for the current round
 func (ep *EventProcessor) processAimTrackingForRound() error {
 	// Get shooting data for the current round
@@ -703,6 +1074,7 @@ func (ep *EventProcessor) processAimTrackingForRound() error {
 			WithContext("event", "RoundEnd").
 			WithContext("round", ep.matchState.CurrentRound)
 	}
+	aimService.SetTickTiming(ep.timing)
 
 	// Get damage events for the round
 	var damageEvents []types.DamageEvent
@@ -726,14 +1098,18 @@ func (ep *EventProcessor) processAimTrackingForRound() error {
 		}).Warn("RoundEndTick is 0, using current tick as fallback for player tick data query")
 	}
 
-	// Load tick data for this round into cache (single bulk query)
+	// Load tick data for this round into cache (single bulk query), unless the smoke
+	// post-processing already did
 	if ep.roundTickCache != nil {
-		err := ep.roundTickCache.LoadRound(
-			context.Background(),
-			ep.matchState.CurrentRound,
-			ep.matchState.RoundStartTick,
-			roundEndTick,
-		)
+		var err error
+		if !ep.roundTickCache.IsRoundLoaded(ep.matchState.CurrentRound) {
+			err = ep.roundTickCache.LoadRound(
+				context.Background(),
+				ep.matchState.CurrentRound,
+				ep.matchState.RoundStartTick,
+				roundEndTick,
+			)
+		}
 		if err != nil {
 			ep.logger.WithError(err).Error("Failed to load round tick data into cache")
 			return types.NewParseError(types.ErrorTypeEventProcessing, "failed to load round tick data", err).
@@ -242,7 +242,7 @@ func TestEventProcessor_AssignTeamBasedOnRound1To12_ErrorScenarios(t *testing.T)
 		t.Run(tt.name, func(t *testing.T) {
 			processor := tt.setup()
 
-			err := processor.assignTeamBasedOnRound1To12("123", "CT")
+			err := processor.assignTeam("123", "CT")
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -178,8 +178,9 @@ func TestEventProcessor_HandleRoundEnd(t *testing.T) {
 		t.Fatal("Expected duration to be set")
 	}
 
-	if *roundEvent.Duration != 120 {
-		t.Errorf("Expected duration to be 120, got %d", *roundEvent.Duration)
+	// No ticks elapsed between round start and end
+	if *roundEvent.Duration != 0 {
+		t.Errorf("Expected duration to be 0, got %d", *roundEvent.Duration)
 	}
 
 	// Test T win
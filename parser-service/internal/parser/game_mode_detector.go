@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"strconv"
+	"strings"
+
 	"parser-service/internal/types"
 
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs"
@@ -105,13 +108,13 @@ func (gmd *GameModeDetector) analyzeGameModeFromRankTypes(rankTypes map[int]bool
 		}
 	}
 
-	// Wingman mode uses rank type 13 (if it exists)
+	// Wingman mode uses rank type 13 (if it exists), MR8 with a halftime switch
 	if rankTypes[13] {
 		return &types.GameMode{
 			Mode:        "wingman",
 			DisplayName: "Wingman",
 			MaxRounds:   16,
-			HasHalftime: false,
+			HasHalftime: true,
 		}
 	}
 
@@ -204,12 +207,12 @@ func (gmd *GameModeDetector) analyzeGameMode(maxRounds, halftime, friendlyFire,
 	}
 
 	// Wingman mode characteristics
-	if maxRounds == "16" && halftime == "false" {
+	if maxRounds == "16" {
 		return &types.GameMode{
 			Mode:        "wingman",
 			DisplayName: "Wingman",
 			MaxRounds:   16,
-			HasHalftime: false,
+			HasHalftime: halftime != "false" && halftime != "0",
 		}, nil
 	}
 
@@ -256,28 +259,13 @@ func (gmd *GameModeDetector) analyzeGameMode(maxRounds, halftime, friendlyFire,
 	}, parseError
 }
 
-// parseMaxRounds safely parses the max rounds string to an integer
+// parseMaxRounds safely parses the max rounds string to an integer, returning 0 when it is not a number
 func (gmd *GameModeDetector) parseMaxRounds(maxRoundsStr string) int {
-	if maxRoundsStr == "" {
-		return 0
-	}
-
-	// Simple parsing - in a real implementation you might want to use strconv.Atoi
-	// For now, we'll handle the common cases
-	switch maxRoundsStr {
-	case "1":
-		return 1
-	case "16":
-		return 16
-	case "24":
-		return 24
-	case "30":
-		return 30
-	default:
-		// Try to parse as integer, fallback to 0 if it fails
-		// In a production environment, you'd use strconv.Atoi here
+	maxRounds, err := strconv.Atoi(strings.TrimSpace(maxRoundsStr))
+	if err != nil || maxRounds < 0 {
 		return 0
 	}
+	return maxRounds
 }
 
 // validateGameModeConfiguration validates the game mode configuration and returns appropriate errors
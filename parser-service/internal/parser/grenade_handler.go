@@ -18,6 +18,7 @@ type GrenadeMovementInfo struct {
 	RoundNumber        int
 	RoundTime          int
 	PlayerPos          types.Position
+	PlayerPlace        string
 	PlayerAim          types.Vector
 	ThrowType          string
 	ProjectileUniqueID int64
@@ -39,25 +40,33 @@ type GrenadeHandler struct {
 	movementService *MovementStateService
 	grenadeThrows   map[string]*GrenadeMovementInfo
 	activeSmokes    map[int64]*SmokeEffect
+	flights         map[int]*grenadeFlight // Grenades in flight by projectile entity ID
+
+	activeInfernos   map[int64]*InfernoEffect // Burning fires by inferno unique ID
+	finishedInfernos []*InfernoEffect         // Fires that went out this round
 }
 
-const MAX_FLASH_DURATION = 288 // 4.5 seconds
+const MAX_FLASH_DURATION_SECONDS = 4.5
 
 // Smoke constants
 const (
-	SMOKE_DURATION_TICKS      = 1152 // 18 seconds in ticks
-	SMOKE_WIDTH_UNITS         = 300  // Smoke average width in units
-	SMOKE_EFFECTIVE_RANGE     = 450  // Effective range to check for enemies
-	SMOKE_EFFECTIVENESS_TICKS = 64   // 1 point for every 64 ticks blocked
+	SMOKE_DURATION_SECONDS = 18.0 // Smoke lifetime
+	SMOKE_WIDTH_UNITS      = 300  // Smoke average width in units
+	SMOKE_EFFECTIVE_RANGE  = 450  // Effective range to check for enemies
 )
 
 func NewGrenadeHandler(processor *EventProcessor, logger *logrus.Logger) *GrenadeHandler {
+	movementService := NewMovementStateService(logger)
+	movementService.SetTickTiming(processor.Timing())
+
 	return &GrenadeHandler{
 		processor:       processor,
 		logger:          logger,
-		movementService: NewMovementStateService(logger),
+		movementService: movementService,
 		grenadeThrows:   make(map[string]*GrenadeMovementInfo),
 		activeSmokes:    make(map[int64]*SmokeEffect),
+		flights:         make(map[int]*grenadeFlight),
+		activeInfernos:  make(map[int64]*InfernoEffect),
 	}
 }
 
@@ -68,6 +77,10 @@ func (gh *GrenadeHandler) HandleGrenadeProjectileDestroy(e events.GrenadeProject
 			WithContext("tick", gh.processor.currentTick)
 	}
 
+	if e.Projectile.Entity != nil {
+		defer gh.endFlight(e.Projectile.Entity.ID())
+	}
+
 	if e.Projectile.Thrower == nil {
 		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityWarning, "projectile thrower is nil", nil).
 			WithContext("event_type", "GrenadeProjectileDestroy").
@@ -82,7 +95,10 @@ func (gh *GrenadeHandler) HandleGrenadeProjectileDestroy(e events.GrenadeProject
 	}
 
 	grenadeTypeString := e.Projectile.WeaponInstance.Type.String()
-	if err := gh.processor.ensurePlayerTracked(e.Projectile.Thrower); err != nil {
+
+	// Grenades thrown by a bot someone took over belong to that player
+	thrower := gh.processor.actingPlayer(e.Projectile.Thrower)
+	if err := gh.processor.ensurePlayerTracked(thrower); err != nil {
 		return err
 	}
 
@@ -109,10 +125,11 @@ func (gh *GrenadeHandler) HandleGrenadeProjectileDestroy(e events.GrenadeProject
 		RoundTime:         roundTime,
 		TickTimestamp:     tickTimestamp,
 		ExplosionTick:     gh.processor.currentTick,
-		PlayerSteamID:     types.SteamIDToString(e.Projectile.Thrower.SteamID64),
-		PlayerSide:        gh.processor.getPlayerCurrentSide(types.SteamIDToString(e.Projectile.Thrower.SteamID64)),
+		PlayerSteamID:     types.SteamIDToString(thrower.SteamID64),
+		PlayerSide:        gh.processor.getPlayerCurrentSide(types.SteamIDToString(thrower.SteamID64)),
 		GrenadeType:       grenadeType,
 		PlayerPosition:    playerPos,
+		PlayerPlace:       movementInfo.PlayerPlace,
 		PlayerAim:         playerAim,
 		ThrowType:         movementThrowType,
 		FlashLeadsToKill:  false,
@@ -125,6 +142,8 @@ func (gh *GrenadeHandler) HandleGrenadeProjectileDestroy(e events.GrenadeProject
 		Y: position.Y,
 		Z: position.Z,
 	}
+	grenadeEvent.GrenadeFinalPlace = gh.processor.getPlace(*grenadeEvent.GrenadeFinalPosition)
+	gh.attachFlight(&grenadeEvent, e.Projectile.Entity.ID())
 
 	gh.processor.matchState.GrenadeEvents = append(gh.processor.matchState.GrenadeEvents, grenadeEvent)
 
@@ -214,6 +233,7 @@ func (gh *GrenadeHandler) HandleFlashExplode(e events.FlashExplode) error {
 		PlayerSide:        gh.processor.getPlayerCurrentSide(flashEffect.ThrowerSteamID),
 		GrenadeType:       "Flashbang",
 		PlayerPosition:    playerPos,
+		PlayerPlace:       movementInfo.PlayerPlace,
 		PlayerAim:         playerAim,
 		ThrowType:         movementThrowType,
 		FlashLeadsToKill:  false,
@@ -225,6 +245,8 @@ func (gh *GrenadeHandler) HandleFlashExplode(e events.FlashExplode) error {
 		Y: e.Position.Y,
 		Z: e.Position.Z,
 	}
+	grenadeEvent.GrenadeFinalPlace = gh.processor.getPlace(*grenadeEvent.GrenadeFinalPosition)
+	gh.attachFlight(&grenadeEvent, e.GrenadeEntityID)
 
 	gh.processor.matchState.GrenadeEvents = append(gh.processor.matchState.GrenadeEvents, grenadeEvent)
 
@@ -323,12 +345,14 @@ func (gh *GrenadeHandler) HandleGrenadeProjectileThrow(e events.GrenadeProjectil
 		RoundNumber:        gh.processor.matchState.CurrentRound,
 		RoundTime:          gh.processor.getCurrentRoundTime(),
 		PlayerPos:          gh.processor.getPlayerPosition(e.Projectile.Thrower),
+		PlayerPlace:        gh.processor.getPlayerPlace(e.Projectile.Thrower),
 		PlayerAim:          gh.processor.getPlayerAim(e.Projectile.Thrower),
 		ThrowType:          movementThrowType,
 		ProjectileUniqueID: projectileUniqueID,
 	}
 
 	gh.grenadeThrows[projectileID] = throwInfo
+	gh.startFlight(e.Projectile)
 
 	return nil
 }
@@ -347,7 +371,7 @@ func (gh *GrenadeHandler) HandleSmokeStart(e events.SmokeStart) error {
 	smokeEffect := &SmokeEffect{
 		EntityID:       entityID,
 		StartTick:      gh.processor.currentTick,
-		EndTick:        gh.processor.currentTick + SMOKE_DURATION_TICKS,
+		EndTick:        gh.processor.currentTick + gh.processor.Timing().SecondsToTicks(SMOKE_DURATION_SECONDS),
 		Position:       types.Position{X: e.Position.X, Y: e.Position.Y, Z: e.Position.Z},
 		ThrowerSteamID: throwerSteamID,
 		RoundNumber:    gh.processor.matchState.CurrentRound,
@@ -355,12 +379,14 @@ func (gh *GrenadeHandler) HandleSmokeStart(e events.SmokeStart) error {
 	}
 
 	gh.activeSmokes[entityID] = smokeEffect
+	gh.smokeInfernos(smokeEffect.Position, e.GrenadeEvent.Thrower.Team)
 
 	// Create GrenadeEvent record for smoke grenade (similar to other grenade types)
 	projectileID := fmt.Sprintf("entity_%d", entityID)
 	movementInfo, hasMovementInfo := gh.grenadeThrows[projectileID]
 
 	var playerPos types.Position
+	var playerPlace string
 	var playerAim types.Vector
 	var roundTime int
 	var movementThrowType string
@@ -368,6 +394,7 @@ func (gh *GrenadeHandler) HandleSmokeStart(e events.SmokeStart) error {
 
 	if hasMovementInfo {
 		playerPos = movementInfo.PlayerPos
+		playerPlace = movementInfo.PlayerPlace
 		playerAim = movementInfo.PlayerAim
 		roundTime = movementInfo.RoundTime
 		movementThrowType = movementInfo.ThrowType
@@ -390,6 +417,7 @@ func (gh *GrenadeHandler) HandleSmokeStart(e events.SmokeStart) error {
 		PlayerSide:        gh.processor.getPlayerCurrentSide(throwerSteamID),
 		GrenadeType:       "Smoke Grenade",
 		PlayerPosition:    playerPos,
+		PlayerPlace:       playerPlace,
 		PlayerAim:         playerAim,
 		ThrowType:         movementThrowType,
 		FlashLeadsToKill:  false,
@@ -401,6 +429,8 @@ func (gh *GrenadeHandler) HandleSmokeStart(e events.SmokeStart) error {
 		Y: e.Position.Y,
 		Z: e.Position.Z,
 	}
+	grenadeEvent.GrenadeFinalPlace = gh.processor.getPlace(*grenadeEvent.GrenadeFinalPosition)
+	gh.attachFlight(&grenadeEvent, int(entityID))
 
 	gh.processor.matchState.GrenadeEvents = append(gh.processor.matchState.GrenadeEvents, grenadeEvent)
 
@@ -462,7 +492,7 @@ func (gh *GrenadeHandler) updateGrenadeEventWithFlashData(flashEffect *FlashEffe
 
 func (gh *GrenadeHandler) CheckFlashEffectiveness(killerSteamID, victimSteamID string, killTick int64) *string {
 	for _, flashEffect := range gh.processor.activeFlashEffects {
-		if killTick-flashEffect.ExplosionTick <= MAX_FLASH_DURATION {
+		if killTick-flashEffect.ExplosionTick <= gh.processor.Timing().SecondsToTicks(MAX_FLASH_DURATION_SECONDS) {
 			if victimInfo, exists := flashEffect.AffectedPlayers[types.StringToSteamID(victimSteamID)]; exists {
 				killerTeam := gh.processor.getAssignedTeam(killerSteamID)
 				flashThrowerTeam := gh.processor.getAssignedTeam(flashEffect.ThrowerSteamID)
@@ -656,7 +686,7 @@ func (gh *GrenadeHandler) calculateGrenadeDataCompleteness(event *types.GrenadeE
 func (gh *GrenadeHandler) AggregateAllGrenadeDamage() {
 	for i := range gh.processor.matchState.GrenadeEvents {
 		grenadeEvent := &gh.processor.matchState.GrenadeEvents[i]
-		if grenadeEvent.GrenadeType == "Molotov" || grenadeEvent.GrenadeType == "Incendiary Grenade" || grenadeEvent.GrenadeType == "HE Grenade" {
+		if isFireGrenade(grenadeEvent.GrenadeType) || grenadeEvent.GrenadeType == "HE Grenade" {
 			gh.aggregateGrenadeDamage(grenadeEvent)
 		}
 	}
@@ -672,21 +702,33 @@ func (gh *GrenadeHandler) aggregateGrenadeDamage(grenadeEvent *types.GrenadeEven
 			continue
 		}
 
-		if damageEvent.Weapon != grenadeEvent.GrenadeType {
+		// Fire damage is reported as incendiary damage for molotovs too
+		if isFireGrenade(grenadeEvent.GrenadeType) {
+			if !isFireGrenade(damageEvent.Weapon) {
+				continue
+			}
+		} else if damageEvent.Weapon != grenadeEvent.GrenadeType {
 			continue
 		}
 
-		timeWindow := int64(types.GrenadeDamageWindow * 64)
+		windowStart := grenadeEvent.TickTimestamp
+		timeWindow := gh.processor.Timing().SecondsToTicks(types.GrenadeDamageWindow)
 
 		if grenadeEvent.GrenadeType == "Molotov" {
-			timeWindow = int64(types.MolotovDuration * 64)
+			timeWindow = gh.processor.Timing().SecondsToTicks(types.MolotovDuration)
 		}
 
 		if grenadeEvent.GrenadeType == "Incendiary Grenade" {
-			timeWindow = int64(types.IncendiaryDuration * 64)
+			timeWindow = gh.processor.Timing().SecondsToTicks(types.IncendiaryDuration)
+		}
+
+		// A tracked fire only does damage while it burns
+		if grenadeEvent.BurnDurationSeconds > 0 {
+			windowStart = grenadeEvent.ExplosionTick
+			timeWindow = gh.processor.Timing().SecondsToTicks(grenadeEvent.BurnDurationSeconds)
 		}
 
-		if damageEvent.TickTimestamp >= grenadeEvent.TickTimestamp && damageEvent.TickTimestamp <= grenadeEvent.TickTimestamp+timeWindow {
+		if damageEvent.TickTimestamp >= windowStart && damageEvent.TickTimestamp <= windowStart+timeWindow {
 			if gh.processor.getAssignedTeam(damageEvent.VictimSteamID) == gh.processor.getAssignedTeam(grenadeEvent.PlayerSteamID) {
 				teamDamage += damageEvent.Damage
 			} else {
@@ -703,7 +745,11 @@ func (gh *GrenadeHandler) aggregateGrenadeDamage(grenadeEvent *types.GrenadeEven
 	grenadeEvent.DamageDealt = enemyDamage
 	grenadeEvent.TeamDamageDealt = teamDamage
 	grenadeEvent.AffectedPlayers = affectedPlayers
-	grenadeEvent.EffectivenessRating = grenade_rating.ScoreExplosive(*grenadeEvent)
+	if isFireGrenade(grenadeEvent.GrenadeType) {
+		grenadeEvent.EffectivenessRating = grenade_rating.ScoreMolotov(*grenadeEvent)
+	} else {
+		grenadeEvent.EffectivenessRating = grenade_rating.ScoreExplosive(*grenadeEvent)
+	}
 }
 
 // CalculateSmokeBlockingDuration calculates how long a smoke blocks enemy line of sight
@@ -803,7 +849,7 @@ func (gh *GrenadeHandler) updateGrenadeEventWithSmokeBlocking(entityID int64, bl
 			grenadeEvent.ExplosionTick == smokeEffect.StartTick {
 			grenadeEvent.SmokeBlockingDuration = blockingDuration
 			// Update effectiveness rating based on smoke blocking
-			grenadeEvent.EffectivenessRating = grenade_rating.ScoreSmokeWithBlockingDuration(blockingDuration)
+			grenadeEvent.EffectivenessRating = grenade_rating.ScoreSmokeWithBlockingDuration(gh.processor.Timing().TicksToSeconds(int64(blockingDuration)))
 			found = true
 			break
 		}
@@ -816,10 +862,11 @@ func (gh *GrenadeHandler) updateGrenadeEventWithSmokeBlocking(entityID int64, bl
 // ProcessSmokeBlockingDurationPostProcess calculates smoke blocking duration using post-processing approach
 // This method fetches player tick data and calculates blocking duration based on actual player positions
 func (gh *GrenadeHandler) ProcessSmokeBlockingDurationPostProcess(matchID string) error {
-	// Find all smoke grenade events
+	// Find the smoke grenade events of the round that just ended; earlier rounds
+	// were processed at their own end
 	var smokeEvents []types.GrenadeEvent
 	for _, grenadeEvent := range gh.processor.matchState.GrenadeEvents {
-		if grenadeEvent.GrenadeType == "Smoke Grenade" {
+		if grenadeEvent.GrenadeType == "Smoke Grenade" && grenadeEvent.RoundNumber == gh.processor.matchState.CurrentRound {
 			smokeEvents = append(smokeEvents, grenadeEvent)
 		}
 	}
@@ -849,7 +896,7 @@ func (gh *GrenadeHandler) calculateSmokeBlockingDurationPostProcess(matchID stri
 
 	smokePos := *smokeEvent.GrenadeFinalPosition
 	startTick := smokeEvent.ExplosionTick
-	endTick := startTick + SMOKE_DURATION_TICKS
+	endTick := startTick + gh.processor.Timing().SecondsToTicks(SMOKE_DURATION_SECONDS)
 
 	// Get player tick data for the smoke duration period from cache if available
 	var playerTickData []*types.PlayerTickData
@@ -857,10 +904,15 @@ func (gh *GrenadeHandler) calculateSmokeBlockingDurationPostProcess(matchID stri
 		// Use cache for faster lookups
 		playerTickData = gh.processor.roundTickCache.GetTickDataByTickRange(startTick, endTick)
 	} else {
-		// Fallback to direct database query if cache not available
+		// Fallback to direct database query if cache not available, preferring the
+		// columnar blobs like the cache does
 		var err error
-		playerTickData, err = gh.processor.playerTickService.GetPlayerTickDataByTickRange(
+		playerTickData, err = gh.processor.playerTickService.GetPlayerTickDataFromBlobs(
 			context.Background(), matchID, startTick, endTick)
+		if err == nil && len(playerTickData) == 0 {
+			playerTickData, err = gh.processor.playerTickService.GetPlayerTickDataByTickRange(
+				context.Background(), matchID, startTick, endTick)
+		}
 		if err != nil {
 			gh.logger.WithFields(logrus.Fields{
 				"match_id":   matchID,
@@ -942,7 +994,7 @@ func (gh *GrenadeHandler) updateGrenadeEventWithSmokeBlockingPostProcess(smokeEv
 
 			grenadeEvent.SmokeBlockingDuration = blockingDuration
 			// Update effectiveness rating based on smoke blocking
-			grenadeEvent.EffectivenessRating = grenade_rating.ScoreSmokeWithBlockingDuration(blockingDuration)
+			grenadeEvent.EffectivenessRating = grenade_rating.ScoreSmokeWithBlockingDuration(gh.processor.Timing().TicksToSeconds(int64(blockingDuration)))
 
 			break
 		}
@@ -1,13 +1,18 @@
 package parser
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"parser-service/internal/database"
 	"parser-service/internal/types"
 
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
 	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 // TestGrenadeHandlerRefactor validates all the fixes implemented in the grenade handler refactor
@@ -669,3 +674,64 @@ func TestGrenadeHandler_GetGrenadeDisplayName(t *testing.T) {
 
 	t.Log("GetGrenadeDisplayName method tested successfully")
 }
+
+func TestGrenadeHandler_SmokeBlockingDurationColumnar(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	// Every new SQLite :memory: connection would see its own empty database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&types.PlayerTickData{}, &types.PlayerTickBlob{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	logger := logrus.New()
+	service := database.NewPlayerTickService(db, logger)
+	writer := database.NewAsyncTickWriter(service, logger, "match-1", database.TickWriterOptions{
+		Format:        types.TickStorageColumnar,
+		QueueSize:     16,
+		BatchSize:     10000,
+		FlushInterval: time.Hour,
+	})
+	defer writer.Close(context.Background())
+
+	smokePosition := types.Position{X: 100, Y: 0, Z: 0}
+	matchState := &types.MatchState{
+		CurrentRound:   1,
+		RoundStartTick: 900,
+		RoundEndTick:   1200,
+		Players:        make(map[string]*types.Player),
+		GrenadeEvents: []types.GrenadeEvent{
+			{RoundNumber: 1, PlayerSteamID: "123", GrenadeType: "Smoke Grenade", ExplosionTick: 1000, GrenadeFinalPosition: &smokePosition},
+		},
+	}
+	processor := NewEventProcessor(matchState, logger, nil, nil)
+	processor.teamAssignments["123"] = "A"
+	processor.teamAssignments["456"] = "B"
+	processor.SetMatchID("match-1")
+	processor.SetPlayerTickService(service)
+	processor.SetTickWriter(writer)
+	processor.InitializeRoundTickCache("match-1")
+	processor.currentTick = 1200
+
+	// An enemy stands in the smoke; the ticks are still buffered by the writer
+	for tick := int64(1000); tick < 1100; tick++ {
+		frame := []*types.PlayerTickData{{MatchID: "match-1", PlayerID: "456", Tick: tick, Team: "B", PositionX: 150}}
+		if err := writer.Write(context.Background(), 1, frame); err != nil {
+			t.Fatalf("Failed to write ticks: %v", err)
+		}
+	}
+
+	if err := processor.loadRoundTicks(); err != nil {
+		t.Fatalf("Failed to load round ticks: %v", err)
+	}
+	if err := processor.grenadeHandler.ProcessSmokeBlockingDurationPostProcess("match-1"); err != nil {
+		t.Fatalf("Failed to process smoke blocking: %v", err)
+	}
+
+	if blocking := matchState.GrenadeEvents[0].SmokeBlockingDuration; blocking <= 0 {
+		t.Errorf("Expected the smoke to block the enemy for some ticks, got %d", blocking)
+	}
+}
@@ -0,0 +1,334 @@
+package parser
+
+import (
+	"math"
+	"sort"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+)
+
+// Inferno constants
+const (
+	INFERNO_FIRE_RADIUS     = 60.0  // Radius of a single fire in units
+	INFERNO_AREA_MARGIN     = 100.0 // Distance from the fire a kill still counts as in the fire area
+	INFERNO_FIRE_HEIGHT     = 80.0  // Height difference at which a player is above or below the fire
+	INFERNO_SMOKE_RADIUS    = 150.0 // Distance from a fire at which a smoke puts it out
+	INFERNO_SMOKE_SECONDS   = 1.0   // Seconds a fire may take to go out after a smoke popped on it
+	INFERNO_MATCH_SECONDS   = 1.0   // Seconds between the projectile going off and the fire starting
+	INFERNO_MIN_HULL_POINTS = 3     // Fires needed to cover an area
+)
+
+type InfernoEffect struct {
+	UniqueID       int64
+	ThrowerSteamID string
+	ThrowerTeam    common.Team
+	RoundNumber    int
+	StartTick      int64
+	EndTick        int64
+	SmokedTick     int64            // Tick a smoke popped on the fire, 0 when none did
+	MaxArea        float64          // Largest area on fire at once
+	Fires          []types.Position // Fires when the area was largest
+	LastFires      []types.Position // Fires burning at the last sample
+	inside         map[string]bool  // Enemies standing in the fire at the last sample
+	displaced      map[string]bool  // Enemies that walked out of the fire
+}
+
+// isFireGrenade reports whether a grenade or damage weapon name is a molotov or incendiary
+func isFireGrenade(name string) bool {
+	switch name {
+	case "Molotov", "Incendiary", "Incendiary Grenade", types.GrenadeTypeMolotov, types.GrenadeTypeIncendiary:
+		return true
+	}
+	return false
+}
+
+func (gh *GrenadeHandler) HandleInfernoStart(e events.InfernoStart) error {
+	if e.Inferno == nil || e.Inferno.Entity == nil {
+		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityWarning, "inferno is nil", nil).
+			WithContext("event_type", "InfernoStart").
+			WithContext("tick", gh.processor.currentTick)
+	}
+
+	thrower := e.Inferno.Thrower()
+	if thrower == nil {
+		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityInfo, "inferno thrower is nil", nil).
+			WithContext("event_type", "InfernoStart").
+			WithContext("tick", gh.processor.currentTick)
+	}
+
+	// Fires started by a bot someone took over belong to that player
+	thrower = gh.processor.actingPlayer(thrower)
+
+	gh.activeInfernos[e.Inferno.UniqueID()] = &InfernoEffect{
+		UniqueID:       e.Inferno.UniqueID(),
+		ThrowerSteamID: types.SteamIDToString(thrower.SteamID64),
+		ThrowerTeam:    thrower.Team,
+		RoundNumber:    gh.processor.matchState.CurrentRound,
+		StartTick:      gh.processor.currentTick,
+		inside:         make(map[string]bool),
+		displaced:      make(map[string]bool),
+	}
+
+	return nil
+}
+
+func (gh *GrenadeHandler) HandleInfernoExpired(e events.InfernoExpired) error {
+	if e.Inferno == nil {
+		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityWarning, "inferno is nil", nil).
+			WithContext("event_type", "InfernoExpired").
+			WithContext("tick", gh.processor.currentTick)
+	}
+
+	inferno, exists := gh.activeInfernos[e.Inferno.UniqueID()]
+	if !exists {
+		return nil
+	}
+
+	inferno.EndTick = gh.processor.currentTick
+	gh.finishedInfernos = append(gh.finishedInfernos, inferno)
+	delete(gh.activeInfernos, e.Inferno.UniqueID())
+
+	return nil
+}
+
+// SampleInfernos records the extent of every burning fire and which enemies of
+// the thrower are standing in it
+func (gh *GrenadeHandler) SampleInfernos(infernos map[int]*common.Inferno, players []*common.Player) {
+	if len(gh.activeInfernos) == 0 {
+		return
+	}
+
+	for _, inferno := range infernos {
+		if inferno == nil || inferno.Entity == nil {
+			continue
+		}
+		effect, exists := gh.activeInfernos[inferno.UniqueID()]
+		if !exists {
+			continue
+		}
+
+		active := inferno.Fires().Active().List()
+		fires := make([]types.Position, 0, len(active))
+		for _, fire := range active {
+			fires = append(fires, types.Position{X: fire.X, Y: fire.Y, Z: fire.Z})
+		}
+
+		enemies := make(map[string]types.Position)
+		for _, player := range players {
+			if player == nil || player.Team == effect.ThrowerTeam || !player.IsAlive() {
+				continue
+			}
+			position := player.Position()
+			enemies[types.SteamIDToString(player.SteamID64)] = types.Position{X: position.X, Y: position.Y, Z: position.Z}
+		}
+
+		effect.sample(fires, enemies)
+	}
+}
+
+// sample updates the fire's extent and marks the enemies that left it
+func (effect *InfernoEffect) sample(fires []types.Position, enemies map[string]types.Position) {
+	effect.LastFires = fires
+	// Until the fire covers an area the sample with the most fires is kept
+	if area := hullArea(fires); area > effect.MaxArea || (effect.MaxArea == 0 && len(fires) >= len(effect.Fires)) {
+		effect.MaxArea = area
+		effect.Fires = fires
+	}
+
+	for steamID := range effect.inside {
+		if _, alive := enemies[steamID]; !alive {
+			delete(effect.inside, steamID)
+		}
+	}
+	for steamID, position := range enemies {
+		inFire := distanceToFire(position, fires) <= INFERNO_FIRE_RADIUS
+		if effect.inside[steamID] && !inFire {
+			effect.displaced[steamID] = true
+		}
+		effect.inside[steamID] = inFire
+	}
+}
+
+// smokeInfernos marks the burning fires of the other team within reach of a smoke
+// that just popped. A smoke on a team's own fire does not count against the fire.
+func (gh *GrenadeHandler) smokeInfernos(smokePosition types.Position, smokeTeam common.Team) {
+	for _, effect := range gh.activeInfernos {
+		if effect.SmokedTick != 0 || effect.ThrowerTeam == smokeTeam {
+			continue
+		}
+		for _, fire := range effect.LastFires {
+			if math.Hypot(fire.X-smokePosition.X, fire.Y-smokePosition.Y) <= INFERNO_SMOKE_RADIUS {
+				effect.SmokedTick = gh.processor.currentTick
+				break
+			}
+		}
+	}
+}
+
+// ProcessInfernos adds the fires of the round to the molotov and incendiary events
+// that started them. Fires still burning when the round ends are cut off there.
+func (gh *GrenadeHandler) ProcessInfernos() {
+	for uniqueID, effect := range gh.activeInfernos {
+		effect.EndTick = gh.processor.currentTick
+		gh.finishedInfernos = append(gh.finishedInfernos, effect)
+		delete(gh.activeInfernos, uniqueID)
+	}
+
+	// Match in start order so the earlier of two close fires takes the earlier grenade
+	sort.Slice(gh.finishedInfernos, func(i, j int) bool {
+		return gh.finishedInfernos[i].StartTick < gh.finishedInfernos[j].StartTick
+	})
+
+	for _, effect := range gh.finishedInfernos {
+		grenadeEvent := gh.findInfernoGrenade(effect)
+		if grenadeEvent == nil {
+			continue
+		}
+
+		// A fire that went out on the tick it started still burned
+		burnTicks := effect.EndTick - effect.StartTick
+		if burnTicks < 1 {
+			burnTicks = 1
+		}
+		grenadeEvent.BurnDurationSeconds = gh.processor.Timing().TicksToSeconds(burnTicks)
+		grenadeEvent.FireAreaCovered = math.Round(effect.MaxArea)
+		grenadeEvent.EnemiesDisplaced = len(gh.displacedEnemies(effect))
+		grenadeEvent.KillsDuringBurn = gh.killsDuringBurn(effect)
+		grenadeEvent.ExtinguishedBySmoke = effect.SmokedTick != 0 &&
+			effect.EndTick-effect.SmokedTick <= gh.processor.Timing().SecondsToTicks(INFERNO_SMOKE_SECONDS)
+	}
+
+	gh.finishedInfernos = nil
+}
+
+// findInfernoGrenade returns the fire grenade event of the thrower that went off
+// closest to when the fire started
+func (gh *GrenadeHandler) findInfernoGrenade(effect *InfernoEffect) *types.GrenadeEvent {
+	window := gh.processor.Timing().SecondsToTicks(INFERNO_MATCH_SECONDS)
+
+	var closest *types.GrenadeEvent
+	var closestGap int64
+	for i := range gh.processor.matchState.GrenadeEvents {
+		grenadeEvent := &gh.processor.matchState.GrenadeEvents[i]
+		if grenadeEvent.RoundNumber != effect.RoundNumber || grenadeEvent.PlayerSteamID != effect.ThrowerSteamID ||
+			!isFireGrenade(grenadeEvent.GrenadeType) || grenadeEvent.BurnDurationSeconds > 0 {
+			continue
+		}
+
+		gap := grenadeEvent.ExplosionTick - effect.StartTick
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap <= window && (closest == nil || gap < closestGap) {
+			closest = grenadeEvent
+			closestGap = gap
+		}
+	}
+
+	return closest
+}
+
+// displacedEnemies returns the enemies that walked out of the fire or took damage from it
+func (gh *GrenadeHandler) displacedEnemies(effect *InfernoEffect) map[string]bool {
+	displaced := make(map[string]bool, len(effect.displaced))
+	for steamID := range effect.displaced {
+		displaced[steamID] = true
+	}
+
+	throwerTeam := gh.processor.getAssignedTeam(effect.ThrowerSteamID)
+	for _, damageEvent := range gh.processor.matchState.DamageEvents {
+		if damageEvent.RoundNumber != effect.RoundNumber || damageEvent.AttackerSteamID != effect.ThrowerSteamID ||
+			!isFireGrenade(damageEvent.Weapon) {
+			continue
+		}
+		if damageEvent.TickTimestamp < effect.StartTick || damageEvent.TickTimestamp > effect.EndTick {
+			continue
+		}
+		if gh.processor.getAssignedTeam(damageEvent.VictimSteamID) != throwerTeam {
+			displaced[damageEvent.VictimSteamID] = true
+		}
+	}
+
+	return displaced
+}
+
+// killsDuringBurn counts the enemies of the thrower killed in the fire area while it burned
+func (gh *GrenadeHandler) killsDuringBurn(effect *InfernoEffect) int {
+	throwerTeam := gh.processor.getAssignedTeam(effect.ThrowerSteamID)
+
+	kills := 0
+	for _, gunfight := range gh.processor.matchState.GunfightEvents {
+		if gunfight.RoundNumber != effect.RoundNumber || gunfight.TickTimestamp < effect.StartTick || gunfight.TickTimestamp > effect.EndTick {
+			continue
+		}
+		if gh.processor.getAssignedTeam(gunfight.Player2SteamID) == throwerTeam {
+			continue
+		}
+		if distanceToFire(gunfight.Player2Position, effect.Fires) <= INFERNO_FIRE_RADIUS+INFERNO_AREA_MARGIN {
+			kills++
+		}
+	}
+
+	return kills
+}
+
+// distanceToFire returns the flat distance from a position to the closest fire at
+// its height, infinite when there is none
+func distanceToFire(position types.Position, fires []types.Position) float64 {
+	closest := math.Inf(1)
+	for _, fire := range fires {
+		if math.Abs(position.Z-fire.Z) > INFERNO_FIRE_HEIGHT {
+			continue
+		}
+		if distance := math.Hypot(position.X-fire.X, position.Y-fire.Y); distance < closest {
+			closest = distance
+		}
+	}
+	return closest
+}
+
+// hullArea returns the area of the flat convex hull around the fires
+func hullArea(fires []types.Position) float64 {
+	if len(fires) < INFERNO_MIN_HULL_POINTS {
+		return 0
+	}
+
+	points := make([]types.Position, len(fires))
+	copy(points, fires)
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].X != points[j].X {
+			return points[i].X < points[j].X
+		}
+		return points[i].Y < points[j].Y
+	})
+
+	cross := func(o, a, b types.Position) float64 {
+		return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+	}
+
+	// Andrew's monotone chain, lower hull then upper hull
+	hull := make([]types.Position, 0, 2*len(points))
+	for _, point := range points {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], point) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, point)
+	}
+	lower := len(hull) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], points[i]) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, points[i])
+	}
+
+	// Shoelace formula
+	area := 0.0
+	for i := 0; i < len(hull)-1; i++ {
+		area += hull[i].X*hull[i+1].Y - hull[i+1].X*hull[i].Y
+	}
+	return math.Abs(area) / 2
+}
@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"math"
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/sirupsen/logrus"
+)
+
+func TestHullArea(t *testing.T) {
+	square := []types.Position{
+		{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 50, Y: 50}, {X: 100, Y: 100}, {X: 0, Y: 100},
+	}
+	if area := hullArea(square); math.Abs(area-10000) > 1e-9 {
+		t.Errorf("Expected an area of 10000, got %f", area)
+	}
+
+	if area := hullArea(square[:2]); area != 0 {
+		t.Errorf("Expected two fires to cover no area, got %f", area)
+	}
+}
+
+func TestInfernoEffect_Sample(t *testing.T) {
+	effect := &InfernoEffect{inside: make(map[string]bool), displaced: make(map[string]bool)}
+	small := []types.Position{{X: 0, Y: 0}, {X: 50, Y: 0}, {X: 0, Y: 50}}
+	large := []types.Position{{X: 0, Y: 0}, {X: 200, Y: 0}, {X: 0, Y: 200}}
+
+	effect.sample(small, map[string]types.Position{
+		"runner": {X: 10, Y: 10},
+		"stayer": {X: 20, Y: 20},
+		"victim": {X: 30, Y: 10},
+	})
+	// The runner walks out, the stayer stays and the victim dies in the fire
+	effect.sample(large, map[string]types.Position{
+		"runner": {X: 500, Y: 500},
+		"stayer": {X: 20, Y: 20},
+	})
+	effect.sample(small, map[string]types.Position{
+		"runner": {X: 500, Y: 500},
+		"stayer": {X: 20, Y: 20},
+	})
+
+	if len(effect.displaced) != 1 || !effect.displaced["runner"] {
+		t.Errorf("Expected only the runner to be displaced, got %v", effect.displaced)
+	}
+	if effect.MaxArea != 20000 || len(effect.Fires) != 3 || effect.Fires[1].X != 200 {
+		t.Errorf("Expected the largest extent to be kept, got area %f with fires %v", effect.MaxArea, effect.Fires)
+	}
+	if effect.LastFires[1].X != 50 {
+		t.Errorf("Expected the last sample to be kept, got %v", effect.LastFires)
+	}
+}
+
+func TestGrenadeHandler_ProcessInfernos(t *testing.T) {
+	matchState := &types.MatchState{
+		CurrentRound:  1,
+		GrenadeEvents: []types.GrenadeEvent{},
+		Players:       make(map[string]*types.Player),
+	}
+	logger := logrus.New()
+	processor := NewEventProcessor(matchState, logger, nil, nil)
+	grenadeHandler := processor.grenadeHandler
+
+	processor.teamAssignments["123"] = "A"
+	processor.teamAssignments["456"] = "B"
+	processor.teamAssignments["789"] = "B"
+
+	fires := []types.Position{{X: 0, Y: 0}, {X: 200, Y: 0}, {X: 0, Y: 200}}
+	matchState.GrenadeEvents = []types.GrenadeEvent{
+		{RoundNumber: 1, PlayerSteamID: "123", GrenadeType: "Molotov", TickTimestamp: 900, ExplosionTick: 1000},
+		{RoundNumber: 1, PlayerSteamID: "123", GrenadeType: "HE Grenade", TickTimestamp: 900, ExplosionTick: 1000},
+	}
+	matchState.DamageEvents = []types.DamageEvent{
+		{RoundNumber: 1, TickTimestamp: 1100, AttackerSteamID: "123", VictimSteamID: "456", Damage: 40, Weapon: "Incendiary Grenade"},
+		{RoundNumber: 1, TickTimestamp: 2000, AttackerSteamID: "123", VictimSteamID: "789", Damage: 10, Weapon: "Incendiary Grenade"}, // After the fire went out
+	}
+	matchState.GunfightEvents = []types.GunfightEvent{
+		{RoundNumber: 1, TickTimestamp: 1200, Player1SteamID: "123", Player2SteamID: "456", Player2Position: types.Position{X: 100, Y: 100}},
+		{RoundNumber: 1, TickTimestamp: 1200, Player1SteamID: "123", Player2SteamID: "789", Player2Position: types.Position{X: 2000, Y: 2000}}, // Away from the fire
+	}
+
+	grenadeHandler.activeInfernos[1] = &InfernoEffect{
+		UniqueID:       1,
+		ThrowerSteamID: "123",
+		RoundNumber:    1,
+		StartTick:      1000,
+		SmokedTick:     1300,
+		MaxArea:        20000,
+		Fires:          fires,
+		LastFires:      fires,
+		inside:         make(map[string]bool),
+		displaced:      map[string]bool{"789": true},
+	}
+	processor.currentTick = 1320
+
+	grenadeHandler.ProcessInfernos()
+	grenadeHandler.AggregateAllGrenadeDamage()
+
+	if len(grenadeHandler.activeInfernos) != 0 || len(grenadeHandler.finishedInfernos) != 0 {
+		t.Error("Expected the fires of the round to be processed")
+	}
+
+	molotov := matchState.GrenadeEvents[0]
+	if molotov.BurnDurationSeconds != 5 {
+		t.Errorf("Expected a 5 second burn, got %f", molotov.BurnDurationSeconds)
+	}
+	if molotov.FireAreaCovered != 20000 {
+		t.Errorf("Expected 20000 square units covered, got %f", molotov.FireAreaCovered)
+	}
+	if molotov.EnemiesDisplaced != 2 {
+		t.Errorf("Expected the enemy that walked out and the one that burned, got %d", molotov.EnemiesDisplaced)
+	}
+	if molotov.KillsDuringBurn != 1 {
+		t.Errorf("Expected 1 kill in the fire, got %d", molotov.KillsDuringBurn)
+	}
+	if !molotov.ExtinguishedBySmoke {
+		t.Error("Expected the fire to be put out by the smoke")
+	}
+	if molotov.DamageDealt != 40 {
+		t.Errorf("Expected the incendiary damage during the burn, got %d", molotov.DamageDealt)
+	}
+	if molotov.EffectivenessRating <= 0 {
+		t.Errorf("Expected a positive molotov rating, got %d", molotov.EffectivenessRating)
+	}
+
+	if he := matchState.GrenadeEvents[1]; he.BurnDurationSeconds != 0 || he.DamageDealt != 0 {
+		t.Errorf("Expected the HE grenade to be left alone, got %+v", he)
+	}
+}
+
+func TestGrenadeHandler_SmokeInfernos(t *testing.T) {
+	matchState := &types.MatchState{
+		CurrentRound: 1,
+		Players:      make(map[string]*types.Player),
+	}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+	grenadeHandler := processor.grenadeHandler
+
+	fires := []types.Position{{X: 0, Y: 0}}
+	grenadeHandler.activeInfernos[1] = &InfernoEffect{UniqueID: 1, ThrowerTeam: common.TeamTerrorists, LastFires: fires}
+	grenadeHandler.activeInfernos[2] = &InfernoEffect{UniqueID: 2, ThrowerTeam: common.TeamCounterTerrorists, LastFires: fires}
+	grenadeHandler.activeInfernos[3] = &InfernoEffect{UniqueID: 3, ThrowerTeam: common.TeamTerrorists, LastFires: []types.Position{{X: 1000, Y: 0}}}
+	processor.currentTick = 1300
+
+	grenadeHandler.smokeInfernos(types.Position{X: 50, Y: 50}, common.TeamCounterTerrorists)
+
+	if smoked := grenadeHandler.activeInfernos[1].SmokedTick; smoked != 1300 {
+		t.Errorf("Expected the enemy fire to be smoked at tick 1300, got %d", smoked)
+	}
+	if smoked := grenadeHandler.activeInfernos[2].SmokedTick; smoked != 0 {
+		t.Errorf("Expected a smoke on the team's own fire not to count, got tick %d", smoked)
+	}
+	if smoked := grenadeHandler.activeInfernos[3].SmokedTick; smoked != 0 {
+		t.Errorf("Expected a fire out of reach not to be smoked, got tick %d", smoked)
+	}
+}
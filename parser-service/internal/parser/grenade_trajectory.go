@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+)
+
+// defaultTrajectoryPoints is the number of trajectory points kept per grenade when
+// the cap is not configured
+const defaultTrajectoryPoints = 64
+
+// grenadeFlight is the sampled flight of a grenade that has not been destroyed yet
+type grenadeFlight struct {
+	throwTick     int64
+	points        []types.TrajectoryPoint
+	bounces       int
+	bouncePending bool // The next sample is where the grenade bounced
+	detonated     bool // Smokes and flashes stop moving once they go off
+}
+
+// trajectoryPointCap returns the number of points kept per trajectory, 0 when
+// trajectories are turned off
+func (gh *GrenadeHandler) trajectoryPointCap() int {
+	config := gh.processor.config
+	if config == nil {
+		return defaultTrajectoryPoints
+	}
+	if config.Parser.GrenadeTrajectoryPoints < 1 {
+		return 0
+	}
+	return config.Parser.GrenadeTrajectoryPoints
+}
+
+// startFlight begins sampling a grenade that was just thrown
+func (gh *GrenadeHandler) startFlight(projectile *common.GrenadeProjectile) {
+	if projectile == nil || projectile.Entity == nil {
+		return
+	}
+
+	flight := &grenadeFlight{throwTick: gh.processor.currentTick}
+	if gh.trajectoryPointCap() > 0 {
+		position := projectile.Position()
+		flight.points = append(flight.points, types.TrajectoryPoint{
+			Tick:     gh.processor.currentTick,
+			Position: types.Position{X: position.X, Y: position.Y, Z: position.Z},
+		})
+	}
+	gh.flights[projectile.Entity.ID()] = flight
+}
+
+// SampleFlights records the position of every grenade in flight at the current tick
+func (gh *GrenadeHandler) SampleFlights(projectiles map[int]*common.GrenadeProjectile) {
+	if gh.trajectoryPointCap() == 0 {
+		return
+	}
+
+	for entityID, flight := range gh.flights {
+		projectile, exists := projectiles[entityID]
+		if !exists || projectile == nil || projectile.Entity == nil || flight.detonated {
+			continue
+		}
+
+		position := projectile.Position()
+		point := types.TrajectoryPoint{
+			Tick:     gh.processor.currentTick,
+			Position: types.Position{X: position.X, Y: position.Y, Z: position.Z},
+			Bounce:   flight.bouncePending,
+		}
+		flight.bouncePending = false
+
+		if last := len(flight.points) - 1; last >= 0 && flight.points[last].Tick == point.Tick {
+			flight.points[last] = point
+			continue
+		}
+		flight.points = append(flight.points, point)
+	}
+}
+
+// HandleGrenadeProjectileBounce counts a bounce and marks where it happened
+func (gh *GrenadeHandler) HandleGrenadeProjectileBounce(e events.GrenadeProjectileBounce) error {
+	if e.Projectile == nil || e.Projectile.Entity == nil {
+		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityWarning, "projectile is nil", nil).
+			WithContext("event_type", "GrenadeProjectileBounce").
+			WithContext("tick", gh.processor.currentTick)
+	}
+
+	flight, exists := gh.flights[e.Projectile.Entity.ID()]
+	if !exists {
+		return nil
+	}
+
+	if e.BounceNr > flight.bounces {
+		flight.bounces = e.BounceNr
+	}
+	flight.bouncePending = true
+
+	return nil
+}
+
+// attachFlight adds the flight of a grenade that went off to its event and stops
+// sampling it. The flight ends at the grenade's final position.
+func (gh *GrenadeHandler) attachFlight(grenadeEvent *types.GrenadeEvent, entityID int) {
+	flight, exists := gh.flights[entityID]
+	if !exists {
+		return
+	}
+	flight.detonated = true
+
+	detonationTick := grenadeEvent.ExplosionTick
+	if detonationTick == 0 {
+		detonationTick = gh.processor.currentTick
+	}
+
+	grenadeEvent.BounceCount = flight.bounces
+	if detonationTick > flight.throwTick {
+		grenadeEvent.AirtimeSeconds = gh.processor.Timing().TicksToSeconds(detonationTick - flight.throwTick)
+	}
+
+	maxPoints := gh.trajectoryPointCap()
+	if maxPoints == 0 || len(flight.points) == 0 {
+		return
+	}
+
+	points := make([]types.TrajectoryPoint, 0, len(flight.points)+1)
+	for _, point := range flight.points {
+		if point.Tick <= detonationTick {
+			points = append(points, point)
+		}
+	}
+	if grenadeEvent.GrenadeFinalPosition != nil && (len(points) == 0 || points[len(points)-1].Tick < detonationTick) {
+		points = append(points, types.TrajectoryPoint{
+			Tick:     detonationTick,
+			Position: *grenadeEvent.GrenadeFinalPosition,
+		})
+	}
+
+	grenadeEvent.Trajectory = capTrajectory(points, maxPoints)
+}
+
+// endFlight forgets a grenade whose projectile was destroyed
+func (gh *GrenadeHandler) endFlight(entityID int) {
+	delete(gh.flights, entityID)
+}
+
+// capTrajectory thins a trajectory out to at most maxPoints points. The first and
+// last points and the bounces are kept, the remaining budget is spread evenly over
+// the rest of the flight.
+func capTrajectory(points []types.TrajectoryPoint, maxPoints int) []types.TrajectoryPoint {
+	if len(points) <= maxPoints {
+		return points
+	}
+	if maxPoints < 2 {
+		return points[:maxPoints]
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	kept := 2
+	for i := 1; i < len(points)-1 && kept < maxPoints; i++ {
+		if points[i].Bounce {
+			keep[i] = true
+			kept++
+		}
+	}
+
+	// Spread what is left of the budget evenly over the points not kept yet
+	var rest []int
+	for i := range points {
+		if !keep[i] {
+			rest = append(rest, i)
+		}
+	}
+	if budget := maxPoints - kept; budget > 0 {
+		step := float64(len(rest)) / float64(budget)
+		for n := 0; n < budget; n++ {
+			keep[rest[int(float64(n)*step+step/2)]] = true
+		}
+	}
+
+	result := make([]types.TrajectoryPoint, 0, maxPoints)
+	for i, point := range points {
+		if keep[i] {
+			result = append(result, point)
+		}
+	}
+	return result
+}
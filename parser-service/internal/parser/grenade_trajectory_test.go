@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/config"
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/sirupsen/logrus"
+)
+
+func TestCapTrajectory(t *testing.T) {
+	points := make([]types.TrajectoryPoint, 20)
+	for i := range points {
+		points[i] = types.TrajectoryPoint{Tick: int64(i)}
+	}
+	points[7].Bounce = true
+	points[13].Bounce = true
+
+	capped := capTrajectory(points, 6)
+	if len(capped) != 6 {
+		t.Fatalf("Expected 6 points, got %d", len(capped))
+	}
+	if capped[0].Tick != 0 || capped[len(capped)-1].Tick != 19 {
+		t.Errorf("Expected the first and last points to be kept, got ticks %d and %d", capped[0].Tick, capped[len(capped)-1].Tick)
+	}
+
+	bounces := 0
+	for i, point := range capped {
+		if point.Bounce {
+			bounces++
+		}
+		if i > 0 && point.Tick <= capped[i-1].Tick {
+			t.Errorf("Expected points in tick order, got %d after %d", point.Tick, capped[i-1].Tick)
+		}
+	}
+	if bounces != 2 {
+		t.Errorf("Expected both bounces to be kept, got %d", bounces)
+	}
+
+	if short := capTrajectory(points[:4], 6); len(short) != 4 {
+		t.Errorf("Expected a trajectory under the cap to be kept whole, got %d points", len(short))
+	}
+}
+
+func TestGrenadeHandler_AttachFlight(t *testing.T) {
+	matchState := &types.MatchState{
+		CurrentRound:  1,
+		GrenadeEvents: []types.GrenadeEvent{},
+		Players:       make(map[string]*types.Player),
+	}
+	logger := logrus.New()
+	processor := NewEventProcessor(matchState, logger, nil, nil)
+	grenadeHandler := NewGrenadeHandler(processor, logger)
+
+	grenadeHandler.flights[42] = &grenadeFlight{
+		throwTick: 1000,
+		bounces:   1,
+		points: []types.TrajectoryPoint{
+			{Tick: 1000, Position: types.Position{X: 0}},
+			{Tick: 1032, Position: types.Position{X: 100}, Bounce: true},
+			{Tick: 1064, Position: types.Position{X: 150}},
+		},
+	}
+
+	final := types.Position{X: 160}
+	grenadeEvent := types.GrenadeEvent{ExplosionTick: 1128, GrenadeFinalPosition: &final}
+	grenadeHandler.attachFlight(&grenadeEvent, 42)
+
+	if grenadeEvent.BounceCount != 1 {
+		t.Errorf("Expected 1 bounce, got %d", grenadeEvent.BounceCount)
+	}
+	if grenadeEvent.AirtimeSeconds != 2 {
+		t.Errorf("Expected 2 seconds of airtime at 64 tick, got %f", grenadeEvent.AirtimeSeconds)
+	}
+	if len(grenadeEvent.Trajectory) != 4 {
+		t.Fatalf("Expected the sampled points and the final position, got %d points", len(grenadeEvent.Trajectory))
+	}
+	if last := grenadeEvent.Trajectory[3]; last.Tick != 1128 || last.Position != final {
+		t.Errorf("Expected the trajectory to end at the final position, got %+v", last)
+	}
+
+	// A detonated grenade is not sampled any further
+	grenadeHandler.SampleFlights(map[int]*common.GrenadeProjectile{})
+	if !grenadeHandler.flights[42].detonated {
+		t.Error("Expected the flight to be marked as detonated")
+	}
+
+	grenadeHandler.endFlight(42)
+	if _, exists := grenadeHandler.flights[42]; exists {
+		t.Error("Expected the flight to be forgotten")
+	}
+}
+
+func TestGrenadeHandler_AttachFlight_TrajectoriesOff(t *testing.T) {
+	matchState := &types.MatchState{
+		CurrentRound:  1,
+		GrenadeEvents: []types.GrenadeEvent{},
+		Players:       make(map[string]*types.Player),
+	}
+	logger := logrus.New()
+	cfg := &config.Config{Parser: config.ParserConfig{GrenadeTrajectoryPoints: 0}}
+	processor := NewEventProcessor(matchState, logger, cfg, nil)
+	grenadeHandler := NewGrenadeHandler(processor, logger)
+
+	grenadeHandler.flights[7] = &grenadeFlight{throwTick: 100, bounces: 2}
+
+	final := types.Position{X: 1}
+	grenadeEvent := types.GrenadeEvent{ExplosionTick: 164, GrenadeFinalPosition: &final}
+	grenadeHandler.attachFlight(&grenadeEvent, 7)
+
+	if grenadeEvent.Trajectory != nil {
+		t.Errorf("Expected no trajectory, got %d points", len(grenadeEvent.Trajectory))
+	}
+	if grenadeEvent.BounceCount != 2 || grenadeEvent.AirtimeSeconds != 1 {
+		t.Errorf("Expected bounces and airtime without a trajectory, got %d and %f", grenadeEvent.BounceCount, grenadeEvent.AirtimeSeconds)
+	}
+}
@@ -85,7 +85,19 @@ func (gh *GunfightHandler) HandlePlayerKilled(e events.Kill) error {
 
 	killerSteamID := types.SteamIDToString(e.Killer.SteamID64)
 	victimSteamID := types.SteamIDToString(e.Victim.SteamID64)
-	gunfightEvent.FlashAssisterSteamID = gh.processor.grenadeHandler.CheckFlashEffectiveness(killerSteamID, victimSteamID, gh.processor.currentTick)
+
+	// Without a flash assist in the kill feed a flash that happened to blind the victim
+	// had no part in the kill, so neither the flash nor its thrower is credited
+	if e.AssistedFlash {
+		gunfightEvent.FlashAssisterSteamID = gh.processor.grenadeHandler.CheckFlashEffectiveness(killerSteamID, victimSteamID, gh.processor.currentTick)
+
+		// The kill feed credits the flash assist the game awarded, which beats the guess
+		// from the flash effects
+		if e.Assister != nil {
+			assisterSteamID := types.SteamIDToString(e.Assister.SteamID64)
+			gunfightEvent.FlashAssisterSteamID = &assisterSteamID
+		}
+	}
 
 	gh.processor.matchState.GunfightEvents = append(gh.processor.matchState.GunfightEvents, gunfightEvent)
 
@@ -125,10 +137,19 @@ func (gh *GunfightHandler) createGunfightEvent(e events.Kill, isFirstKill bool)
 		Player2GrenadeValue: gh.getPlayerGrenadeValue(e.Victim),
 		Player1Position:     player1Pos,
 		Player2Position:     player2Pos,
+		Player1Place:        gh.processor.getPlayerPlace(e.Killer),
+		Player2Place:        gh.processor.getPlayerPlace(e.Victim),
 		Distance:            distance,
 		Headshot:            e.IsHeadshot,
 		Wallbang:            e.PenetratedObjects > 0,
 		PenetratedObjects:   e.PenetratedObjects,
+		ThroughSmoke:        e.ThroughSmoke,
+		NoScope:             e.NoScope,
+		AttackerBlind:       e.AttackerBlind,
+		AttackerInAir:       e.Killer.IsAirborne(),
+		VictimBlindDuration: gh.getPlayerBlindDuration(e.Victim),
+		AssistedFlash:       e.AssistedFlash,
+		DistanceBucket:      types.GetDistanceBucket(distance),
 		VictorSteamID:       nil,
 		DamageDealt:         0,
 		IsFirstKill:         isFirstKill,
@@ -165,6 +186,14 @@ func (gh *GunfightHandler) getPlayerFlashed(player *common.Player) bool {
 	return flashDuration > 0
 }
 
+// getPlayerBlindDuration returns the seconds of blindness a player has left
+func (gh *GunfightHandler) getPlayerBlindDuration(player *common.Player) float64 {
+	if player == nil || player.FlashDuration <= 0 {
+		return 0
+	}
+	return player.FlashDurationTimeRemaining().Seconds()
+}
+
 func (gh *GunfightHandler) getPlayerWeapon(player *common.Player) string {
 	if player == nil || player.ActiveWeapon() == nil {
 		return "Unknown"
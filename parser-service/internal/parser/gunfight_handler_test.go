@@ -5,6 +5,7 @@ import (
 
 	"parser-service/internal/types"
 
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
 	"github.com/sirupsen/logrus"
 )
@@ -165,3 +166,111 @@ func TestGunfightHandler_FindDamageAssist(t *testing.T) {
 	// In a real scenario, the Player objects would be properly initialized
 	t.Log("FindDamageAssist method test skipped - requires complex Player object mocking")
 }
+
+func TestGunfightHandler_HandlePlayerKilled_KillModifiers(t *testing.T) {
+	matchState := &types.MatchState{
+		CurrentRound:   1,
+		GunfightEvents: []types.GunfightEvent{},
+		Players:        make(map[string]*types.Player),
+	}
+	logger := logrus.New()
+	processor := NewEventProcessor(matchState, logger, nil, nil)
+	gunfightHandler := NewGunfightHandler(processor, logger)
+
+	provider := weaponProvider{weapon: &common.Equipment{Type: common.EqAWP}}
+	killer := common.NewPlayer(provider)
+	killer.SteamID64 = 76561198000000001
+	victim := common.NewPlayer(provider)
+	victim.SteamID64 = 76561198000000002
+	victim.FlashDuration = 2.5
+	assister := common.NewPlayer(provider)
+	assister.SteamID64 = 76561198000000003
+
+	err := gunfightHandler.HandlePlayerKilled(events.Kill{
+		Killer:        killer,
+		Victim:        victim,
+		Assister:      assister,
+		Weapon:        &common.Equipment{Type: common.EqAWP},
+		ThroughSmoke:  true,
+		NoScope:       true,
+		AttackerBlind: true,
+		AssistedFlash: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(matchState.GunfightEvents) != 1 {
+		t.Fatalf("Expected 1 gunfight event, got %d", len(matchState.GunfightEvents))
+	}
+
+	gunfight := matchState.GunfightEvents[0]
+	if !gunfight.ThroughSmoke || !gunfight.NoScope || !gunfight.AttackerBlind || !gunfight.AssistedFlash {
+		t.Errorf("Expected the kill feed modifiers to be carried over, got %+v", gunfight)
+	}
+	if gunfight.AttackerInAir {
+		t.Error("Expected the killer to be on the ground")
+	}
+	if gunfight.VictimBlindDuration != 2.5 {
+		t.Errorf("Expected 2.5 seconds of victim blindness, got %f", gunfight.VictimBlindDuration)
+	}
+	if gunfight.DistanceBucket != types.DistanceBucketClose {
+		t.Errorf("Expected a close range kill, got %q", gunfight.DistanceBucket)
+	}
+	if gunfight.FlashAssisterSteamID == nil || *gunfight.FlashAssisterSteamID != "76561198000000003" {
+		t.Errorf("Expected the kill feed flash assister, got %v", gunfight.FlashAssisterSteamID)
+	}
+}
+
+func TestGunfightHandler_HandlePlayerKilled_NoFlashAssist(t *testing.T) {
+	matchState := &types.MatchState{
+		CurrentRound: 1,
+		Players:      make(map[string]*types.Player),
+		GrenadeEvents: []types.GrenadeEvent{
+			{RoundNumber: 1, PlayerSteamID: "76561198000000003", GrenadeType: "Flashbang", ExplosionTick: 900},
+		},
+	}
+	logger := logrus.New()
+	processor := NewEventProcessor(matchState, logger, nil, nil)
+	gunfightHandler := NewGunfightHandler(processor, logger)
+
+	processor.teamAssignments["76561198000000001"] = "A"
+	processor.teamAssignments["76561198000000002"] = "B"
+	processor.teamAssignments["76561198000000003"] = "A"
+	processor.currentTick = 1000
+
+	// A teammate's flash blinded the victim, but the game did not award a flash assist
+	processor.activeFlashEffects[1] = &FlashEffect{
+		EntityID:       1,
+		ThrowerSteamID: "76561198000000003",
+		ExplosionTick:  900,
+		RoundNumber:    1,
+		AffectedPlayers: map[uint64]*PlayerFlashInfo{
+			76561198000000002: {SteamID: "76561198000000002", Team: "B"},
+		},
+	}
+
+	provider := weaponProvider{weapon: &common.Equipment{Type: common.EqAK47}}
+	killer := common.NewPlayer(provider)
+	killer.SteamID64 = 76561198000000001
+	victim := common.NewPlayer(provider)
+	victim.SteamID64 = 76561198000000002
+
+	err := gunfightHandler.HandlePlayerKilled(events.Kill{
+		Killer: killer,
+		Victim: victim,
+		Weapon: &common.Equipment{Type: common.EqAK47},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(matchState.GunfightEvents) != 1 {
+		t.Fatalf("Expected 1 gunfight event, got %d", len(matchState.GunfightEvents))
+	}
+
+	if assister := matchState.GunfightEvents[0].FlashAssisterSteamID; assister != nil {
+		t.Errorf("Expected no flash assister without a kill feed flash assist, got %s", *assister)
+	}
+	if flash := matchState.GrenadeEvents[0]; flash.FlashLeadsToKill || flash.FlashLeadsToDeath {
+		t.Errorf("Expected the flash not to be credited with the kill, got %+v", flash)
+	}
+}
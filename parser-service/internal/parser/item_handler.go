@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/sirupsen/logrus"
+)
+
+// ItemHandler records items being bought, dropped and picked up. The demo reports
+// purchases as pickups, so a pickup is a purchase when the player's money spent this
+// round went up since the previous purchase.
+type ItemHandler struct {
+	processor *EventProcessor
+	logger    *logrus.Logger
+
+	spent map[string]int            // Money spent this round already credited to purchases, by player
+	drops map[*common.Equipment]int // Index in ItemEvents of drops nobody picked up yet
+}
+
+// NewItemHandler creates a new item handler
+func NewItemHandler(processor *EventProcessor, logger *logrus.Logger) *ItemHandler {
+	return &ItemHandler{
+		processor: processor,
+		logger:    logger,
+		spent:     make(map[string]int),
+		drops:     make(map[*common.Equipment]int),
+	}
+}
+
+// ResetRound forgets the purchases and drops of the previous round
+func (ih *ItemHandler) ResetRound() {
+	ih.spent = make(map[string]int)
+	ih.drops = make(map[*common.Equipment]int)
+}
+
+// HandleItemPickup handles an item being bought or picked up
+func (ih *ItemHandler) HandleItemPickup(e events.ItemPickup) error {
+	if e.Player == nil || e.Weapon == nil || isUntrackedItem(e.Weapon) {
+		return nil
+	}
+
+	ih.recordPickup(ih.processor.actingPlayer(e.Player), e.Weapon, e.Player.MoneySpentThisRound())
+	return nil
+}
+
+// HandleItemDrop handles an item being dropped
+func (ih *ItemHandler) HandleItemDrop(e events.ItemDrop) error {
+	if e.Player == nil || e.Weapon == nil || isUntrackedItem(e.Weapon) {
+		return nil
+	}
+
+	// Thrown grenades are removed from the inventory like dropped ones, so grenade
+	// drops are only recorded while grenades cannot be thrown
+	if e.Weapon.Class() == common.EqClassGrenade && ih.processor.matchState.RoundFreezeEndTick > 0 {
+		return nil
+	}
+
+	ih.recordDrop(ih.processor.actingPlayer(e.Player), e.Weapon)
+	return nil
+}
+
+// HandleItemRefund handles an item being sold back in the buy menu
+func (ih *ItemHandler) HandleItemRefund(e events.ItemRefund) error {
+	if e.Player == nil || e.Weapon == nil || isUntrackedItem(e.Weapon) {
+		return nil
+	}
+
+	ih.recordRefund(ih.processor.actingPlayer(e.Player), e.Weapon)
+	return nil
+}
+
+// recordPickup stores a purchase or pickup. moneySpent is the money the player
+// spent so far this round, as reported by the game.
+func (ih *ItemHandler) recordPickup(player *common.Player, weapon *common.Equipment, moneySpent int) {
+	ep := ih.processor
+	steamID := types.SteamIDToString(player.SteamID64)
+	value := types.GetEquipmentValue(int(weapon.Type))
+
+	if unaccounted := moneySpent - ih.spent[steamID]; unaccounted > 0 {
+		// Items bought in the same tick share one money update, so the list price
+		// is used when the spend covers it
+		price := value
+		if price <= 0 || price > unaccounted {
+			price = unaccounted
+		}
+		ih.spent[steamID] += price
+		ih.appendItemEvent(ih.newItemEvent(types.ItemEventPurchase, player, weapon, price))
+		return
+	}
+
+	event := ih.newItemEvent(types.ItemEventPickup, player, weapon, value)
+
+	index, dropped := ih.drops[weapon]
+	if !dropped {
+		// Weapons on the ground are cleared between rounds, so anything picked up
+		// during freeze time without a drop is what the player spawned with
+		if ep.matchState.RoundFreezeEndTick == 0 {
+			return
+		}
+	} else {
+		delete(ih.drops, weapon)
+
+		if index < len(ep.matchState.ItemEvents) {
+			drop := &ep.matchState.ItemEvents[index]
+			if drop.EventType == types.ItemEventDrop && drop.PlayerSteamID != steamID && drop.PlayerSide == event.PlayerSide {
+				recipient := steamID
+				dropper := drop.PlayerSteamID
+				drop.RecipientSteamID = &recipient
+				event.DroppedBySteamID = &dropper
+			}
+		}
+	}
+
+	ih.appendItemEvent(event)
+}
+
+// recordDrop stores a drop and remembers it until somebody picks the item up
+func (ih *ItemHandler) recordDrop(player *common.Player, weapon *common.Equipment) {
+	ih.drops[weapon] = len(ih.processor.matchState.ItemEvents)
+	ih.appendItemEvent(ih.newItemEvent(types.ItemEventDrop, player, weapon, types.GetEquipmentValue(int(weapon.Type))))
+}
+
+// recordRefund stores a refund and returns its price to the money left to credit
+func (ih *ItemHandler) recordRefund(player *common.Player, weapon *common.Equipment) {
+	steamID := types.SteamIDToString(player.SteamID64)
+	price := types.GetEquipmentValue(int(weapon.Type))
+
+	ih.spent[steamID] -= price
+	if ih.spent[steamID] < 0 {
+		ih.spent[steamID] = 0
+	}
+	delete(ih.drops, weapon)
+
+	ih.appendItemEvent(ih.newItemEvent(types.ItemEventRefund, player, weapon, price))
+}
+
+func (ih *ItemHandler) newItemEvent(eventType string, player *common.Player, weapon *common.Equipment, price int) types.ItemEvent {
+	ep := ih.processor
+	return types.ItemEvent{
+		RoundNumber:   ep.matchState.CurrentRound,
+		RoundTime:     ep.getCurrentRoundTime(),
+		TickTimestamp: ep.currentTick,
+		EventType:     eventType,
+		PlayerSteamID: types.SteamIDToString(player.SteamID64),
+		PlayerSide:    ep.getTeamString(player.Team),
+		Item:          weapon.Type.String(),
+		Price:         price,
+		Position:      ep.getPlayerPosition(player),
+	}
+}
+
+func (ih *ItemHandler) appendItemEvent(event types.ItemEvent) {
+	ih.processor.matchState.ItemEvents = append(ih.processor.matchState.ItemEvents, event)
+
+	ih.logger.WithFields(logrus.Fields{
+		"round":      event.RoundNumber,
+		"event_type": event.EventType,
+		"player":     event.PlayerSteamID,
+		"item":       event.Item,
+		"price":      event.Price,
+	}).Debug("Item event")
+}
+
+// isUntrackedItem reports whether an item is left out of item events. Knives cannot
+// be bought or dropped and the bomb has its own events.
+func isUntrackedItem(weapon *common.Equipment) bool {
+	return weapon.Type == common.EqKnife || weapon.Type == common.EqBomb
+}
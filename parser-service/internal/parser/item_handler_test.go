@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newItemTestProcessor() *EventProcessor {
+	matchState := &types.MatchState{
+		Players:      make(map[string]*types.Player),
+		CurrentRound: 4,
+		ItemEvents:   make([]types.ItemEvent, 0),
+	}
+	return NewEventProcessor(matchState, logrus.New(), nil, nil)
+}
+
+func TestItemHandler_PurchaseAndDropForTeammate(t *testing.T) {
+	processor := newItemTestProcessor()
+	handler := processor.itemHandler
+
+	buyer := &common.Player{SteamID64: 76561198000000001, Team: common.TeamTerrorists}
+	receiver := &common.Player{SteamID64: 76561198000000002, Team: common.TeamTerrorists}
+	rifle := &common.Equipment{Type: common.EqAK47}
+	grenade := &common.Equipment{Type: common.EqSmoke}
+	pistol := &common.Equipment{Type: common.EqGlock}
+
+	// Both items were bought in the same tick, so they share one money update
+	handler.recordPickup(buyer, rifle, 3000)
+	handler.recordPickup(buyer, grenade, 3000)
+	require.NoError(t, handler.HandleItemDrop(events.ItemDrop{Player: buyer, Weapon: rifle}))
+	handler.recordPickup(receiver, rifle, 0)
+
+	// Spawn equipment is not recorded
+	handler.recordPickup(receiver, pistol, 0)
+
+	itemEvents := processor.matchState.ItemEvents
+	require.Len(t, itemEvents, 4)
+
+	assert.Equal(t, types.ItemEventPurchase, itemEvents[0].EventType)
+	assert.Equal(t, common.EqAK47.String(), itemEvents[0].Item)
+	assert.Equal(t, 2700, itemEvents[0].Price)
+	assert.Equal(t, "76561198000000001", itemEvents[0].PlayerSteamID)
+	assert.Equal(t, "T", itemEvents[0].PlayerSide)
+	assert.Equal(t, 4, itemEvents[0].RoundNumber)
+
+	assert.Equal(t, types.ItemEventPurchase, itemEvents[1].EventType)
+	assert.Equal(t, 300, itemEvents[1].Price)
+
+	drop := itemEvents[2]
+	assert.Equal(t, types.ItemEventDrop, drop.EventType)
+	require.NotNil(t, drop.RecipientSteamID)
+	assert.Equal(t, "76561198000000002", *drop.RecipientSteamID)
+
+	pickup := itemEvents[3]
+	assert.Equal(t, types.ItemEventPickup, pickup.EventType)
+	require.NotNil(t, pickup.DroppedBySteamID)
+	assert.Equal(t, "76561198000000001", *pickup.DroppedBySteamID)
+	assert.Equal(t, 2700, pickup.Price)
+}
+
+func TestItemHandler_EnemyPickupIsNotLinked(t *testing.T) {
+	processor := newItemTestProcessor()
+	handler := processor.itemHandler
+	processor.matchState.RoundFreezeEndTick = 1000
+
+	victim := &common.Player{SteamID64: 76561198000000001, Team: common.TeamCounterTerrorists}
+	enemy := &common.Player{SteamID64: 76561198000000002, Team: common.TeamTerrorists}
+	rifle := &common.Equipment{Type: common.EqM4A4}
+
+	handler.recordDrop(victim, rifle)
+	handler.recordPickup(enemy, rifle, 0)
+
+	itemEvents := processor.matchState.ItemEvents
+	require.Len(t, itemEvents, 2)
+	assert.Nil(t, itemEvents[0].RecipientSteamID)
+	assert.Equal(t, types.ItemEventPickup, itemEvents[1].EventType)
+	assert.Nil(t, itemEvents[1].DroppedBySteamID)
+}
+
+func TestItemHandler_RefundAndThrownGrenades(t *testing.T) {
+	processor := newItemTestProcessor()
+	handler := processor.itemHandler
+
+	player := &common.Player{SteamID64: 76561198000000001, Team: common.TeamCounterTerrorists}
+	flash := &common.Equipment{Type: common.EqFlash}
+
+	handler.recordPickup(player, flash, 200)
+	handler.recordRefund(player, flash)
+
+	// Buying it again after the refund is a new purchase
+	handler.recordPickup(player, flash, 200)
+
+	// After freeze time a grenade leaving the inventory was thrown
+	processor.matchState.RoundFreezeEndTick = 1000
+	require.NoError(t, handler.HandleItemDrop(events.ItemDrop{Player: player, Weapon: flash}))
+
+	// The bomb and knives are not items
+	require.NoError(t, handler.HandleItemPickup(events.ItemPickup{Player: player, Weapon: &common.Equipment{Type: common.EqKnife}}))
+
+	itemEvents := processor.matchState.ItemEvents
+	require.Len(t, itemEvents, 3)
+	assert.Equal(t, types.ItemEventPurchase, itemEvents[0].EventType)
+	assert.Equal(t, types.ItemEventRefund, itemEvents[1].EventType)
+	assert.Equal(t, 200, itemEvents[1].Price)
+	assert.Equal(t, types.ItemEventPurchase, itemEvents[2].EventType)
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/sirupsen/logrus"
+)
+
+// LoadoutHandler snapshots what every player carries into the round
+type LoadoutHandler struct {
+	processor *EventProcessor
+	logger    *logrus.Logger
+}
+
+// NewLoadoutHandler creates a new loadout handler
+func NewLoadoutHandler(processor *EventProcessor, logger *logrus.Logger) *LoadoutHandler {
+	return &LoadoutHandler{
+		processor: processor,
+		logger:    logger,
+	}
+}
+
+// HandleFreezetimeEnd records the loadout of every player on a team once buying is over
+func (lh *LoadoutHandler) HandleFreezetimeEnd() {
+	ep := lh.processor
+	if ep.demoParser == nil {
+		return
+	}
+
+	gameState := ep.demoParser.GameState()
+	if gameState == nil {
+		return
+	}
+
+	for _, player := range gameState.Participants().Playing() {
+		if player == nil {
+			continue
+		}
+		lh.recordLoadout(player)
+	}
+}
+
+// recordLoadout stores the loadout a player carries at the current tick
+func (lh *LoadoutHandler) recordLoadout(player *common.Player) {
+	ep := lh.processor
+
+	side := ep.getTeamString(player.Team)
+	if side != "CT" && side != "T" {
+		return
+	}
+
+	loadout := inventoryLoadout(player.Weapons(), int(player.FlashbangCount()))
+	loadout.RoundNumber = ep.matchState.CurrentRound
+	loadout.TickTimestamp = ep.currentTick
+	loadout.PlayerSteamID = types.SteamIDToString(player.SteamID64)
+	loadout.PlayerSide = side
+	loadout.Armor = player.Armor()
+	loadout.HasHelmet = player.HasHelmet()
+	loadout.HasDefuseKit = player.HasDefuseKit()
+	loadout.MoneyLeft = player.Money()
+
+	// Prefer the game's own valuation, which includes armor and kit
+	if value := player.EquipmentValueFreezeTimeEnd(); value > 0 {
+		loadout.EquipmentValue = value
+	} else if value := player.EquipmentValueCurrent(); value > 0 {
+		loadout.EquipmentValue = value
+	}
+
+	ep.matchState.LoadoutEvents = append(ep.matchState.LoadoutEvents, loadout)
+
+	lh.logger.WithFields(logrus.Fields{
+		"round":     loadout.RoundNumber,
+		"player":    loadout.PlayerSteamID,
+		"primary":   loadout.Primary,
+		"secondary": loadout.Secondary,
+		"value":     loadout.EquipmentValue,
+	}).Debug("Loadout recorded")
+}
+
+// inventoryLoadout sorts an inventory into loadout slots. flashbangs is the number of
+// flashbangs carried, which the inventory holds as a single item. The equipment
+// value is estimated from list prices and replaced by the game's value when known.
+func inventoryLoadout(weapons []*common.Equipment, flashbangs int) types.LoadoutEvent {
+	loadout := types.LoadoutEvent{
+		Grenades: make(map[string]int),
+	}
+
+	for _, weapon := range weapons {
+		if weapon == nil {
+			continue
+		}
+
+		value := types.GetEquipmentValue(int(weapon.Type))
+
+		switch weapon.Class() {
+		case common.EqClassRifle, common.EqClassSMG, common.EqClassHeavy:
+			loadout.Primary = weapon.Type.String()
+		case common.EqClassPistols:
+			loadout.Secondary = weapon.Type.String()
+		case common.EqClassGrenade:
+			count := 1
+			if weapon.Type == common.EqFlash && flashbangs > 1 {
+				count = flashbangs
+			}
+			loadout.Grenades[weapon.Type.String()] += count
+			loadout.GrenadeValue += value * count
+			value *= count
+		case common.EqClassEquipment:
+			if weapon.Type == common.EqZeus {
+				loadout.HasZeus = true
+			}
+		}
+
+		loadout.EquipmentValue += value
+	}
+
+	return loadout
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventoryLoadout(t *testing.T) {
+	weapons := []*common.Equipment{
+		{Type: common.EqKnife},
+		{Type: common.EqAK47},
+		{Type: common.EqGlock},
+		{Type: common.EqFlash},
+		{Type: common.EqSmoke},
+		{Type: common.EqZeus},
+		nil,
+	}
+
+	loadout := inventoryLoadout(weapons, 2)
+
+	assert.Equal(t, common.EqAK47.String(), loadout.Primary)
+	assert.Equal(t, common.EqGlock.String(), loadout.Secondary)
+	assert.Equal(t, map[string]int{common.EqFlash.String(): 2, common.EqSmoke.String(): 1}, loadout.Grenades)
+	assert.Equal(t, 2*200+300, loadout.GrenadeValue)
+	assert.True(t, loadout.HasZeus)
+	assert.Equal(t, 2700+200+2*200+300+types.GetEquipmentValue(int(common.EqZeus)), loadout.EquipmentValue)
+}
+
+func TestLoadoutHandler_RecordLoadout(t *testing.T) {
+	matchState := &types.MatchState{
+		Players:       make(map[string]*types.Player),
+		CurrentRound:  6,
+		LoadoutEvents: make([]types.LoadoutEvent, 0),
+	}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+	processor.currentTick = 5000
+
+	player := &common.Player{
+		SteamID64: 76561198000000001,
+		Team:      common.TeamCounterTerrorists,
+		Inventory: map[int]*common.Equipment{
+			1: {Type: common.EqMP9},
+			2: {Type: common.EqUSP},
+			3: {Type: common.EqHE},
+		},
+	}
+	spectator := &common.Player{SteamID64: 76561198000000002, Team: common.TeamSpectators}
+
+	processor.loadoutHandler.recordLoadout(player)
+	processor.loadoutHandler.recordLoadout(spectator)
+
+	require.Len(t, matchState.LoadoutEvents, 1)
+	loadout := matchState.LoadoutEvents[0]
+	assert.Equal(t, 6, loadout.RoundNumber)
+	assert.Equal(t, int64(5000), loadout.TickTimestamp)
+	assert.Equal(t, "76561198000000001", loadout.PlayerSteamID)
+	assert.Equal(t, "CT", loadout.PlayerSide)
+	assert.Equal(t, common.EqMP9.String(), loadout.Primary)
+	assert.Equal(t, common.EqUSP.String(), loadout.Secondary)
+	assert.Equal(t, map[string]int{common.EqHE.String(): 1}, loadout.Grenades)
+	assert.False(t, loadout.HasZeus)
+}
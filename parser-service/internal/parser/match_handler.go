@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"math"
+
 	"parser-service/internal/types"
 
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
@@ -48,6 +50,22 @@ func (mh *MatchHandler) HandleRoundStart(e events.RoundStart) error {
 	mh.processor.currentRound = mh.processor.matchState.CurrentRound // Track current round for team assignment
 	mh.processor.matchState.RoundStartTick = mh.processor.currentTick
 	mh.processor.matchState.CurrentRoundKills = 0
+	mh.resetRoundTiming()
+	if mh.processor.matchState.MatchStartTick == 0 {
+		mh.processor.matchState.MatchStartTick = mh.processor.currentTick
+	}
+
+	// Team entities know which players are on which side; the round schedule is
+	// only used when they cannot tell us
+	if synced, switched := mh.processor.syncTeamSidesFromGameState(); !synced {
+		mh.checkForSideSwitch()
+	} else if switched {
+		mh.logger.WithFields(logrus.Fields{
+			"round":               mh.processor.currentRound,
+			"team_a_current_side": mh.processor.teamACurrentSide,
+			"team_b_current_side": mh.processor.teamBCurrentSide,
+		}).Info("Team sides updated from team entities")
+	}
 	mh.processor.matchState.CurrentRoundDeaths = 0
 
 	// Update movement service with new round number
@@ -100,20 +118,20 @@ func (mh *MatchHandler) HandleRoundEnd(e events.RoundEnd) error {
 		mh.logger.WithField("winner_team", e.Winner).Warn("Unknown winner team")
 	}
 
-	duration := 120
-
 	roundEvent := types.RoundEvent{
 		RoundNumber:   mh.processor.matchState.CurrentRound,
 		TickTimestamp: mh.processor.currentTick,
 		EventType:     "end",
 		Winner:        &winner,
-		Duration:      &duration,
 	}
+	mh.applyRoundTiming(&roundEvent, e.Reason)
+	mh.processor.matchState.MatchEndTick = mh.processor.currentTick
+
 	mh.processor.matchState.RoundEvents = append(mh.processor.matchState.RoundEvents, roundEvent)
 
 	// Update team wins for determining the winning team
 	if winner != "Unknown" {
-		mh.updateTeamWins(winner)
+		mh.updateTeamWins(winner, e.WinnerState)
 	}
 
 	mh.logger.WithFields(logrus.Fields{
@@ -133,6 +151,13 @@ func (mh *MatchHandler) HandleBombPlanted(e events.BombPlanted) error {
 			WithContext("event", "BombPlanted")
 	}
 
+	if mh.processor.matchState != nil {
+		mh.processor.matchState.RoundBombPlantTick = mh.processor.currentTick
+		if e.Site != 0 {
+			mh.processor.matchState.RoundBombSite = string(rune(e.Site))
+		}
+	}
+
 	mh.logger.Debug("Bomb planted")
 	return nil
 }
@@ -144,6 +169,10 @@ func (mh *MatchHandler) HandleBombDefused(e events.BombDefused) error {
 			WithContext("event", "BombDefused")
 	}
 
+	if mh.processor.matchState != nil {
+		mh.processor.matchState.RoundBombDefuseTick = mh.processor.currentTick
+	}
+
 	mh.logger.Debug("Bomb defused")
 	return nil
 }
@@ -155,10 +184,123 @@ func (mh *MatchHandler) HandleBombExplode(e events.BombExplode) error {
 			WithContext("event", "BombExplode")
 	}
 
+	if mh.processor.matchState != nil {
+		mh.processor.matchState.RoundBombExplodeTick = mh.processor.currentTick
+	}
+
 	mh.logger.Debug("Bomb exploded")
 	return nil
 }
 
+// HandleRoundFreezetimeEnd records when the round went live
+func (mh *MatchHandler) HandleRoundFreezetimeEnd(e events.RoundFreezetimeEnd) error {
+	if mh.processor == nil || mh.processor.matchState == nil {
+		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityCritical, "match state is nil", nil).
+			WithContext("event", "RoundFreezetimeEnd")
+	}
+
+	mh.processor.matchState.RoundFreezeEndTick = mh.processor.currentTick
+	return nil
+}
+
+// HandleMatchStart records when the match went live. Restarts announce the start
+// again, so the latest one wins.
+func (mh *MatchHandler) HandleMatchStart(e events.MatchStart) error {
+	if mh.processor == nil || mh.processor.matchState == nil {
+		return types.NewParseErrorWithSeverity(types.ErrorTypeEventProcessing, types.ErrorSeverityCritical, "match state is nil", nil).
+			WithContext("event", "MatchStart")
+	}
+
+	mh.processor.matchState.MatchStartTick = mh.processor.currentTick
+	return nil
+}
+
+// resetRoundTiming clears the timing of the previous round
+func (mh *MatchHandler) resetRoundTiming() {
+	matchState := mh.processor.matchState
+	matchState.RoundFreezeEndTick = 0
+	matchState.RoundBombPlantTick = 0
+	matchState.RoundBombSite = ""
+	matchState.RoundBombDefuseTick = 0
+	matchState.RoundBombExplodeTick = 0
+}
+
+// applyRoundTiming fills in the live duration, end reason and bomb phase of a round end event
+func (mh *MatchHandler) applyRoundTiming(roundEvent *types.RoundEvent, reason events.RoundEndReason) {
+	matchState := mh.processor.matchState
+	timing := mh.processor.Timing()
+	endTick := mh.processor.currentTick
+
+	// Live play starts when freeze time ends; fall back to the round start if it was not seen
+	liveStartTick := matchState.RoundStartTick
+	if matchState.RoundFreezeEndTick >= matchState.RoundStartTick && matchState.RoundFreezeEndTick > 0 {
+		freezeEndTick := matchState.RoundFreezeEndTick
+		roundEvent.FreezeEndTick = &freezeEndTick
+		liveStartTick = freezeEndTick
+	}
+
+	duration := 0
+	if endTick > liveStartTick {
+		duration = int(math.Round(timing.TicksToSeconds(endTick - liveStartTick)))
+	}
+	roundEvent.Duration = &duration
+
+	endReason := roundEndReasonString(reason)
+	roundEvent.EndReason = &endReason
+
+	if matchState.RoundBombPlantTick == 0 {
+		return
+	}
+
+	plantTick := matchState.RoundBombPlantTick
+	plantTime := timing.TicksToSeconds(plantTick - liveStartTick)
+	roundEvent.BombPlantTick = &plantTick
+	roundEvent.BombPlantTime = &plantTime
+	if matchState.RoundBombSite != "" {
+		site := matchState.RoundBombSite
+		roundEvent.BombSite = &site
+	}
+
+	phaseEndTick := endTick
+	if matchState.RoundBombDefuseTick > 0 {
+		defuseTick := matchState.RoundBombDefuseTick
+		roundEvent.BombDefuseTick = &defuseTick
+		phaseEndTick = defuseTick
+	}
+	if matchState.RoundBombExplodeTick > 0 {
+		explodeTick := matchState.RoundBombExplodeTick
+		roundEvent.BombExplodeTick = &explodeTick
+		phaseEndTick = explodeTick
+	}
+
+	bombPhase := timing.TicksToSeconds(phaseEndTick - plantTick)
+	roundEvent.BombPhaseDuration = &bombPhase
+}
+
+// roundEndReasonString maps the game's round end reason to a RoundEvent end reason
+func roundEndReasonString(reason events.RoundEndReason) string {
+	switch reason {
+	case events.RoundEndReasonCTWin, events.RoundEndReasonTerroristsWin:
+		return types.RoundEndReasonElimination
+	case events.RoundEndReasonTargetBombed:
+		return types.RoundEndReasonBombExploded
+	case events.RoundEndReasonBombDefused:
+		return types.RoundEndReasonBombDefused
+	case events.RoundEndReasonTargetSaved, events.RoundEndReasonHostagesNotRescued:
+		return types.RoundEndReasonTimeExpired
+	case events.RoundEndReasonTerroristsSurrender, events.RoundEndReasonCTSurrender:
+		return types.RoundEndReasonSurrender
+	case events.RoundEndReasonHostagesRescued:
+		return types.RoundEndReasonHostagesRescued
+	case events.RoundEndReasonDraw:
+		return types.RoundEndReasonDraw
+	case events.RoundEndReasonGameStart:
+		return types.RoundEndReasonGameStart
+	default:
+		return types.RoundEndReasonOther
+	}
+}
+
 // HandlePlayerConnect handles player connect events
 func (mh *MatchHandler) HandlePlayerConnect(e events.PlayerConnect) error {
 	if mh.processor == nil {
@@ -180,7 +322,7 @@ func (mh *MatchHandler) HandlePlayerConnect(e events.PlayerConnect) error {
 	side := mh.getTeamString(e.Player.Team)
 
 	// Assign team based on rounds 1-12
-	mh.processor.assignTeamBasedOnRound1To12(steamID, side)
+	mh.processor.assignTeam(steamID, side)
 	assignedTeam := mh.processor.getAssignedTeam(steamID)
 
 	// Add player to match state if not already present
@@ -189,6 +331,7 @@ func (mh *MatchHandler) HandlePlayerConnect(e events.PlayerConnect) error {
 			SteamID: steamID,
 			Name:    e.Player.Name,
 			Team:    assignedTeam,
+			IsBot:   e.Player.IsBot,
 		}
 	}
 
@@ -201,6 +344,9 @@ func (mh *MatchHandler) HandlePlayerConnect(e events.PlayerConnect) error {
 		}
 	}
 
+	// A reconnecting player keeps their slot, a new one may be a substitute
+	mh.processor.rosterJoined(e.Player)
+
 	mh.logger.WithFields(logrus.Fields{
 		"steam_id":      steamID,
 		"name":          e.Player.Name,
@@ -225,6 +371,8 @@ func (mh *MatchHandler) HandlePlayerDisconnected(e events.PlayerDisconnected) er
 
 	steamID := types.SteamIDToString(e.Player.SteamID64)
 
+	mh.processor.rosterLeft(e.Player)
+
 	mh.logger.WithFields(logrus.Fields{
 		"steam_id": steamID,
 		"name":     e.Player.Name,
@@ -253,8 +401,8 @@ func (mh *MatchHandler) HandlePlayerTeamChange(e events.PlayerTeamChange) error
 	steamID := types.SteamIDToString(e.Player.SteamID64)
 	side := mh.getTeamString(e.Player.Team)
 
-	// Assign team based on rounds 1-12 (if not already assigned)
-	mh.processor.assignTeamBasedOnRound1To12(steamID, side)
+	// Assign a team letter if the player has not been seen before
+	mh.processor.assignTeam(steamID, side)
 	assignedTeam := mh.processor.getAssignedTeam(steamID)
 
 	// Update player in match state
@@ -267,6 +415,13 @@ func (mh *MatchHandler) HandlePlayerTeamChange(e events.PlayerTeamChange) error
 		playerState.Team = assignedTeam
 	}
 
+	// Moving to spectators frees the slot just like disconnecting
+	if side == "CT" || side == "T" {
+		mh.processor.rosterJoined(e.Player)
+	} else {
+		mh.processor.rosterLeft(e.Player)
+	}
+
 	mh.logger.WithFields(logrus.Fields{
 		"steam_id":      steamID,
 		"name":          e.Player.Name,
@@ -326,46 +481,44 @@ func (mh *MatchHandler) getTeamString(team common.Team) string {
 }
 
 // updateTeamWins updates the win count for the appropriate team based on round winner
-func (mh *MatchHandler) updateTeamWins(winner string) {
-	// Check for side switches before updating wins
-	mh.checkForSideSwitch()
+func (mh *MatchHandler) updateTeamWins(winner string, winnerState *common.TeamState) {
+	// The winning team entity's members identify the team letter directly
+	if winnerState != nil {
+		if team := mh.processor.teamFromMembers(winnerState.Members()); team != "" {
+			mh.processor.setTeamSide(team, winner)
+		}
+	}
+
+	team := mh.processor.teamOnSide(winner)
+	if team == "" {
+		return
+	}
 
-	if winner == mh.processor.teamACurrentSide {
+	if mh.processor.roundWinners != nil {
+		mh.processor.roundWinners[mh.processor.matchState.CurrentRound] = team
+	}
+	if team == "A" {
 		mh.processor.teamAWins++
-	} else if winner == mh.processor.teamBCurrentSide {
+	} else {
 		mh.processor.teamBWins++
 	}
 }
 
-// checkForSideSwitch handles halftime and overtime side switches
+// checkForSideSwitch swaps sides when the match rules schedule a halftime or
+// overtime switch for the current round
 func (mh *MatchHandler) checkForSideSwitch() {
-	// Halftime switch: after round 12, teams switch sides
-	if mh.processor.currentRound == 13 {
-		mh.switchTeamSides()
-		mh.logger.Info("Halftime switch occurred", logrus.Fields{
-			"round":               mh.processor.currentRound,
-			"team_a_current_side": mh.processor.teamACurrentSide,
-			"team_b_current_side": mh.processor.teamBCurrentSide,
-		})
+	if !mh.processor.matchRules.IsSideSwitchRound(mh.processor.currentRound) {
 		return
 	}
 
-	// Overtime switches: every 3 rounds after round 24
-	if mh.processor.currentRound > 24 {
-		// Calculate which overtime period we're in
-		overtimeRounds := mh.processor.currentRound - 24
-		// Switch sides at the start of each new overtime period (rounds 28, 31, 34, etc.)
-		// This means: overtime rounds 4, 7, 10, etc. (every 3rd overtime round starting from round 4)
-		if overtimeRounds%3 == 1 && overtimeRounds > 3 {
-			mh.switchTeamSides()
-			mh.logger.Info("Overtime side switch occurred", logrus.Fields{
-				"round":               mh.processor.currentRound,
-				"overtime_round":      overtimeRounds,
-				"team_a_current_side": mh.processor.teamACurrentSide,
-				"team_b_current_side": mh.processor.teamBCurrentSide,
-			})
-		}
-	}
+	mh.switchTeamSides()
+	mh.logger.WithFields(logrus.Fields{
+		"round":               mh.processor.currentRound,
+		"max_rounds":          mh.processor.matchRules.MaxRounds,
+		"overtime_max_rounds": mh.processor.matchRules.OvertimeMaxRounds,
+		"team_a_current_side": mh.processor.teamACurrentSide,
+		"team_b_current_side": mh.processor.teamBCurrentSide,
+	}).Info("Side switch occurred")
 }
 
 // switchTeamSides swaps the current sides of both teams
@@ -785,3 +785,58 @@ func TestMatchHandler_HandleWeaponFire_ErrorScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchHandler_RoundTiming(t *testing.T) {
+	logger := logrus.New()
+	matchState := &types.MatchState{
+		Players:     make(map[string]*types.Player),
+		RoundEvents: make([]types.RoundEvent, 0),
+	}
+	processor := NewEventProcessor(matchState, logger, nil, nil)
+	processor.SetTickRate(128)
+	handler := NewMatchHandler(processor, logger)
+
+	processor.currentTick = 1000
+	if err := handler.HandleMatchStart(events.MatchStart{}); err != nil {
+		t.Fatalf("HandleMatchStart returned error: %v", err)
+	}
+	if err := handler.HandleRoundStart(events.RoundStart{}); err != nil {
+		t.Fatalf("HandleRoundStart returned error: %v", err)
+	}
+
+	// 15 seconds of freeze time, plant 40 seconds in, explosion 40 seconds later
+	processor.currentTick = 1000 + 15*128
+	_ = handler.HandleRoundFreezetimeEnd(events.RoundFreezetimeEnd{})
+	processor.currentTick += 40 * 128
+	_ = handler.HandleBombPlanted(events.BombPlanted{BombEvent: events.BombEvent{Site: events.BombsiteA}})
+	processor.currentTick += 40 * 128
+	_ = handler.HandleBombExplode(events.BombExplode{})
+	processor.currentTick += 64
+
+	if err := handler.HandleRoundEnd(events.RoundEnd{Winner: common.TeamTerrorists, Reason: events.RoundEndReasonTargetBombed}); err != nil {
+		t.Fatalf("HandleRoundEnd returned error: %v", err)
+	}
+
+	roundEvent := matchState.RoundEvents[len(matchState.RoundEvents)-1]
+	if roundEvent.Duration == nil || *roundEvent.Duration != 81 {
+		t.Errorf("Expected live duration of 81 seconds, got %v", roundEvent.Duration)
+	}
+	if roundEvent.EndReason == nil || *roundEvent.EndReason != types.RoundEndReasonBombExploded {
+		t.Errorf("Expected end reason %q, got %v", types.RoundEndReasonBombExploded, roundEvent.EndReason)
+	}
+	if roundEvent.FreezeEndTick == nil || *roundEvent.FreezeEndTick != 1000+15*128 {
+		t.Errorf("Expected freeze end tick %d, got %v", 1000+15*128, roundEvent.FreezeEndTick)
+	}
+	if roundEvent.BombPlantTime == nil || *roundEvent.BombPlantTime != 40 {
+		t.Errorf("Expected bomb plant time of 40 seconds, got %v", roundEvent.BombPlantTime)
+	}
+	if roundEvent.BombSite == nil || *roundEvent.BombSite != "A" {
+		t.Errorf("Expected bomb site A, got %v", roundEvent.BombSite)
+	}
+	if roundEvent.BombPhaseDuration == nil || *roundEvent.BombPhaseDuration != 40 {
+		t.Errorf("Expected bomb phase of 40 seconds, got %v", roundEvent.BombPhaseDuration)
+	}
+	if matchState.MatchStartTick != 1000 || matchState.MatchEndTick != processor.currentTick {
+		t.Errorf("Expected match to span ticks 1000-%d, got %d-%d", processor.currentTick, matchState.MatchStartTick, matchState.MatchEndTick)
+	}
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"parser-service/internal/types"
+)
+
+// Overtime defaults used when the demo does not report mp_overtime_maxrounds
+const defaultOvertimeMaxRounds = 6
+
+// MatchRules describes the round structure of a match: regulation length, whether
+// teams swap at halftime and how long each overtime lasts
+type MatchRules struct {
+	MaxRounds         int
+	HasHalftime       bool
+	OvertimeMaxRounds int
+}
+
+// DefaultMatchRules returns the MR12 rules with MR3 overtime used by Premier
+func DefaultMatchRules() MatchRules {
+	return MatchRules{
+		MaxRounds:         24,
+		HasHalftime:       true,
+		OvertimeMaxRounds: defaultOvertimeMaxRounds,
+	}
+}
+
+// NewMatchRules derives the rules from the detected game mode. Convars from the demo
+// take precedence, so custom mp_maxrounds and mp_overtime_maxrounds servers are honoured.
+func NewMatchRules(gameMode *types.GameMode, conVars map[string]string) MatchRules {
+	rules := DefaultMatchRules()
+
+	if gameMode != nil && gameMode.MaxRounds > 0 {
+		rules.MaxRounds = gameMode.MaxRounds
+		rules.HasHalftime = gameMode.HasHalftime
+	}
+
+	if maxRounds, ok := parseConVarInt(conVars, "mp_maxrounds"); ok && maxRounds > 0 {
+		rules.MaxRounds = maxRounds
+	}
+
+	if halftime, ok := parseConVarBool(conVars, "mp_halftime"); ok {
+		rules.HasHalftime = halftime
+	}
+
+	if overtimeRounds, ok := parseConVarInt(conVars, "mp_overtime_maxrounds"); ok && overtimeRounds > 0 {
+		rules.OvertimeMaxRounds = overtimeRounds
+	}
+
+	return rules
+}
+
+// IsSideSwitchRound reports whether teams play the given round on the opposite side
+// to the round before it
+func (r MatchRules) IsSideSwitchRound(round int) bool {
+	if round <= 1 || r.MaxRounds < 2 {
+		return false
+	}
+
+	if round <= r.MaxRounds {
+		return r.HasHalftime && round == r.MaxRounds/2+1
+	}
+
+	// Teams keep their sides into overtime and swap at every overtime half after that
+	overtimeHalf := r.OvertimeMaxRounds / 2
+	if overtimeHalf < 1 {
+		return false
+	}
+
+	overtimeRound := round - r.MaxRounds
+	return overtimeRound > overtimeHalf && (overtimeRound-1)%overtimeHalf == 0
+}
+
+// IsPistolRound reports whether the round opens a regulation half, when every
+// player starts again with the starting money
+func (r MatchRules) IsPistolRound(round int) bool {
+	return round == 1 || (round <= r.MaxRounds && r.IsSideSwitchRound(round))
+}
+
+// IsEconomyResetRound reports whether money and loss bonuses are reset before the
+// round: at the start of each regulation half and of each overtime half
+func (r MatchRules) IsEconomyResetRound(round int) bool {
+	if r.IsPistolRound(round) {
+		return true
+	}
+
+	overtimeHalf := r.OvertimeMaxRounds / 2
+	if round <= r.MaxRounds || overtimeHalf < 1 {
+		return false
+	}
+	return (round-r.MaxRounds-1)%overtimeHalf == 0
+}
+
+func parseConVarInt(conVars map[string]string, name string) (int, bool) {
+	value, exists := conVars[name]
+	if !exists {
+		return 0, false
+	}
+
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+
+	return parsed, true
+}
+
+func parseConVarBool(conVars map[string]string, name string) (bool, bool) {
+	value, exists := conVars[name]
+	if !exists {
+		return false, false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true":
+		return true, true
+	case "0", "false":
+		return false, true
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func sideSwitchRounds(rules MatchRules, lastRound int) []int {
+	var rounds []int
+	for round := 1; round <= lastRound; round++ {
+		if rules.IsSideSwitchRound(round) {
+			rounds = append(rounds, round)
+		}
+	}
+	return rounds
+}
+
+func TestMatchRules_IsSideSwitchRound(t *testing.T) {
+	tests := []struct {
+		name      string
+		gameMode  *types.GameMode
+		conVars   map[string]string
+		lastRound int
+		expected  []int
+	}{
+		{
+			name:      "premier MR12 with MR3 overtime",
+			gameMode:  &types.GameMode{Mode: "premier", MaxRounds: 24, HasHalftime: true},
+			lastRound: 36,
+			expected:  []int{13, 28, 31, 34},
+		},
+		{
+			name:      "legacy MR15",
+			conVars:   map[string]string{"mp_maxrounds": "30", "mp_halftime": "1"},
+			lastRound: 30,
+			expected:  []int{16},
+		},
+		{
+			name:      "wingman MR8",
+			gameMode:  &types.GameMode{Mode: "wingman", MaxRounds: 16, HasHalftime: true},
+			lastRound: 16,
+			expected:  []int{9},
+		},
+		{
+			name:      "custom rounds and overtime length",
+			conVars:   map[string]string{"mp_maxrounds": "20", "mp_overtime_maxrounds": "10"},
+			lastRound: 40,
+			expected:  []int{11, 26, 31, 36},
+		},
+		{
+			name:      "no halftime",
+			conVars:   map[string]string{"mp_maxrounds": "30", "mp_halftime": "false"},
+			lastRound: 30,
+			expected:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := NewMatchRules(tt.gameMode, tt.conVars)
+			assert.Equal(t, tt.expected, sideSwitchRounds(rules, tt.lastRound))
+		})
+	}
+}
+
+func TestMatchRules_EconomyResetRounds(t *testing.T) {
+	rules := DefaultMatchRules()
+
+	var pistolRounds, resetRounds []int
+	for round := 1; round <= 36; round++ {
+		if rules.IsPistolRound(round) {
+			pistolRounds = append(pistolRounds, round)
+		}
+		if rules.IsEconomyResetRound(round) {
+			resetRounds = append(resetRounds, round)
+		}
+	}
+
+	assert.Equal(t, []int{1, 13}, pistolRounds)
+	assert.Equal(t, []int{1, 13, 25, 28, 31, 34}, resetRounds)
+}
+
+func TestEventProcessor_AssignTeamAfterHalftime(t *testing.T) {
+	processor := NewEventProcessor(&types.MatchState{Players: make(map[string]*types.Player)}, logrus.New(), nil, nil)
+
+	assert.NoError(t, processor.assignTeam("ct-player", "CT"))
+	assert.NoError(t, processor.assignTeam("t-player", "T"))
+	assert.Equal(t, "A", processor.getAssignedTeam("ct-player"))
+	assert.Equal(t, "B", processor.getAssignedTeam("t-player"))
+
+	// A substitute joining on CT in the second half belongs to team B
+	processor.currentRound = 15
+	processor.setTeamSide("A", "T")
+	assert.NoError(t, processor.assignTeam("substitute", "CT"))
+	assert.Equal(t, "B", processor.getAssignedTeam("substitute"))
+}
+
+func TestMatchHandler_UpdateTeamWinsFromWinnerState(t *testing.T) {
+	logger := logrus.New()
+	processor := NewEventProcessor(&types.MatchState{Players: make(map[string]*types.Player)}, logger, nil, nil)
+	processor.teamAssignments["1"] = "A"
+	processor.teamAssignments["2"] = "B"
+	processor.teamACurrentSide = "CT"
+	processor.teamBCurrentSide = "T"
+
+	// Team A won on T, so the sides switched without the schedule predicting it
+	teamAMembers := []*common.Player{{SteamID64: 1}}
+	winnerState := common.NewTeamState(common.TeamTerrorists, func(common.Team) []*common.Player { return teamAMembers }, nil)
+
+	handler := NewMatchHandler(processor, logger)
+	assert.NoError(t, handler.HandleRoundEnd(events.RoundEnd{Winner: common.TeamTerrorists, WinnerState: &winnerState}))
+
+	assert.Equal(t, 1, processor.teamAWins)
+	assert.Equal(t, 0, processor.teamBWins)
+	assert.Equal(t, "T", processor.teamACurrentSide)
+	assert.Equal(t, "CT", processor.teamBCurrentSide)
+}
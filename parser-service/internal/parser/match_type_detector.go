@@ -0,0 +1,286 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"parser-service/internal/config"
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs"
+	"github.com/sirupsen/logrus"
+)
+
+// MatchTypeSignals is everything the match type rules can look at
+type MatchTypeSignals struct {
+	ServerName string
+	ConVars    map[string]string
+	// RankTypeCounts counts players per rank type; RankedPlayers counts players
+	// with an actual competitive rank
+	RankTypeCounts map[int]int
+	RankedPlayers  int
+}
+
+// MatchTypeRule recognises one platform. Match returns the evidence it found, or
+// false when the rule does not apply.
+type MatchTypeRule struct {
+	Name       string
+	MatchType  string
+	Confidence float64
+	Match      func(signals MatchTypeSignals) (string, bool)
+}
+
+// MatchTypeDetector evaluates a registry of rules. Rules agreeing on a match type
+// reinforce each other; the type with the highest combined confidence wins.
+type MatchTypeDetector struct {
+	logger *logrus.Logger
+	rules  []MatchTypeRule
+}
+
+// NewMatchTypeDetector creates a detector with the built in rules followed by the
+// configured ones
+func NewMatchTypeDetector(logger *logrus.Logger, configured []config.MatchTypeRuleConfig) *MatchTypeDetector {
+	detector := &MatchTypeDetector{logger: logger}
+
+	for _, rule := range DefaultMatchTypeRules() {
+		detector.Register(rule)
+	}
+	for _, ruleConfig := range configured {
+		detector.Register(matchTypeRuleFromConfig(ruleConfig))
+	}
+
+	return detector
+}
+
+// Register adds a rule to the registry
+func (d *MatchTypeDetector) Register(rule MatchTypeRule) {
+	if rule.Match == nil || rule.MatchType == "" {
+		return
+	}
+	d.rules = append(d.rules, rule)
+}
+
+// Detect returns the most likely match type with its confidence and the evidence
+// that supports it
+func (d *MatchTypeDetector) Detect(signals MatchTypeSignals) *types.MatchTypeDetection {
+	type candidate struct {
+		order      int
+		remaining  float64 // Probability that every matching rule is wrong
+		evidence   []string
+		confidence float64
+	}
+
+	candidates := make(map[string]*candidate)
+	for i, rule := range d.rules {
+		evidence, matched := rule.Match(signals)
+		if !matched {
+			continue
+		}
+
+		c, exists := candidates[rule.MatchType]
+		if !exists {
+			c = &candidate{order: i, remaining: 1}
+			candidates[rule.MatchType] = c
+		}
+		c.remaining *= 1 - clampConfidence(rule.Confidence)
+		c.evidence = append(c.evidence, evidence)
+	}
+
+	if len(candidates) == 0 {
+		return &types.MatchTypeDetection{MatchType: types.MatchTypeUnknown, Evidence: []string{}}
+	}
+
+	matchTypes := make([]string, 0, len(candidates))
+	for matchType, c := range candidates {
+		c.confidence = 1 - c.remaining
+		matchTypes = append(matchTypes, matchType)
+	}
+
+	// Earlier rules are more specific, so they break ties
+	sort.Slice(matchTypes, func(i, j int) bool {
+		a, b := candidates[matchTypes[i]], candidates[matchTypes[j]]
+		if a.confidence != b.confidence {
+			return a.confidence > b.confidence
+		}
+		return a.order < b.order
+	})
+
+	best := candidates[matchTypes[0]]
+	detection := &types.MatchTypeDetection{
+		MatchType:  matchTypes[0],
+		Confidence: best.confidence,
+		Evidence:   best.evidence,
+	}
+
+	if d.logger != nil {
+		d.logger.WithFields(logrus.Fields{
+			"match_type": detection.MatchType,
+			"confidence": detection.Confidence,
+			"evidence":   detection.Evidence,
+		}).Debug("Match type detected")
+	}
+
+	return detection
+}
+
+// DefaultMatchTypeRules returns the built in rules, most specific first
+func DefaultMatchTypeRules() []MatchTypeRule {
+	return []MatchTypeRule{
+		serverNameRule("faceit_server_name", types.MatchTypeFaceit, 0.95, "FACEIT.COM"),
+		conVarPrefixRule("faceit_plugin", types.MatchTypeFaceit, 0.8, "faceit_"),
+		serverNameRule("esea_server_name", types.MatchTypeESEA, 0.9, "ESEA"),
+		conVarPrefixRule("esea_plugin", types.MatchTypeESEA, 0.8, "esea_"),
+		serverNameRule("esportal_server_name", types.MatchTypeESPortal, 0.9, "ESPORTAL"),
+		conVarPrefixRule("esportal_plugin", types.MatchTypeESPortal, 0.8, "esportal_"),
+		serverNameRule("esl_server_name", types.MatchTypeESL, 0.8, "ESL"),
+		conVarPrefixRule("tournament_plugin", types.MatchTypeTournament, 0.6, "get5_", "matchzy_"),
+		serverNameRule("cybershoke_server_name", types.MatchTypeCommunity, 0.9, "CYBERSHOKE"),
+		conVarPrefixRule("community_plugin", types.MatchTypeCommunity, 0.4, "sourcemod_", "metamod_", "css_"),
+		serverNameRule("valve_server_name", types.MatchTypeValve, 0.9, "VALVE"),
+		{
+			Name:       "valve_competitive_ranks",
+			MatchType:  types.MatchTypeValve,
+			Confidence: 0.6,
+			Match: func(signals MatchTypeSignals) (string, bool) {
+				if signals.RankedPlayers == 0 {
+					return "", false
+				}
+				return fmt.Sprintf("%d players with matchmaking ranks", signals.RankedPlayers), true
+			},
+		},
+		serverNameRule("hltv_server_name", types.MatchTypeHLTV, 0.6, "HLTV", "GOTV"),
+		{
+			Name:       "hltv_broadcast",
+			MatchType:  types.MatchTypeHLTV,
+			Confidence: 0.5,
+			Match: func(signals MatchTypeSignals) (string, bool) {
+				if signals.ConVars["tv_broadcast"] == "1" {
+					return "convar tv_broadcast is enabled", true
+				}
+				if url := signals.ConVars["tv_broadcast_url"]; url != "" {
+					return "convar tv_broadcast_url is set", true
+				}
+				return "", false
+			},
+		},
+	}
+}
+
+// serverNameRule matches any of the words in the server name, ignoring case.
+// Words must not be part of a longer word, so "ESL" does not match "DIESEL".
+func serverNameRule(name string, matchType string, confidence float64, words ...string) MatchTypeRule {
+	patterns := make([]*regexp.Regexp, 0, len(words))
+	for _, word := range words {
+		patterns = append(patterns, regexp.MustCompile(`(?i)(^|[^A-Z0-9])`+regexp.QuoteMeta(word)+`($|[^A-Z0-9])`))
+	}
+
+	return MatchTypeRule{
+		Name:       name,
+		MatchType:  matchType,
+		Confidence: confidence,
+		Match: func(signals MatchTypeSignals) (string, bool) {
+			for i, pattern := range patterns {
+				if pattern.MatchString(signals.ServerName) {
+					return fmt.Sprintf("server name contains %q", words[i]), true
+				}
+			}
+			return "", false
+		},
+	}
+}
+
+// conVarPrefixRule matches when the server reports a convar from a known plugin
+func conVarPrefixRule(name string, matchType string, confidence float64, prefixes ...string) MatchTypeRule {
+	return MatchTypeRule{
+		Name:       name,
+		MatchType:  matchType,
+		Confidence: confidence,
+		Match: func(signals MatchTypeSignals) (string, bool) {
+			conVarNames := make([]string, 0, len(signals.ConVars))
+			for conVar := range signals.ConVars {
+				conVarNames = append(conVarNames, conVar)
+			}
+			sort.Strings(conVarNames)
+
+			for _, conVar := range conVarNames {
+				for _, prefix := range prefixes {
+					if strings.HasPrefix(strings.ToLower(conVar), prefix) {
+						return fmt.Sprintf("plugin convar %s", conVar), true
+					}
+				}
+			}
+			return "", false
+		},
+	}
+}
+
+func matchTypeRuleFromConfig(ruleConfig config.MatchTypeRuleConfig) MatchTypeRule {
+	serverNames := serverNameRule(ruleConfig.Name, ruleConfig.MatchType, ruleConfig.Confidence, ruleConfig.ServerNamePatterns...)
+
+	prefixes := make([]string, 0, len(ruleConfig.ConVarPrefixes))
+	for _, prefix := range ruleConfig.ConVarPrefixes {
+		prefixes = append(prefixes, strings.ToLower(prefix))
+	}
+	conVars := conVarPrefixRule(ruleConfig.Name, ruleConfig.MatchType, ruleConfig.Confidence, prefixes...)
+
+	return MatchTypeRule{
+		Name:       ruleConfig.Name,
+		MatchType:  ruleConfig.MatchType,
+		Confidence: ruleConfig.Confidence,
+		Match: func(signals MatchTypeSignals) (string, bool) {
+			if evidence, matched := serverNames.Match(signals); matched {
+				return evidence, true
+			}
+			return conVars.Match(signals)
+		},
+	}
+}
+
+func clampConfidence(confidence float64) float64 {
+	if confidence < 0 {
+		return 0
+	}
+	if confidence > 1 {
+		return 1
+	}
+	return confidence
+}
+
+// collectMatchTypeSignals gathers the signals of a parsed demo
+func collectMatchTypeSignals(serverName string, parser demoinfocs.Parser) MatchTypeSignals {
+	signals := MatchTypeSignals{
+		ServerName:     serverName,
+		ConVars:        map[string]string{},
+		RankTypeCounts: map[int]int{},
+	}
+
+	if parser == nil {
+		return signals
+	}
+
+	gameState := parser.GameState()
+	if gameState == nil {
+		return signals
+	}
+
+	if rules := gameState.Rules(); rules != nil && rules.ConVars() != nil {
+		signals.ConVars = rules.ConVars()
+	}
+
+	for _, player := range gameState.Participants().All() {
+		if player == nil {
+			continue
+		}
+		rankType := player.RankType()
+		signals.RankTypeCounts[rankType]++
+
+		// A valid rank is one that's not 0 (unranked) and has a valid rank type
+		if player.Rank() > 0 && rankType > 0 {
+			signals.RankedPlayers++
+		}
+	}
+
+	return signals
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/config"
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchTypeDetector_Detect(t *testing.T) {
+	detector := NewMatchTypeDetector(logrus.New(), nil)
+
+	tests := []struct {
+		name     string
+		signals  MatchTypeSignals
+		expected string
+	}{
+		{
+			name:     "ESEA server name",
+			signals:  MatchTypeSignals{ServerName: "ESEA Premier #123"},
+			expected: types.MatchTypeESEA,
+		},
+		{
+			name:     "Esportal server name",
+			signals:  MatchTypeSignals{ServerName: "Esportal.com | Match 42"},
+			expected: types.MatchTypeESPortal,
+		},
+		{
+			name:     "CyberShoke community server",
+			signals:  MatchTypeSignals{ServerName: "CYBERSHOKE.NET | 5v5 MIX #7"},
+			expected: types.MatchTypeCommunity,
+		},
+		{
+			name:     "ESL needs a whole word",
+			signals:  MatchTypeSignals{ServerName: "Diesel Gaming"},
+			expected: types.MatchTypeUnknown,
+		},
+		{
+			name:     "Tournament plugin",
+			signals:  MatchTypeSignals{ConVars: map[string]string{"matchzy_kniferound_enabled": "1"}},
+			expected: types.MatchTypeTournament,
+		},
+		{
+			name:     "GOTV broadcast",
+			signals:  MatchTypeSignals{ConVars: map[string]string{"tv_broadcast": "1"}},
+			expected: types.MatchTypeHLTV,
+		},
+		{
+			name:     "Matchmaking ranks",
+			signals:  MatchTypeSignals{RankedPlayers: 10},
+			expected: types.MatchTypeValve,
+		},
+		{
+			name:     "Platform name beats a broadcast",
+			signals:  MatchTypeSignals{ServerName: "FACEIT.com register to play here", ConVars: map[string]string{"tv_broadcast": "1"}},
+			expected: types.MatchTypeFaceit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, detector.Detect(tt.signals).MatchType)
+		})
+	}
+}
+
+func TestMatchTypeDetector_CombinesEvidence(t *testing.T) {
+	detector := NewMatchTypeDetector(logrus.New(), nil)
+
+	detection := detector.Detect(MatchTypeSignals{
+		ServerName: "ESEA Match Server",
+		ConVars:    map[string]string{"esea_version": "2.1"},
+	})
+
+	assert.Equal(t, types.MatchTypeESEA, detection.MatchType)
+	assert.InDelta(t, 0.98, detection.Confidence, 0.001)
+	assert.Equal(t, []string{`server name contains "ESEA"`, "plugin convar esea_version"}, detection.Evidence)
+
+	unknown := detector.Detect(MatchTypeSignals{})
+	assert.Equal(t, types.MatchTypeUnknown, unknown.MatchType)
+	assert.Zero(t, unknown.Confidence)
+	assert.Empty(t, unknown.Evidence)
+}
+
+func TestMatchTypeDetector_ConfiguredRules(t *testing.T) {
+	detector := NewMatchTypeDetector(logrus.New(), []config.MatchTypeRuleConfig{
+		{
+			Name:               "league",
+			MatchType:          "league",
+			Confidence:         0.7,
+			ServerNamePatterns: []string{"Open League"},
+			ConVarPrefixes:     []string{"OL_"},
+		},
+	})
+
+	assert.Equal(t, "league", detector.Detect(MatchTypeSignals{ServerName: "open league | div 3"}).MatchType)
+	assert.Equal(t, "league", detector.Detect(MatchTypeSignals{ConVars: map[string]string{"ol_match_id": "55"}}).MatchType)
+}
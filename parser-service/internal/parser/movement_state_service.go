@@ -18,8 +18,8 @@ const (
 
 // Position tracking constants
 const (
-	MOVEMENT_LOOKFORWARD_TICKS = 16  // Look 16 ticks after throw for movement detection
-	MOVEMENT_THRESHOLD         = 1.0 // Minimum movement component to detect directional input
+	MOVEMENT_LOOKFORWARD_SECONDS = 0.25 // Look a quarter second after throw for movement detection
+	MOVEMENT_THRESHOLD           = 1.0  // Minimum movement component to detect directional input
 )
 
 // PlayerPositionRecord stores a single position data point with all movement-related data
@@ -41,6 +41,7 @@ type MovementStateService struct {
 	positionRecords []PlayerPositionRecord
 	currentRound    int
 	positionIndex   map[string]*PlayerPositionRecord
+	timing          *types.TickTiming
 }
 
 // NewMovementStateService creates a new movement state service
@@ -53,6 +54,11 @@ func NewMovementStateService(logger *logrus.Logger) *MovementStateService {
 	}
 }
 
+// SetTickTiming sets the timing context used to convert look-ahead windows to ticks
+func (mss *MovementStateService) SetTickTiming(timing *types.TickTiming) {
+	mss.timing = timing
+}
+
 // UpdatePlayerPosition records player position for movement analysis
 func (mss *MovementStateService) UpdatePlayerPosition(player *common.Player, currentTick int64) {
 	if player == nil {
@@ -141,6 +147,18 @@ func (mss *MovementStateService) findPosition(steamID uint64, round int, tick in
 	return nil
 }
 
+// PositionAt returns where a player was at a tick, falling back to the latest record
+// at most maxAge ticks earlier since frames do not land on every tick
+func (mss *MovementStateService) PositionAt(steamID uint64, round int, tick int64, maxAge int64) *types.Position {
+	for t := tick; t >= tick-maxAge; t-- {
+		if record := mss.findPosition(steamID, round, t); record != nil {
+			position := record.Position
+			return &position
+		}
+	}
+	return nil
+}
+
 // findPositionWithFallback finds a position with fallback to earlier ticks
 func (mss *MovementStateService) findPositionWithFallback(steamID uint64, round int, preferredTick int64, throwTick int64) *PlayerPositionRecord {
 
@@ -183,13 +201,14 @@ func (mss *MovementStateService) CalculateGrenadeMovementSimple(steamID uint64,
 		return "Standing"
 	}
 
-	futurePos := mss.findPositionWithFallback(steamID, round, throwTick+MOVEMENT_LOOKFORWARD_TICKS, throwTick)
+	lookforwardTicks := mss.timing.SecondsToTicks(MOVEMENT_LOOKFORWARD_SECONDS)
+	futurePos := mss.findPositionWithFallback(steamID, round, throwTick+lookforwardTicks, throwTick)
 	if futurePos == nil {
 		mss.logger.WithFields(logrus.Fields{
 			"steam_id":    steamID,
 			"round":       round,
 			"throw_tick":  throwTick,
-			"target_tick": throwTick + MOVEMENT_LOOKFORWARD_TICKS,
+			"target_tick": throwTick + lookforwardTicks,
 		}).Debug("Could not find future position")
 		return "Standing"
 	}
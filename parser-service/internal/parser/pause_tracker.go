@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"parser-service/internal/types"
+
+	st "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/sendtables"
+	"github.com/sirupsen/logrus"
+)
+
+// PauseState is the pause related part of the game rules at one tick
+type PauseState struct {
+	CTTimeout bool
+	TTimeout  bool
+	// Technical covers team called technical timeouts and admin pauses (mp_pause_match)
+	Technical bool
+}
+
+// pauseType returns the kind of pause in effect and, for tactical timeouts, the side
+// that called it. An empty type means the match is running.
+func (s PauseState) pauseType() (string, string) {
+	switch {
+	case s.CTTimeout:
+		return types.PauseTypeTacticalTimeout, "CT"
+	case s.TTimeout:
+		return types.PauseTypeTacticalTimeout, "T"
+	case s.Technical:
+		return types.PauseTypeTechnical, ""
+	default:
+		return "", ""
+	}
+}
+
+// readPauseState reads the pause flags from the CCSGameRulesProxy entity
+func readPauseState(entity st.Entity) (PauseState, bool) {
+	if entity == nil {
+		return PauseState{}, false
+	}
+
+	return PauseState{
+		CTTimeout: gameRulesBool(entity, "m_bCTTimeOutActive"),
+		TTimeout:  gameRulesBool(entity, "m_bTerroristTimeOutActive"),
+		Technical: gameRulesBool(entity, "m_bTechnicalTimeOut") || gameRulesBool(entity, "m_bMatchWaitingForResume"),
+	}, true
+}
+
+func gameRulesBool(entity st.Entity, name string) bool {
+	value, exists := entity.PropertyValue(gameRulesPropertyPrefix + name)
+	if !exists {
+		return false
+	}
+
+	flag, ok := value.Any.(bool)
+	return ok && flag
+}
+
+const gameRulesPropertyPrefix = "m_pGameRules."
+
+// PauseTracker turns the game rules pause flags into PauseEvents. A pause that
+// changes kind, e.g. a tactical timeout followed by an admin pause, is split in two.
+type PauseTracker struct {
+	active *types.PauseEvent
+}
+
+// NewPauseTracker creates a tracker with no pause in progress
+func NewPauseTracker() *PauseTracker {
+	return &PauseTracker{}
+}
+
+// Update applies the pause state of a tick and returns the pause that ended on it, if any
+func (pt *PauseTracker) Update(tick int64, round int, state PauseState) *types.PauseEvent {
+	pauseType, side := state.pauseType()
+
+	if pt.active != nil && pt.active.PauseType == pauseType && stringValue(pt.active.Side) == side {
+		return nil
+	}
+
+	finished := pt.Finish(tick)
+
+	if pauseType != "" {
+		pt.active = &types.PauseEvent{
+			RoundNumber: round,
+			PauseType:   pauseType,
+			StartTick:   tick,
+		}
+		if side != "" {
+			pt.active.Side = &side
+		}
+	}
+
+	return finished
+}
+
+// Finish ends the pause in progress, e.g. when the demo stops while paused
+func (pt *PauseTracker) Finish(tick int64) *types.PauseEvent {
+	if pt.active == nil {
+		return nil
+	}
+
+	finished := pt.active
+	finished.EndTick = tick
+	pt.active = nil
+	return finished
+}
+
+// Renumber moves the pause in progress to the new number of its round, or drops it
+// along with its round
+func (pt *PauseTracker) Renumber(mapRound func(int) int) {
+	if pt.active == nil {
+		return
+	}
+	if pt.active.RoundNumber = mapRound(pt.active.RoundNumber); pt.active.RoundNumber == 0 {
+		pt.active = nil
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// UpdatePauseState records pauses from the game rules. It is called once per frame.
+func (ep *EventProcessor) UpdatePauseState(state PauseState) {
+	if ep.pauseTracker == nil || ep.matchState == nil {
+		return
+	}
+
+	round := ep.matchState.CurrentRound
+	if ep.shouldSkipCurrentRound() {
+		// Pauses outside the match, e.g. during warmup, are not reported
+		state = PauseState{}
+	}
+
+	ep.recordPause(ep.pauseTracker.Update(ep.currentTick, round, state))
+}
+
+// FinishPauses closes a pause that was still running when the demo ended
+func (ep *EventProcessor) FinishPauses() {
+	if ep.pauseTracker == nil {
+		return
+	}
+	ep.recordPause(ep.pauseTracker.Finish(ep.currentTick))
+}
+
+func (ep *EventProcessor) recordPause(pause *types.PauseEvent) {
+	if pause == nil {
+		return
+	}
+
+	pause.Duration = ep.Timing().TicksToSeconds(pause.EndTick - pause.StartTick)
+	if pause.Side != nil {
+		if team := ep.teamOnSide(*pause.Side); team != "" {
+			pause.Team = &team
+		}
+	}
+
+	ep.matchState.PauseEvents = append(ep.matchState.PauseEvents, *pause)
+
+	ep.logger.WithFields(logrus.Fields{
+		"round":      pause.RoundNumber,
+		"pause_type": pause.PauseType,
+		"duration":   pause.Duration,
+	}).Info("Pause ended")
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseTracker_Update(t *testing.T) {
+	tracker := NewPauseTracker()
+
+	assert.Nil(t, tracker.Update(100, 3, PauseState{}))
+	assert.Nil(t, tracker.Update(200, 3, PauseState{TTimeout: true}))
+	assert.Nil(t, tracker.Update(300, 3, PauseState{TTimeout: true}))
+
+	// An admin pause straight after the timeout is reported separately
+	timeout := tracker.Update(2120, 3, PauseState{Technical: true})
+	require.NotNil(t, timeout)
+	assert.Equal(t, types.PauseTypeTacticalTimeout, timeout.PauseType)
+	require.NotNil(t, timeout.Side)
+	assert.Equal(t, "T", *timeout.Side)
+	assert.Equal(t, int64(200), timeout.StartTick)
+	assert.Equal(t, int64(2120), timeout.EndTick)
+
+	technical := tracker.Update(5000, 3, PauseState{})
+	require.NotNil(t, technical)
+	assert.Equal(t, types.PauseTypeTechnical, technical.PauseType)
+	assert.Nil(t, technical.Side)
+
+	assert.Nil(t, tracker.Finish(6000))
+}
+
+func TestPauseTracker_Renumber(t *testing.T) {
+	tracker := NewPauseTracker()
+	tracker.Update(100, 3, PauseState{Technical: true})
+
+	tracker.Renumber(func(round int) int { return round - 1 })
+	pause := tracker.Finish(200)
+	require.NotNil(t, pause)
+	assert.Equal(t, 2, pause.RoundNumber)
+
+	// A pause in a dropped round is dropped with it
+	tracker.Update(300, 3, PauseState{Technical: true})
+	tracker.Renumber(func(int) int { return 0 })
+	assert.Nil(t, tracker.Finish(400))
+}
+
+func TestEventProcessor_RecordsPauses(t *testing.T) {
+	matchState := &types.MatchState{Players: make(map[string]*types.Player)}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+	processor.SetTickRate(64)
+	processor.teamACurrentSide = "T"
+	processor.teamBCurrentSide = "CT"
+	matchState.CurrentRound = 7
+
+	processor.currentTick = 1000
+	processor.UpdatePauseState(PauseState{CTTimeout: true})
+	processor.currentTick = 1000 + 30*64
+	processor.UpdatePauseState(PauseState{})
+
+	// A pause still running when the demo ends is closed at the last tick
+	processor.currentTick = 10000
+	processor.UpdatePauseState(PauseState{Technical: true})
+	processor.currentTick = 10000 + 64
+	processor.FinishPauses()
+
+	require.Len(t, matchState.PauseEvents, 2)
+	timeout := matchState.PauseEvents[0]
+	assert.Equal(t, 7, timeout.RoundNumber)
+	assert.InDelta(t, 30.0, timeout.Duration, 0.001)
+	require.NotNil(t, timeout.Team)
+	assert.Equal(t, "B", *timeout.Team)
+	assert.Equal(t, types.PauseTypeTechnical, matchState.PauseEvents[1].PauseType)
+	assert.InDelta(t, 1.0, matchState.PauseEvents[1].Duration, 0.001)
+}
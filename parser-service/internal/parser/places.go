@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"strings"
+
+	"parser-service/internal/config"
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+// mapZone is a named area of a map, see config.MapZoneConfig
+type mapZone struct {
+	name    string
+	polygon []types.Position
+	minZ    *float64
+	maxZ    *float64
+}
+
+// PlaceResolver names positions on a map from the zones configured for it
+type PlaceResolver struct {
+	mapName string
+	zones   []mapZone
+}
+
+// NewPlaceResolver creates a place resolver for a map. Zones with fewer than three
+// points are ignored.
+func NewPlaceResolver(mapName string, cfg *config.Config) *PlaceResolver {
+	resolver := &PlaceResolver{mapName: mapName}
+	if cfg == nil {
+		return resolver
+	}
+
+	for _, zone := range cfg.Parser.MapZones[strings.ToLower(mapName)] {
+		polygon := make([]types.Position, 0, len(zone.Polygon))
+		for _, point := range zone.Polygon {
+			if len(point) < 2 {
+				continue
+			}
+			polygon = append(polygon, types.Position{X: point[0], Y: point[1]})
+		}
+		if zone.Name == "" || len(polygon) < 3 {
+			continue
+		}
+
+		resolver.zones = append(resolver.zones, mapZone{
+			name:    zone.Name,
+			polygon: polygon,
+			minZ:    zone.MinZ,
+			maxZ:    zone.MaxZ,
+		})
+	}
+
+	return resolver
+}
+
+// PlaceAt returns the name of the first zone containing the position, or an empty
+// string when no zone does
+func (pr *PlaceResolver) PlaceAt(position types.Position) string {
+	for _, zone := range pr.zones {
+		if zone.minZ != nil && position.Z < *zone.minZ {
+			continue
+		}
+		if zone.maxZ != nil && position.Z > *zone.maxZ {
+			continue
+		}
+		if polygonContains(zone.polygon, position) {
+			return zone.name
+		}
+	}
+	return ""
+}
+
+// polygonContains reports whether a point lies inside a polygon, looking from above
+func polygonContains(polygon []types.Position, point types.Position) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > point.Y) != (b.Y > point.Y) &&
+			point.X < (b.X-a.X)*(point.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// SetMapName sets the map of the demo being parsed
+func (ep *EventProcessor) SetMapName(mapName string) {
+	ep.mapName = mapName
+}
+
+// getPlayerPlace returns the place name of a player's position. The demo's own
+// place name is preferred, with the configured map zones as a fallback.
+func (ep *EventProcessor) getPlayerPlace(player *common.Player) string {
+	if player == nil {
+		return ""
+	}
+	if place := player.LastPlaceName(); place != "" {
+		return place
+	}
+	return ep.getPlace(ep.getPlayerPosition(player))
+}
+
+// getPlace returns the configured map zone containing a position
+func (ep *EventProcessor) getPlace(position types.Position) string {
+	if ep.places == nil || ep.places.mapName != ep.mapName {
+		ep.places = NewPlaceResolver(ep.mapName, ep.config)
+	}
+	return ep.places.PlaceAt(position)
+}
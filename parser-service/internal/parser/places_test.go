@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/config"
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func placesTestConfig() *config.Config {
+	upper := 100.0
+	return &config.Config{
+		Parser: config.ParserConfig{
+			MapZones: map[string][]config.MapZoneConfig{
+				"de_test": {
+					{Name: "Lower", Polygon: [][]float64{{0, 0}, {100, 0}, {100, 100}, {0, 100}}, MaxZ: &upper},
+					{Name: "Upper", Polygon: [][]float64{{0, 0}, {100, 0}, {100, 100}, {0, 100}}},
+					{Name: "Triangle", Polygon: [][]float64{{200, 0}, {300, 0}, {250, 100}}},
+					{Name: "Broken", Polygon: [][]float64{{500, 0}, {600}}},
+				},
+			},
+		},
+	}
+}
+
+func TestPlaceResolver_PlaceAt(t *testing.T) {
+	resolver := NewPlaceResolver("DE_TEST", placesTestConfig())
+
+	tests := []struct {
+		name     string
+		position types.Position
+		expected string
+	}{
+		{"inside square", types.Position{X: 50, Y: 50}, "Lower"},
+		{"above lower level", types.Position{X: 50, Y: 50, Z: 150}, "Upper"},
+		{"inside triangle", types.Position{X: 250, Y: 40}, "Triangle"},
+		{"outside triangle", types.Position{X: 210, Y: 90}, ""},
+		{"outside every zone", types.Position{X: -10, Y: 50}, ""},
+		{"zone with too few points", types.Position{X: 550, Y: 0}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolver.PlaceAt(tt.position))
+		})
+	}
+}
+
+func TestPlaceResolver_UnknownMap(t *testing.T) {
+	assert.Equal(t, "", NewPlaceResolver("de_other", placesTestConfig()).PlaceAt(types.Position{X: 50, Y: 50}))
+	assert.Equal(t, "", NewPlaceResolver("de_test", nil).PlaceAt(types.Position{X: 50, Y: 50}))
+}
+
+func TestEventProcessor_GetPlayerPlace_FallsBackToZones(t *testing.T) {
+	cfg := &config.Config{
+		Parser: config.ParserConfig{
+			MapZones: map[string][]config.MapZoneConfig{
+				"de_test": {{Name: "Mid", Polygon: [][]float64{{-50, -50}, {50, -50}, {50, 50}, {-50, 50}}}},
+			},
+		},
+	}
+	matchState := &types.MatchState{Players: make(map[string]*types.Player)}
+	processor := NewEventProcessor(matchState, logrus.New(), cfg, nil)
+
+	// Without demo entities the player has no place name and stands at the origin
+	player := &common.Player{SteamID64: 76561198000000001}
+
+	assert.Equal(t, "", processor.getPlayerPlace(player), "no zones before the map is known")
+
+	processor.SetMapName("de_test")
+	assert.Equal(t, "Mid", processor.getPlayerPlace(player))
+	assert.Equal(t, "", processor.getPlace(types.Position{X: 80, Y: 0}))
+	assert.Equal(t, "", processor.getPlayerPlace(nil))
+}
@@ -0,0 +1,404 @@
+package parser
+
+import (
+	"sort"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/sirupsen/logrus"
+)
+
+// playerSession is one continuous stretch a player spent on a team
+type playerSession struct {
+	team      string
+	startTick int64
+	endTick   int64
+	active    bool
+}
+
+// vacancy is a team slot left open by a player who disconnected during the match
+type vacancy struct {
+	steamID   string
+	team      string
+	startTick int64
+	endTick   int64
+}
+
+// RosterTracker follows who is on each team. A player who reconnects keeps their
+// identity; a new player who fills an open slot is recorded as a substitution.
+type RosterTracker struct {
+	sessions      map[string]*playerSession // Latest session of every player seen
+	vacancies     []vacancy
+	substitutions []*types.Substitution
+}
+
+// NewRosterTracker creates a tracker with nobody on a team
+func NewRosterTracker() *RosterTracker {
+	return &RosterTracker{
+		sessions: make(map[string]*playerSession),
+	}
+}
+
+// Joined records a human taking a slot on team. round is the first round the player
+// will play, or 0 before the match has started. It returns the substitution the join
+// completes, if any.
+func (rt *RosterTracker) Joined(steamID string, team string, tick int64, round int) *types.Substitution {
+	session, seen := rt.sessions[steamID]
+	if seen && session.active {
+		return nil
+	}
+
+	rt.sessions[steamID] = &playerSession{team: team, startTick: tick, active: true}
+
+	if seen {
+		// A reconnecting player takes back their own slot
+		rt.removeVacancy(steamID)
+		return nil
+	}
+
+	if round == 0 {
+		return nil
+	}
+
+	for i, open := range rt.vacancies {
+		if open.team != team {
+			continue
+		}
+
+		rt.vacancies = append(rt.vacancies[:i], rt.vacancies[i+1:]...)
+		substitution := &types.Substitution{
+			Team:               team,
+			PlayerOutSteamID:   open.steamID,
+			PlayerInSteamID:    steamID,
+			RoundNumber:        round,
+			PlayerOutStartTick: open.startTick,
+			PlayerOutEndTick:   open.endTick,
+			PlayerInStartTick:  tick,
+		}
+		rt.substitutions = append(rt.substitutions, substitution)
+		return substitution
+	}
+
+	return nil
+}
+
+// Left records a player leaving their team. Slots left during the match stay open
+// until the player returns or a substitute takes them.
+func (rt *RosterTracker) Left(steamID string, tick int64, matchStarted bool) {
+	session, seen := rt.sessions[steamID]
+	if !seen || !session.active {
+		return
+	}
+
+	session.active = false
+	session.endTick = tick
+
+	if matchStarted {
+		rt.vacancies = append(rt.vacancies, vacancy{
+			steamID:   steamID,
+			team:      session.team,
+			startTick: session.startTick,
+			endTick:   tick,
+		})
+	}
+}
+
+// Finish closes the sessions still open at tick and returns the substitutions
+func (rt *RosterTracker) Finish(tick int64) []types.Substitution {
+	substitutions := make([]types.Substitution, 0, len(rt.substitutions))
+	for _, substitution := range rt.substitutions {
+		substitution.PlayerInEndTick = tick
+		if session, seen := rt.sessions[substitution.PlayerInSteamID]; seen && !session.active {
+			substitution.PlayerInEndTick = session.endTick
+		}
+		substitutions = append(substitutions, *substitution)
+	}
+	return substitutions
+}
+
+// Renumber moves substitutions to the new number of their first round, dropping those
+// whose round no longer counts
+func (rt *RosterTracker) Renumber(mapRound func(int) int) {
+	substitutions := rt.substitutions[:0]
+	for _, substitution := range rt.substitutions {
+		if substitution.RoundNumber = mapRound(substitution.RoundNumber); substitution.RoundNumber > 0 {
+			substitutions = append(substitutions, substitution)
+		}
+	}
+	rt.substitutions = substitutions
+}
+
+func (rt *RosterTracker) removeVacancy(steamID string) {
+	for i, open := range rt.vacancies {
+		if open.steamID == steamID {
+			rt.vacancies = append(rt.vacancies[:i], rt.vacancies[i+1:]...)
+			return
+		}
+	}
+}
+
+// HandleBotTakenOver remembers which human controls a bot for the rest of the round
+func (ep *EventProcessor) HandleBotTakenOver(e events.BotTakenOver) error {
+	if e.Taker == nil {
+		return types.NewParseErrorWithSeverity(types.ErrorTypeValidation, types.ErrorSeverityWarning, "bot taker is nil", nil).
+			WithContext("event", "BotTakenOver")
+	}
+
+	bot := e.Taker.ControlledBot()
+	if bot == nil {
+		return nil
+	}
+
+	ep.botControllers[bot] = e.Taker
+
+	ep.logger.WithFields(logrus.Fields{
+		"round":    ep.currentRound,
+		"steam_id": types.SteamIDToString(e.Taker.SteamID64),
+		"bot":      bot.Name,
+	}).Debug("Player took over bot")
+
+	return nil
+}
+
+// actingPlayer returns the human controlling a bot, so that what the bot does is
+// credited to them, or the player itself
+func (ep *EventProcessor) actingPlayer(player *common.Player) *common.Player {
+	if player == nil || !player.IsBot {
+		return player
+	}
+
+	if controller, controlled := ep.botControllers[player]; controlled && controller != nil {
+		return controller
+	}
+	return player
+}
+
+// rosterJoined records a human joining a team
+func (ep *EventProcessor) rosterJoined(player *common.Player) {
+	if ep.roster == nil || ep.matchState == nil || player == nil || player.IsBot {
+		return
+	}
+
+	if side := ep.getTeamString(player.Team); side != "CT" && side != "T" {
+		return
+	}
+
+	steamID := types.SteamIDToString(player.SteamID64)
+	team := ep.getAssignedTeam(steamID)
+
+	if substitution := ep.roster.Joined(steamID, team, ep.currentTick, ep.substitutionRound()); substitution != nil {
+		ep.logger.WithFields(logrus.Fields{
+			"team":       team,
+			"round":      substitution.RoundNumber,
+			"player_out": substitution.PlayerOutSteamID,
+			"player_in":  substitution.PlayerInSteamID,
+		}).Info("Player substituted")
+	}
+
+	ep.markRoundParticipant(player)
+}
+
+// rosterLeft records a human leaving their team, by disconnecting or spectating
+func (ep *EventProcessor) rosterLeft(player *common.Player) {
+	if ep.roster == nil || ep.matchState == nil || player == nil || player.IsBot {
+		return
+	}
+
+	ep.roster.Left(types.SteamIDToString(player.SteamID64), ep.currentTick, ep.matchState.CurrentRound > 0)
+}
+
+// substitutionRound returns the first round a player joining now will play, or 0
+// before the match has started
+func (ep *EventProcessor) substitutionRound() int {
+	round := ep.matchState.CurrentRound
+	if round == 0 {
+		return 0
+	}
+
+	if ep.roundValidity != nil && !ep.roundValidity.LiveRoundInProgress() {
+		return round + 1
+	}
+	return round
+}
+
+// FinishRoster stores the substitutions of the match once the demo has ended
+func (ep *EventProcessor) FinishRoster() {
+	if ep.roster == nil || ep.matchState == nil {
+		return
+	}
+
+	// A substitute who joined after the last round never played for the team
+	substitutions := make([]types.Substitution, 0)
+	for _, substitution := range ep.roster.Finish(ep.currentTick) {
+		if substitution.RoundNumber <= ep.matchState.CurrentRound {
+			substitutions = append(substitutions, substitution)
+		}
+	}
+	ep.matchState.Substitutions = substitutions
+}
+
+// startRoundParticipants resets the players taking part in the round
+func (ep *EventProcessor) startRoundParticipants() {
+	ep.roundParticipants = make(map[string]bool)
+	ep.botControllers = make(map[*common.Player]*common.Player)
+	ep.markPlayingParticipants()
+}
+
+// markRoundParticipant records that a player on a team took part in the round in progress
+func (ep *EventProcessor) markRoundParticipant(player *common.Player) {
+	if player == nil || ep.roundParticipants == nil {
+		return
+	}
+
+	if side := ep.getTeamString(player.Team); side != "CT" && side != "T" {
+		return
+	}
+	ep.roundParticipants[types.SteamIDToString(player.SteamID64)] = true
+}
+
+// markPlayingParticipants adds everyone currently on a team to the round's participants
+func (ep *EventProcessor) markPlayingParticipants() {
+	if ep.demoParser == nil {
+		return
+	}
+
+	gameState := ep.demoParser.GameState()
+	if gameState == nil {
+		return
+	}
+
+	for _, player := range gameState.Participants().Playing() {
+		ep.markRoundParticipant(player)
+	}
+}
+
+// roundPlayers returns the known players who took part in the round in progress,
+// or nil when nobody was seen on a team
+func (ep *EventProcessor) roundPlayers() []string {
+	ep.markPlayingParticipants()
+
+	players := make([]string, 0, len(ep.roundParticipants))
+	for steamID := range ep.roundParticipants {
+		if _, exists := ep.matchState.Players[steamID]; exists {
+			players = append(players, steamID)
+		}
+	}
+	sort.Strings(players)
+
+	if len(players) == 0 {
+		return nil
+	}
+	return players
+}
+
+// removeBots drops bots from the per player results. Events involving bots, e.g. a
+// human killing a bot, are kept. Achievements must be calculated afterwards.
+func removeBots(data *types.ParsedDemoData) {
+	bots := make(map[string]bool)
+	players := data.Players[:0]
+	for _, player := range data.Players {
+		if player.IsBot {
+			bots[player.SteamID] = true
+			continue
+		}
+		players = append(players, player)
+	}
+	data.Players = players
+
+	if len(bots) == 0 {
+		return
+	}
+
+	playerRoundEvents := data.PlayerRoundEvents[:0]
+	for _, event := range data.PlayerRoundEvents {
+		if !bots[event.PlayerSteamID] {
+			playerRoundEvents = append(playerRoundEvents, event)
+		}
+	}
+	data.PlayerRoundEvents = playerRoundEvents
+
+	loadoutEvents := data.LoadoutEvents[:0]
+	for _, event := range data.LoadoutEvents {
+		if !bots[event.PlayerSteamID] {
+			loadoutEvents = append(loadoutEvents, event)
+		}
+	}
+	data.LoadoutEvents = loadoutEvents
+
+	tradeEvents := data.TradeEvents[:0]
+	for _, event := range data.TradeEvents {
+		if bots[event.TradedPlayerSteamID] {
+			continue
+		}
+		opportunities := event.OpportunitySteamIDs[:0]
+		for _, steamID := range event.OpportunitySteamIDs {
+			if !bots[steamID] {
+				opportunities = append(opportunities, steamID)
+			}
+		}
+		event.OpportunitySteamIDs = opportunities
+		// A refrag by a bot is no trade by a player
+		if event.TraderSteamID != nil && bots[*event.TraderSteamID] {
+			event.Traded = false
+			event.TraderSteamID = nil
+			event.TradeTick = nil
+			event.TimeGapSeconds = nil
+		}
+		if len(opportunities) > 0 {
+			tradeEvents = append(tradeEvents, event)
+		}
+	}
+	data.TradeEvents = tradeEvents
+
+	for i := range data.RoundReplays {
+		tracks := data.RoundReplays[i].Players[:0]
+		for _, track := range data.RoundReplays[i].Players {
+			if !bots[track.PlayerSteamID] {
+				tracks = append(tracks, track)
+			}
+		}
+		data.RoundReplays[i].Players = tracks
+	}
+
+	playerMatchEvents := data.PlayerMatchEvents[:0]
+	for _, event := range data.PlayerMatchEvents {
+		if !bots[event.PlayerSteamID] {
+			playerMatchEvents = append(playerMatchEvents, event)
+		}
+	}
+	data.PlayerMatchEvents = playerMatchEvents
+
+	aimEvents := data.AimEvents[:0]
+	for _, event := range data.AimEvents {
+		if !bots[event.PlayerSteamID] {
+			aimEvents = append(aimEvents, event)
+		}
+	}
+	data.AimEvents = aimEvents
+
+	aimWeaponEvents := data.AimWeaponEvents[:0]
+	for _, event := range data.AimWeaponEvents {
+		if !bots[event.PlayerSteamID] {
+			aimWeaponEvents = append(aimWeaponEvents, event)
+		}
+	}
+	data.AimWeaponEvents = aimWeaponEvents
+
+	roundAimEvents := data.RoundAimEvents[:0]
+	for _, event := range data.RoundAimEvents {
+		if !bots[event.PlayerSteamID] {
+			roundAimEvents = append(roundAimEvents, event)
+		}
+	}
+	data.RoundAimEvents = roundAimEvents
+
+	roundAimWeaponEvents := data.RoundAimWeaponEvents[:0]
+	for _, event := range data.RoundAimWeaponEvents {
+		if !bots[event.PlayerSteamID] {
+			roundAimWeaponEvents = append(roundAimWeaponEvents, event)
+		}
+	}
+	data.RoundAimWeaponEvents = roundAimWeaponEvents
+}
@@ -0,0 +1,227 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRosterTracker_Reconnect(t *testing.T) {
+	roster := NewRosterTracker()
+
+	assert.Nil(t, roster.Joined("1", "A", 100, 0))
+	roster.Left("1", 5000, true)
+
+	// Coming back keeps the slot, even after someone else joined the other team
+	assert.Nil(t, roster.Joined("9", "B", 5500, 4))
+	assert.Nil(t, roster.Joined("1", "A", 6000, 4))
+	assert.Nil(t, roster.Joined("2", "A", 7000, 5))
+
+	assert.Empty(t, roster.Finish(9000))
+}
+
+func TestRosterTracker_Substitution(t *testing.T) {
+	roster := NewRosterTracker()
+
+	roster.Joined("1", "A", 100, 0)
+	roster.Joined("2", "B", 100, 0)
+
+	// Leaving before the match starts opens no slot
+	roster.Left("2", 200, false)
+	assert.Nil(t, roster.Joined("3", "B", 300, 0))
+
+	roster.Left("1", 5000, true)
+	assert.Nil(t, roster.Joined("4", "B", 5200, 6), "a slot on the other team is not taken")
+
+	substitution := roster.Joined("5", "A", 5500, 6)
+	require.NotNil(t, substitution)
+	roster.Left("5", 8000, true)
+
+	substitutions := roster.Finish(9000)
+	require.Len(t, substitutions, 1)
+	assert.Equal(t, types.Substitution{
+		Team:               "A",
+		PlayerOutSteamID:   "1",
+		PlayerInSteamID:    "5",
+		RoundNumber:        6,
+		PlayerOutStartTick: 100,
+		PlayerOutEndTick:   5000,
+		PlayerInStartTick:  5500,
+		PlayerInEndTick:    8000,
+	}, substitutions[0])
+}
+
+func TestEventProcessor_ActingPlayer(t *testing.T) {
+	processor := NewEventProcessor(&types.MatchState{Players: make(map[string]*types.Player)}, logrus.New(), nil, nil)
+
+	human := &common.Player{SteamID64: 76561198000000001, Name: "human"}
+	bot := &common.Player{IsBot: true, Name: "BOT Albert"}
+	otherBot := &common.Player{IsBot: true, Name: "BOT Brian"}
+
+	processor.botControllers[bot] = human
+
+	assert.Same(t, human, processor.actingPlayer(bot))
+	assert.Same(t, otherBot, processor.actingPlayer(otherBot))
+	assert.Same(t, human, processor.actingPlayer(human))
+	assert.Nil(t, processor.actingPlayer(nil))
+
+	// Control ends with the round
+	processor.startRoundParticipants()
+	assert.Same(t, bot, processor.actingPlayer(bot))
+}
+
+func TestEventProcessor_BotTakeoverVictim(t *testing.T) {
+	matchState := &types.MatchState{CurrentRound: 1, Players: make(map[string]*types.Player)}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+
+	provider := weaponProvider{weapon: &common.Equipment{Type: common.EqAK47}}
+	human := common.NewPlayer(provider)
+	human.SteamID64 = 76561198000000001
+	bot := common.NewPlayer(provider)
+	bot.IsBot = true
+	enemy := common.NewPlayer(provider)
+	enemy.SteamID64 = 76561198000000002
+
+	processor.botControllers[bot] = human
+
+	require.NoError(t, processor.HandlePlayerHurt(events.PlayerHurt{
+		Attacker:     enemy,
+		Player:       bot,
+		Weapon:       &common.Equipment{Type: common.EqAK47},
+		HealthDamage: 27,
+	}))
+	require.Len(t, matchState.DamageEvents, 1)
+	assert.Equal(t, "76561198000000001", matchState.DamageEvents[0].VictimSteamID)
+
+	require.NoError(t, processor.HandlePlayerKilled(events.Kill{
+		Killer: enemy,
+		Victim: bot,
+		Weapon: &common.Equipment{Type: common.EqAK47},
+	}))
+	require.Len(t, matchState.GunfightEvents, 1)
+	assert.Equal(t, "76561198000000001", matchState.GunfightEvents[0].Player2SteamID)
+}
+
+func TestRosterTracker_Renumber(t *testing.T) {
+	roster := NewRosterTracker()
+	roster.Joined("1", "A", 100, 0)
+	roster.Joined("2", "A", 100, 0)
+	roster.Left("1", 1000, true)
+	roster.Joined("3", "A", 1100, 3)
+	roster.Left("2", 2000, true)
+	roster.Joined("4", "A", 2100, 5)
+
+	// Round 5 was superseded and the rounds before it moved down by one
+	roster.Renumber(func(round int) int {
+		if round >= 5 {
+			return 0
+		}
+		return round - 1
+	})
+
+	substitutions := roster.Finish(3000)
+	require.Len(t, substitutions, 1)
+	assert.Equal(t, "3", substitutions[0].PlayerInSteamID)
+	assert.Equal(t, 2, substitutions[0].RoundNumber)
+}
+
+func TestEventProcessor_RoundsPlayed(t *testing.T) {
+	matchState := &types.MatchState{
+		Players:     make(map[string]*types.Player),
+		RoundEvents: make([]types.RoundEvent, 0),
+	}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+
+	starter := &common.Player{SteamID64: 1, Name: "starter", Team: common.TeamCounterTerrorists}
+	opponent := &common.Player{SteamID64: 2, Name: "opponent", Team: common.TeamTerrorists}
+	substitute := &common.Player{SteamID64: 3, Name: "substitute", Team: common.TeamCounterTerrorists}
+
+	processor.currentTick = 100
+	require.NoError(t, processor.HandlePlayerConnect(events.PlayerConnect{Player: starter}))
+	require.NoError(t, processor.HandlePlayerConnect(events.PlayerConnect{Player: opponent}))
+	require.NoError(t, processor.HandleRoundStart(events.RoundStart{}))
+	processor.markRoundParticipant(starter)
+	processor.markRoundParticipant(opponent)
+	assert.Equal(t, []string{"1", "2"}, processor.roundHandler.getPlayersInRound(1))
+
+	processor.currentTick = 500
+	processor.roundValidity.RoundEnded(processor.currentTick, events.RoundEndReasonCTWin)
+	require.NoError(t, processor.HandlePlayerDisconnected(events.PlayerDisconnected{Player: starter}))
+	require.NoError(t, processor.HandlePlayerConnect(events.PlayerConnect{Player: substitute}))
+
+	processor.currentTick = 600
+	require.NoError(t, processor.HandleRoundStart(events.RoundStart{}))
+	processor.markRoundParticipant(opponent)
+	processor.markRoundParticipant(substitute)
+	assert.Equal(t, []string{"2", "3"}, processor.roundHandler.getPlayersInRound(2))
+
+	processor.FinishRoster()
+	require.Len(t, matchState.Substitutions, 1)
+	assert.Equal(t, "1", matchState.Substitutions[0].PlayerOutSteamID)
+	assert.Equal(t, "3", matchState.Substitutions[0].PlayerInSteamID)
+	assert.Equal(t, 2, matchState.Substitutions[0].RoundNumber)
+
+	matchState.PlayerRoundEvents = []types.PlayerRoundEvent{
+		{PlayerSteamID: "1", RoundNumber: 1, Damage: 100},
+		{PlayerSteamID: "2", RoundNumber: 1, Damage: 50},
+		{PlayerSteamID: "2", RoundNumber: 2, Damage: 150},
+		{PlayerSteamID: "3", RoundNumber: 2, Damage: 80},
+	}
+	starterMatch := processor.playerMatchHandler.createPlayerMatchEvent("1")
+	assert.Equal(t, 1, starterMatch.RoundsPlayed)
+	assert.Equal(t, 100.0, starterMatch.ADR)
+	assert.Equal(t, 2, processor.playerMatchHandler.createPlayerMatchEvent("2").RoundsPlayed)
+}
+
+func TestRemoveBots(t *testing.T) {
+	botTrader, botTradeTick := "0", int64(1200)
+	data := &types.ParsedDemoData{
+		Players: []types.Player{
+			{SteamID: "76561198000000001", Name: "human"},
+			{SteamID: "0", Name: "BOT Albert", IsBot: true},
+		},
+		GunfightEvents: []types.GunfightEvent{
+			{Player1SteamID: "76561198000000001", Player2SteamID: "0"},
+		},
+		PlayerRoundEvents: []types.PlayerRoundEvent{
+			{PlayerSteamID: "76561198000000001"},
+			{PlayerSteamID: "0"},
+		},
+		PlayerMatchEvents: []types.PlayerMatchEvent{
+			{PlayerSteamID: "0"},
+			{PlayerSteamID: "76561198000000001"},
+		},
+		AimEvents: []types.AimAnalysisResult{{PlayerSteamID: "0"}},
+		TradeEvents: []types.TradeEvent{
+			{TradedPlayerSteamID: "76561198000000001", KillerSteamID: "0", OpportunitySteamIDs: []string{"76561198000000002", "0"}},
+			{TradedPlayerSteamID: "76561198000000001", KillerSteamID: "0", OpportunitySteamIDs: []string{"0"}},
+			{TradedPlayerSteamID: "0", KillerSteamID: "76561198000000001", OpportunitySteamIDs: []string{"76561198000000002"}},
+			{TradedPlayerSteamID: "76561198000000001", KillerSteamID: "76561198000000003", Traded: true, TraderSteamID: &botTrader, TradeTick: &botTradeTick, OpportunitySteamIDs: []string{"0"}},
+			{TradedPlayerSteamID: "76561198000000001", KillerSteamID: "76561198000000003", Traded: true, TraderSteamID: &botTrader, TradeTick: &botTradeTick, OpportunitySteamIDs: []string{"76561198000000002", "0"}},
+		},
+	}
+
+	removeBots(data)
+
+	require.Len(t, data.Players, 1)
+	assert.Equal(t, "human", data.Players[0].Name)
+	assert.Len(t, data.GunfightEvents, 1)
+	require.Len(t, data.PlayerRoundEvents, 1)
+	assert.Equal(t, "76561198000000001", data.PlayerRoundEvents[0].PlayerSteamID)
+	require.Len(t, data.PlayerMatchEvents, 1)
+	assert.Equal(t, "76561198000000001", data.PlayerMatchEvents[0].PlayerSteamID)
+	assert.Empty(t, data.AimEvents)
+	// Only the bot's refrag is dropped from a death a player could also have traded
+	require.Len(t, data.TradeEvents, 2)
+	assert.Equal(t, []string{"76561198000000002"}, data.TradeEvents[0].OpportunitySteamIDs)
+	assert.Equal(t, []string{"76561198000000002"}, data.TradeEvents[1].OpportunitySteamIDs)
+	assert.False(t, data.TradeEvents[1].Traded)
+	assert.Nil(t, data.TradeEvents[1].TraderSteamID)
+	assert.Nil(t, data.TradeEvents[1].TradeTick)
+}
@@ -173,12 +173,16 @@ func (mh *PlayerMatchHandler) createPlayerMatchEvent(playerSteamID string) types
 		totalGrenadeValueLostOnDeath += roundEvent.GrenadeValueLostOnDeath
 	}
 
+	playerMatchEvent.RoundsPlayed = numberOfRoundsParticipated
+
 	//Average metrics
-	playerMatchEvent.AverageRoundTimeOfDeath = float64(totalRoundTimeOfDeath) / float64(numberOfRoundsParticipated)
 	playerMatchEvent.AverageGrenadeEffectiveness = int(math.Round((float64(totalGrenadeEffectiveness) / float64(nonZeroGrenadeEffectivenessRounds))))
-	playerMatchEvent.AverageTimeToContact = float64(totalTimeToContact) / float64(numberOfRoundsParticipated)
-	playerMatchEvent.AverageGrenadeValueLost = float64(totalGrenadeValueLostOnDeath) / float64(numberOfRoundsParticipated)
-	playerMatchEvent.ADR = float64(playerMatchEvent.Damage) / float64(numberOfRoundsParticipated)
+	if numberOfRoundsParticipated > 0 {
+		playerMatchEvent.AverageRoundTimeOfDeath = float64(totalRoundTimeOfDeath) / float64(numberOfRoundsParticipated)
+		playerMatchEvent.AverageTimeToContact = float64(totalTimeToContact) / float64(numberOfRoundsParticipated)
+		playerMatchEvent.AverageGrenadeValueLost = float64(totalGrenadeValueLostOnDeath) / float64(numberOfRoundsParticipated)
+		playerMatchEvent.ADR = float64(playerMatchEvent.Damage) / float64(numberOfRoundsParticipated)
+	}
 
 	// Calculate impact values by aggregating from gunfight events
 	playerMatchEvent.TotalImpact = 0
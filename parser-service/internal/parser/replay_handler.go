@@ -0,0 +1,308 @@
+package parser
+
+import (
+	"context"
+	"sort"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultReplayInterval is the number of ticks between two replay frames when the
+// interval is not configured
+const defaultReplayInterval = 16
+
+// ReplayHandler builds a 2D replay of every round. Positions and aim come from the
+// stored tick data; health and the active weapon, which the tick data does not
+// hold, are sampled on the same ticks while the round is played.
+type ReplayHandler struct {
+	processor *EventProcessor
+	logger    *logrus.Logger
+
+	states map[int64]map[string]replayState // Sampled state by tick and player
+}
+
+// replayState is the part of a replay frame that is not in the stored tick data
+type replayState struct {
+	Health int
+	Weapon string
+}
+
+// NewReplayHandler creates a new replay handler
+func NewReplayHandler(processor *EventProcessor, logger *logrus.Logger) *ReplayHandler {
+	return &ReplayHandler{
+		processor: processor,
+		logger:    logger,
+		states:    make(map[int64]map[string]replayState),
+	}
+}
+
+// enabled reports whether round replays are configured
+func (rh *ReplayHandler) enabled() bool {
+	config := rh.processor.config
+	return config != nil && config.Parser.RoundReplays
+}
+
+// interval returns the number of ticks between two frames of a track
+func (rh *ReplayHandler) interval() int64 {
+	config := rh.processor.config
+	if config == nil || config.Parser.ReplayInterval < 1 {
+		return defaultReplayInterval
+	}
+	return int64(config.Parser.ReplayInterval)
+}
+
+// ResetRound forgets the state sampled during the previous round
+func (rh *ReplayHandler) ResetRound() {
+	rh.states = make(map[int64]map[string]replayState)
+}
+
+// RecordSample stores a player's health and active weapon at a sampled tick
+func (rh *ReplayHandler) RecordSample(tick int64, player *common.Player) {
+	if player == nil || !rh.enabled() {
+		return
+	}
+
+	state := replayState{Health: player.Health()}
+	if weapon := player.ActiveWeapon(); weapon != nil {
+		state.Weapon = weapon.Type.String()
+	}
+
+	tickStates, exists := rh.states[tick]
+	if !exists {
+		tickStates = make(map[string]replayState)
+		rh.states[tick] = tickStates
+	}
+	tickStates[types.SteamIDToString(player.SteamID64)] = state
+}
+
+// ProcessRoundEnd builds the replay of the round that just ended from the round
+// tick cache and the events recorded during the round
+func (rh *ReplayHandler) ProcessRoundEnd() {
+	defer rh.ResetRound()
+
+	ep := rh.processor
+	if !rh.enabled() || ep.roundTickCache == nil {
+		return
+	}
+
+	round := ep.matchState.CurrentRound
+	startTick := ep.matchState.RoundStartTick
+	endTick := ep.matchState.RoundEndTick
+	if endTick == 0 {
+		endTick = ep.currentTick
+	}
+
+	// Aim tracking usually loaded the round already
+	if !ep.roundTickCache.IsRoundLoaded(round) {
+		if err := ep.roundTickCache.LoadRound(context.Background(), round, startTick, endTick); err != nil {
+			rh.logger.WithError(err).WithField("round", round).Warn("Failed to load round tick data for replay")
+			return
+		}
+	}
+
+	replay := types.RoundReplay{
+		RoundNumber:    round,
+		StartTick:      startTick,
+		EndTick:        endTick,
+		TickRate:       ep.timing.TickRate,
+		SampleInterval: rh.interval(),
+		Players:        buildReplayTracks(ep.roundTickCache.GetAllTickDataForRound(), rh.states, rh.interval()),
+		Grenades:       replayGrenades(ep.matchState.GrenadeEvents, round),
+		Events:         replayEvents(ep.matchState, round),
+	}
+
+	for i := range replay.Players {
+		replay.Players[i].Side = ep.getPlayerCurrentSide(replay.Players[i].PlayerSteamID)
+	}
+
+	ep.matchState.RoundReplays = append(ep.matchState.RoundReplays, replay)
+
+	rh.logger.WithFields(logrus.Fields{
+		"round":    round,
+		"players":  len(replay.Players),
+		"grenades": len(replay.Grenades),
+		"events":   len(replay.Events),
+	}).Debug("Round replay built")
+}
+
+// buildReplayTracks groups tick data by player and keeps a frame every interval
+// ticks. The last frame of every player is always kept so tracks end where the
+// player died or the round ended.
+func buildReplayTracks(samples []*types.PlayerTickData, states map[int64]map[string]replayState, interval int64) []types.ReplayTrack {
+	byPlayer := make(map[string][]*types.PlayerTickData)
+	for _, sample := range samples {
+		if sample == nil {
+			continue
+		}
+		byPlayer[sample.PlayerID] = append(byPlayer[sample.PlayerID], sample)
+	}
+
+	tracks := make([]types.ReplayTrack, 0, len(byPlayer))
+	for playerID, playerSamples := range byPlayer {
+		sort.Slice(playerSamples, func(i, j int) bool {
+			return playerSamples[i].Tick < playerSamples[j].Tick
+		})
+
+		track := types.ReplayTrack{
+			PlayerSteamID: playerID,
+			Team:          playerSamples[0].Team,
+			Frames:        make([]types.ReplayFrame, 0, len(playerSamples)),
+		}
+
+		lastKept := int64(0)
+		for i, sample := range playerSamples {
+			last := i == len(playerSamples)-1
+			if i > 0 && !last && sample.Tick-lastKept < interval {
+				continue
+			}
+			lastKept = sample.Tick
+
+			frame := types.ReplayFrame{
+				Tick: sample.Tick,
+				Position: types.Position{
+					X: sample.PositionX,
+					Y: sample.PositionY,
+					Z: sample.PositionZ,
+				},
+				Yaw:   sample.AimX,
+				Pitch: sample.AimY,
+			}
+			if state, exists := states[sample.Tick][playerID]; exists {
+				frame.Health = state.Health
+				frame.Weapon = state.Weapon
+			}
+			track.Frames = append(track.Frames, frame)
+		}
+
+		tracks = append(tracks, track)
+	}
+
+	sort.Slice(tracks, func(i, j int) bool {
+		if tracks[i].Team != tracks[j].Team {
+			return tracks[i].Team < tracks[j].Team
+		}
+		return tracks[i].PlayerSteamID < tracks[j].PlayerSteamID
+	})
+
+	return tracks
+}
+
+// replayGrenades returns the flights of the grenades thrown in a round
+func replayGrenades(grenadeEvents []types.GrenadeEvent, round int) []types.ReplayGrenade {
+	grenades := make([]types.ReplayGrenade, 0)
+	for _, event := range grenadeEvents {
+		if event.RoundNumber != round {
+			continue
+		}
+
+		grenade := types.ReplayGrenade{
+			PlayerSteamID:  event.PlayerSteamID,
+			PlayerSide:     event.PlayerSide,
+			GrenadeType:    event.GrenadeType,
+			ThrowTick:      event.TickTimestamp,
+			DetonationTick: event.ExplosionTick,
+			Path:           []types.Position{event.PlayerPosition},
+		}
+		if event.GrenadeFinalPosition != nil {
+			grenade.Path = append(grenade.Path, *event.GrenadeFinalPosition)
+		}
+		// The sampled flight replaces the straight line when there is one
+		if len(event.Trajectory) > 0 {
+			grenade.Path = make([]types.Position, 0, len(event.Trajectory))
+			for _, point := range event.Trajectory {
+				grenade.Path = append(grenade.Path, point.Position)
+			}
+		}
+		grenades = append(grenades, grenade)
+	}
+
+	sort.SliceStable(grenades, func(i, j int) bool {
+		return grenades[i].ThrowTick < grenades[j].ThrowTick
+	})
+
+	return grenades
+}
+
+// replayEvents merges the kills, damage, grenade detonations and bomb events of a
+// round into one stream in tick order
+func replayEvents(matchState *types.MatchState, round int) []types.ReplayEvent {
+	events := make([]types.ReplayEvent, 0)
+
+	for _, gunfight := range matchState.GunfightEvents {
+		if gunfight.RoundNumber != round {
+			continue
+		}
+		victimPosition := gunfight.Player2Position
+		events = append(events, types.ReplayEvent{
+			Tick:          gunfight.TickTimestamp,
+			Type:          types.ReplayEventKill,
+			PlayerSteamID: gunfight.Player1SteamID,
+			TargetSteamID: gunfight.Player2SteamID,
+			Detail:        gunfight.Player1Weapon,
+			Headshot:      gunfight.Headshot,
+			Position:      &victimPosition,
+		})
+	}
+
+	for _, damage := range matchState.DamageEvents {
+		if damage.RoundNumber != round {
+			continue
+		}
+		events = append(events, types.ReplayEvent{
+			Tick:          damage.TickTimestamp,
+			Type:          types.ReplayEventDamage,
+			PlayerSteamID: damage.AttackerSteamID,
+			TargetSteamID: damage.VictimSteamID,
+			Detail:        damage.Weapon,
+			Damage:        damage.HealthDamage,
+			Headshot:      damage.Headshot,
+		})
+	}
+
+	for _, grenade := range matchState.GrenadeEvents {
+		if grenade.RoundNumber != round {
+			continue
+		}
+		tick := grenade.ExplosionTick
+		if tick == 0 {
+			tick = grenade.TickTimestamp
+		}
+		event := types.ReplayEvent{
+			Tick:          tick,
+			Type:          types.ReplayEventUtility,
+			PlayerSteamID: grenade.PlayerSteamID,
+			Detail:        grenade.GrenadeType,
+			Damage:        grenade.DamageDealt,
+		}
+		if grenade.GrenadeFinalPosition != nil {
+			position := *grenade.GrenadeFinalPosition
+			event.Position = &position
+		}
+		events = append(events, event)
+	}
+
+	for _, bomb := range matchState.BombEvents {
+		if bomb.RoundNumber != round {
+			continue
+		}
+		event := types.ReplayEvent{
+			Tick:     bomb.TickTimestamp,
+			Type:     types.ReplayEventBomb,
+			Detail:   bomb.EventType,
+			Position: &bomb.Position,
+		}
+		if bomb.PlayerSteamID != nil {
+			event.PlayerSteamID = *bomb.PlayerSteamID
+		}
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Tick < events[j].Tick
+	})
+
+	return events
+}
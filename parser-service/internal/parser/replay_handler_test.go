@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/config"
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	st "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/sendtables"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildReplayTracks_Downsamples(t *testing.T) {
+	var samples []*types.PlayerTickData
+	for tick := int64(100); tick <= 150; tick += 2 {
+		samples = append(samples, &types.PlayerTickData{Tick: tick, PlayerID: "2", Team: "B", PositionX: float64(tick)})
+	}
+	samples = append(samples,
+		&types.PlayerTickData{Tick: 100, PlayerID: "1", Team: "A", AimX: 90, AimY: -5},
+		&types.PlayerTickData{Tick: 104, PlayerID: "1", Team: "A"},
+		nil,
+	)
+	states := map[int64]map[string]replayState{
+		100: {"1": {Health: 100, Weapon: "AK-47"}},
+		116: {"2": {Health: 73, Weapon: "USP-S"}},
+	}
+
+	tracks := buildReplayTracks(samples, states, 16)
+
+	require.Len(t, tracks, 2)
+	assert.Equal(t, "1", tracks[0].PlayerSteamID)
+	assert.Equal(t, "A", tracks[0].Team)
+	require.Len(t, tracks[0].Frames, 2, "the last frame is kept even inside the interval")
+	assert.Equal(t, types.ReplayFrame{Tick: 100, Yaw: 90, Pitch: -5, Health: 100, Weapon: "AK-47"}, tracks[0].Frames[0])
+	assert.Equal(t, int64(104), tracks[0].Frames[1].Tick)
+
+	ticks := make([]int64, 0, len(tracks[1].Frames))
+	for _, frame := range tracks[1].Frames {
+		ticks = append(ticks, frame.Tick)
+	}
+	assert.Equal(t, []int64{100, 116, 132, 148, 150}, ticks)
+	assert.Equal(t, 73, tracks[1].Frames[1].Health)
+	assert.Equal(t, 116.0, tracks[1].Frames[1].Position.X)
+}
+
+func TestReplayEvents_MergesInTickOrder(t *testing.T) {
+	planter := "3"
+	final := types.Position{X: 10, Y: 20}
+	matchState := &types.MatchState{
+		GunfightEvents: []types.GunfightEvent{
+			{RoundNumber: 4, TickTimestamp: 300, Player1SteamID: "1", Player2SteamID: "2", Player1Weapon: "AK-47", Headshot: true},
+			{RoundNumber: 3, TickTimestamp: 50},
+		},
+		DamageEvents: []types.DamageEvent{
+			{RoundNumber: 4, TickTimestamp: 290, AttackerSteamID: "1", VictimSteamID: "2", Weapon: "AK-47", HealthDamage: 27},
+		},
+		GrenadeEvents: []types.GrenadeEvent{
+			{RoundNumber: 4, TickTimestamp: 100, ExplosionTick: 200, PlayerSteamID: "2", GrenadeType: "Smoke Grenade", GrenadeFinalPosition: &final},
+		},
+		BombEvents: []types.BombEvent{
+			{RoundNumber: 4, TickTimestamp: 400, EventType: types.BombEventPlanted, PlayerSteamID: &planter},
+		},
+	}
+
+	events := replayEvents(matchState, 4)
+
+	require.Len(t, events, 4)
+	assert.Equal(t, types.ReplayEventUtility, events[0].Type)
+	assert.Equal(t, int64(200), events[0].Tick)
+	assert.Equal(t, "Smoke Grenade", events[0].Detail)
+	require.NotNil(t, events[0].Position)
+	assert.NotSame(t, &final, events[0].Position)
+	assert.Equal(t, types.ReplayEventDamage, events[1].Type)
+	assert.Equal(t, 27, events[1].Damage)
+	assert.Equal(t, types.ReplayEventKill, events[2].Type)
+	assert.Equal(t, "2", events[2].TargetSteamID)
+	assert.True(t, events[2].Headshot)
+	assert.Equal(t, types.ReplayEventBomb, events[3].Type)
+	assert.Equal(t, "3", events[3].PlayerSteamID)
+
+	grenades := replayGrenades(matchState.GrenadeEvents, 4)
+	require.Len(t, grenades, 1)
+	assert.Equal(t, int64(100), grenades[0].ThrowTick)
+	assert.Equal(t, int64(200), grenades[0].DetonationTick)
+	assert.Len(t, grenades[0].Path, 2)
+}
+
+// weaponProvider answers the demo lookups a player makes for its active weapon
+type weaponProvider struct {
+	weapon *common.Equipment
+}
+
+func (p weaponProvider) IngameTick() int                              { return 0 }
+func (p weaponProvider) TickRate() float64                            { return types.DefaultTickRate }
+func (p weaponProvider) FindPlayerByHandle(uint64) *common.Player     { return nil }
+func (p weaponProvider) FindPlayerByPawnHandle(uint64) *common.Player { return nil }
+func (p weaponProvider) FindWeaponByEntityID(int) *common.Equipment   { return p.weapon }
+func (p weaponProvider) FindEntityByHandle(uint64) st.Entity          { return nil }
+
+func TestReplayHandler_RecordSampleOnlyWhenEnabled(t *testing.T) {
+	matchState := &types.MatchState{Players: make(map[string]*types.Player)}
+	player := common.NewPlayer(weaponProvider{weapon: &common.Equipment{Type: common.EqAK47}})
+	player.SteamID64 = 76561198000000001
+
+	disabled := NewEventProcessor(matchState, logrus.New(), nil, nil)
+	disabled.replayHandler.RecordSample(100, player)
+	assert.Empty(t, disabled.replayHandler.states)
+
+	cfg := &config.Config{Parser: config.ParserConfig{RoundReplays: true}}
+	enabled := NewEventProcessor(matchState, logrus.New(), cfg, nil)
+	enabled.replayHandler.RecordSample(100, player)
+	assert.Equal(t, replayState{Weapon: common.EqAK47.String()}, enabled.replayHandler.states[100]["76561198000000001"])
+	assert.Equal(t, int64(defaultReplayInterval), enabled.replayHandler.interval())
+
+	enabled.replayHandler.ResetRound()
+	assert.Empty(t, enabled.replayHandler.states)
+}
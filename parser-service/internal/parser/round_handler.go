@@ -81,9 +81,14 @@ func (rh *RoundHandler) ProcessRoundEnd() error {
 	return nil
 }
 
-// getPlayersInRound returns all players that are in the match, regardless of event participation
+// getPlayersInRound returns the players who were on a team during the round, so that
+// substitutes and players who left are only credited with the rounds they played
 func (rh *RoundHandler) getPlayersInRound(roundNumber int) []string {
-	// Get all players from match state - these are all players who connected to the match
+	if players := rh.processor.roundPlayers(); players != nil {
+		return players
+	}
+
+	// Without roster information fall back to all players who connected to the match
 	players := make([]string, 0, len(rh.processor.matchState.Players))
 	for steamID := range rh.processor.matchState.Players {
 		players = append(players, steamID)
@@ -332,199 +337,33 @@ func (rh *RoundHandler) aggregateGrenadeMetrics(event *types.PlayerRoundEvent, p
 	event.SmokeBlockingDuration = smokeBlockingDuration
 }
 
-// aggregateTradeMetrics aggregates trade-related statistics from gunfight events
+// aggregateTradeMetrics counts the player's trades and traded deaths from the round's
+// trade events
 func (rh *RoundHandler) aggregateTradeMetrics(event *types.PlayerRoundEvent, playerSteamID string, roundNumber int) {
-	successfulTrades := 0
-	totalPossibleTrades := 0
-	successfulTradedDeaths := 0
-	totalPossibleTradedDeaths := 0
-
-	// Create a list of all deaths in this round with timestamps and positions
-	type DeathInfo struct {
-		VictimSteamID  string
-		KillerSteamID  string
-		DeathTime      int64
-		DeathPosition  types.Position
-		DeathRoundTime int
-	}
-
-	var roundDeaths []DeathInfo
-
-	// Collect all deaths in this round
-	for _, gunfightEvent := range rh.processor.matchState.GunfightEvents {
-		if gunfightEvent.RoundNumber != roundNumber || gunfightEvent.VictorSteamID == nil {
-			continue
-		}
-
-		// Someone died in this gunfight
-		if gunfightEvent.Player1SteamID != *gunfightEvent.VictorSteamID {
-			// Player 1 died
-			roundDeaths = append(roundDeaths, DeathInfo{
-				VictimSteamID:  gunfightEvent.Player1SteamID,
-				KillerSteamID:  *gunfightEvent.VictorSteamID,
-				DeathTime:      gunfightEvent.TickTimestamp,
-				DeathPosition:  gunfightEvent.Player1Position,
-				DeathRoundTime: gunfightEvent.RoundTime,
-			})
-		} else if gunfightEvent.Player2SteamID != *gunfightEvent.VictorSteamID {
-			// Player 2 died
-			roundDeaths = append(roundDeaths, DeathInfo{
-				VictimSteamID:  gunfightEvent.Player2SteamID,
-				KillerSteamID:  *gunfightEvent.VictorSteamID,
-				DeathTime:      gunfightEvent.TickTimestamp,
-				DeathPosition:  gunfightEvent.Player2Position,
-				DeathRoundTime: gunfightEvent.RoundTime,
-			})
-		}
-	}
-
-	// Check for possible trades for this player
-	for _, death := range roundDeaths {
-		// Skip if this player died (we'll handle that separately)
-		if death.VictimSteamID == playerSteamID {
+	for _, tradeEvent := range rh.processor.matchState.TradeEvents {
+		if tradeEvent.RoundNumber != roundNumber {
 			continue
 		}
 
-		// Check if the dead player was a teammate
-		deadPlayerTeam := rh.processor.getAssignedTeam(death.VictimSteamID)
-		thisPlayerTeam := rh.processor.getAssignedTeam(playerSteamID)
-
-		if deadPlayerTeam != thisPlayerTeam {
-			continue // Not a teammate
-		}
-
-		// Get this player's position at the time of the teammate's death
-		thisPlayerPosition := rh.getPlayerPositionAtTime(playerSteamID, death.DeathTime)
-		if thisPlayerPosition == nil {
-			continue // Couldn't determine player position
-		}
-
-		// Check if this player was within trade distance
-		distance := types.CalculateDistance(death.DeathPosition, *thisPlayerPosition)
-		if distance <= types.TradeDistanceThreshold {
-			// This was a possible trade opportunity
-			totalPossibleTrades++
-
-			// Check if this player actually got the trade kill
-			tradeTimeWindow := death.DeathTime + int64(types.TradeTimeWindowSeconds*64) // Convert seconds to ticks (approximate)
-
-			for _, gunfightEvent := range rh.processor.matchState.GunfightEvents {
-				if gunfightEvent.RoundNumber != roundNumber || gunfightEvent.VictorSteamID == nil {
-					continue
-				}
-
-				// Check if this player got a kill on the original killer within the time window
-				if *gunfightEvent.VictorSteamID == playerSteamID &&
-					gunfightEvent.TickTimestamp >= death.DeathTime &&
-					gunfightEvent.TickTimestamp <= tradeTimeWindow {
-
-					// Check if the victim was the original killer
-					if (gunfightEvent.Player1SteamID == death.KillerSteamID && gunfightEvent.Player1SteamID != *gunfightEvent.VictorSteamID) ||
-						(gunfightEvent.Player2SteamID == death.KillerSteamID && gunfightEvent.Player2SteamID != *gunfightEvent.VictorSteamID) {
-						successfulTrades++
-						break // Found the trade, no need to check more
-					}
-				}
+		if tradeEvent.TradedPlayerSteamID == playerSteamID {
+			event.TotalPossibleTradedDeaths++
+			if tradeEvent.Traded {
+				event.SuccessfulTradedDeaths++
 			}
+			continue
 		}
-	}
-
-	// Check if this player's deaths were traded
-	for _, death := range roundDeaths {
-		if death.VictimSteamID != playerSteamID {
-			continue // Not this player's death
-		}
-
-		// Check if any teammates were in position to trade
-		tradeTimeWindow := death.DeathTime + int64(types.TradeTimeWindowSeconds*64) // Convert seconds to ticks
-
-		// Find teammates who could have traded
-		for _, otherPlayerID := range rh.getPlayersInRound(roundNumber) {
-			if otherPlayerID == playerSteamID {
-				continue // Skip self
-			}
-
-			// Check if they're teammates
-			otherPlayerTeam := rh.processor.getAssignedTeam(otherPlayerID)
-			thisPlayerTeam := rh.processor.getAssignedTeam(playerSteamID)
 
-			if otherPlayerTeam != thisPlayerTeam {
-				continue // Not a teammate
-			}
-
-			// Get teammate's position at time of this player's death
-			teammatePosition := rh.getPlayerPositionAtTime(otherPlayerID, death.DeathTime)
-			if teammatePosition == nil {
+		for _, steamID := range tradeEvent.OpportunitySteamIDs {
+			if steamID != playerSteamID {
 				continue
 			}
-
-			// Check if teammate was within trade distance
-			distance := types.CalculateDistance(death.DeathPosition, *teammatePosition)
-			if distance <= types.TradeDistanceThreshold {
-				// This was a possible traded death opportunity
-				totalPossibleTradedDeaths++
-
-				// Check if the teammate actually got the trade
-				for _, gunfightEvent := range rh.processor.matchState.GunfightEvents {
-					if gunfightEvent.RoundNumber != roundNumber || gunfightEvent.VictorSteamID == nil {
-						continue
-					}
-
-					// Check if the teammate got a kill on the original killer within the time window
-					if *gunfightEvent.VictorSteamID == otherPlayerID &&
-						gunfightEvent.TickTimestamp >= death.DeathTime &&
-						gunfightEvent.TickTimestamp <= tradeTimeWindow {
-
-						// Check if the victim was the original killer
-						if (gunfightEvent.Player1SteamID == death.KillerSteamID && gunfightEvent.Player1SteamID != *gunfightEvent.VictorSteamID) ||
-							(gunfightEvent.Player2SteamID == death.KillerSteamID && gunfightEvent.Player2SteamID != *gunfightEvent.VictorSteamID) {
-							successfulTradedDeaths++
-							break // Found the trade, no need to check more teammates
-						}
-					}
-				}
-				break // Only count one possible trade per death
-			}
-		}
-	}
-
-	// Set the aggregated values
-	event.SuccessfulTrades = successfulTrades
-	event.TotalPossibleTrades = totalPossibleTrades
-	event.SuccessfulTradedDeaths = successfulTradedDeaths
-	event.TotalPossibleTradedDeaths = totalPossibleTradedDeaths
-}
-
-// getPlayerPositionAtTime attempts to get a player's position at a specific time
-// This is a simplified implementation - in a real scenario, you might need more sophisticated position tracking
-func (rh *RoundHandler) getPlayerPositionAtTime(playerSteamID string, targetTime int64) *types.Position {
-	// Look for the closest gunfight event to get player position
-	var closestEvent *types.GunfightEvent
-	var closestTimeDiff int64 = int64(^uint64(0) >> 1) // Max int64
-
-	for _, gunfightEvent := range rh.processor.matchState.GunfightEvents {
-		if gunfightEvent.Player1SteamID == playerSteamID || gunfightEvent.Player2SteamID == playerSteamID {
-			timeDiff := targetTime - gunfightEvent.TickTimestamp
-			if timeDiff < 0 {
-				timeDiff = -timeDiff // Make it positive
-			}
-
-			if timeDiff < closestTimeDiff {
-				closestTimeDiff = timeDiff
-				closestEvent = &gunfightEvent
+			event.TotalPossibleTrades++
+			if tradeEvent.TraderSteamID != nil && *tradeEvent.TraderSteamID == playerSteamID {
+				event.SuccessfulTrades++
 			}
+			break
 		}
 	}
-
-	if closestEvent != nil {
-		if closestEvent.Player1SteamID == playerSteamID {
-			return &closestEvent.Player1Position
-		} else if closestEvent.Player2SteamID == playerSteamID {
-			return &closestEvent.Player2Position
-		}
-	}
-
-	return nil // Couldn't determine position
 }
 
 // aggregateClutchMetrics detects clutch scenarios and outcomes
@@ -11,10 +11,20 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// TickFlusher is implemented by tick writers that buffer data before it reaches the database
+type TickFlusher interface {
+	Flush(ctx context.Context) error
+}
+
 // RoundTickCache provides an in-memory cache for player tick data within a round
 // This dramatically reduces database queries by loading all tick data for a round once
+// fallbackRoundDurationSeconds bounds a round whose end tick is unknown
+const fallbackRoundDurationSeconds = 4 * 60
+
 type RoundTickCache struct {
 	playerTickService *database.PlayerTickService
+	tickWriter        TickFlusher
+	timing            *types.TickTiming
 	logger            *logrus.Logger
 	matchID           string
 	currentRound      int
@@ -42,6 +52,16 @@ func NewRoundTickCache(playerTickService *database.PlayerTickService, logger *lo
 	}
 }
 
+// SetTickWriter sets the writer whose pending data must be flushed before a round is loaded
+func (c *RoundTickCache) SetTickWriter(writer TickFlusher) {
+	c.tickWriter = writer
+}
+
+// SetTickTiming sets the timing context used to size the fallback round window
+func (c *RoundTickCache) SetTickTiming(timing *types.TickTiming) {
+	c.timing = timing
+}
+
 // LoadRound loads all player tick data for a specific round into memory
 // This replaces multiple database queries with a single bulk query
 func (c *RoundTickCache) LoadRound(ctx context.Context, roundNum int, startTick, endTick int64) error {
@@ -49,8 +69,8 @@ func (c *RoundTickCache) LoadRound(ctx context.Context, roundNum int, startTick,
 
 	// Handle edge case where endTick is 0 (use a fallback)
 	if endTick == 0 || endTick < startTick {
-		// Use startTick + estimated round duration (2 minutes max)
-		endTick = startTick + (2 * 60 * 128) // 128 tick/sec
+		// Use startTick + a generous round duration (round time, bomb timer and freeze time)
+		endTick = startTick + c.timing.SecondsToTicks(fallbackRoundDurationSeconds)
 		c.logger.WithFields(logrus.Fields{
 			"round":             roundNum,
 			"start_tick":        startTick,
@@ -58,6 +78,13 @@ func (c *RoundTickCache) LoadRound(ctx context.Context, roundNum int, startTick,
 		}).Warn("Round end tick is 0, using fallback")
 	}
 
+	// Wait until the round's ticks have been written by the background writer
+	if c.tickWriter != nil {
+		if err := c.tickWriter.Flush(ctx); err != nil {
+			return fmt.Errorf("failed to flush pending tick data: %w", err)
+		}
+	}
+
 	// Clear previous round data to free memory
 	c.tickData = make(map[int64]map[string]*types.PlayerTickData)
 	c.currentRound = roundNum
@@ -68,10 +95,17 @@ func (c *RoundTickCache) LoadRound(ctx context.Context, roundNum int, startTick,
 	c.cacheHits = 0
 	c.cacheMisses = 0
 
-	// Load all tick data for this round from database
-	data, err := c.playerTickService.GetPlayerTickDataByRound(ctx, c.matchID, startTick, endTick)
+	// Load all tick data for this round from database, preferring the columnar
+	// blobs and falling back to per-tick rows for matches stored in that format
+	data, err := c.playerTickService.GetPlayerTickDataFromBlobs(ctx, c.matchID, startTick, endTick)
 	if err != nil {
-		return fmt.Errorf("failed to load round tick data: %w", err)
+		return fmt.Errorf("failed to load round tick blobs: %w", err)
+	}
+	if len(data) == 0 {
+		data, err = c.playerTickService.GetPlayerTickDataByRound(ctx, c.matchID, startTick, endTick)
+		if err != nil {
+			return fmt.Errorf("failed to load round tick data: %w", err)
+		}
 	}
 
 	// Build in-memory index for O(1) lookups
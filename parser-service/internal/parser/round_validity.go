@@ -0,0 +1,537 @@
+package parser
+
+import (
+	"context"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/sirupsen/logrus"
+)
+
+// RoundStartDecision tells the event processor how to treat a new round
+type RoundStartDecision struct {
+	// DiscardPrevious is set when the previous live round never ended, e.g. it was
+	// interrupted by mp_restartgame or a "live on three" restart
+	DiscardPrevious bool
+	// Restart is set when the scores were reset after live rounds had been played
+	Restart bool
+	// SupersededRounds is the number of completed rounds, counted back from the
+	// latest, that a backup restore rolled back and that are replayed from now on
+	SupersededRounds int
+	// Validity of the new round
+	Validity string
+}
+
+// RoundEndDecision tells the event processor how to treat a round end
+type RoundEndDecision struct {
+	// Live is set when the round counts towards the match
+	Live bool
+	// DiscardCurrent is set when a round that was numbered turned out not to count
+	DiscardCurrent bool
+	// Restart is set when the round end restarted the match
+	Restart bool
+}
+
+// completedRound remembers how a live round began so later score rollbacks can be
+// matched against it
+type completedRound struct {
+	startTick  int64
+	startScore int
+	draw       bool
+}
+
+// RoundValidityTracker classifies rounds as live, warmup, knife or restarted from
+// the game rules state so that only competitive rounds are numbered and counted
+type RoundValidityTracker struct {
+	current         string
+	roundInProgress bool
+	roundStartTick  int64
+	roundStartScore int
+	completed       []completedRound
+	discarded       []types.DiscardedRound
+}
+
+// NewRoundValidityTracker creates a tracker that treats events before the first
+// round start as live, matching demos that start recording mid-round
+func NewRoundValidityTracker() *RoundValidityTracker {
+	return &RoundValidityTracker{}
+}
+
+// Current returns the validity of the round in progress, or "" before the first round
+func (t *RoundValidityTracker) Current() string {
+	return t.current
+}
+
+// IsLive reports whether events of the round in progress should be processed
+func (t *RoundValidityTracker) IsLive() bool {
+	return t.current == "" || t.current == types.RoundValidityLive
+}
+
+// LiveRoundInProgress reports whether a live round has started and not yet ended
+func (t *RoundValidityTracker) LiveRoundInProgress() bool {
+	return t.roundInProgress && t.current == types.RoundValidityLive
+}
+
+// RoundStarted classifies a new round. scoreTotal is the sum of both team scores,
+// or a negative value when the scores are unknown.
+func (t *RoundValidityTracker) RoundStarted(tick int64, warmup bool, scoreTotal int) RoundStartDecision {
+	decision := RoundStartDecision{}
+
+	if t.roundInProgress && t.current == types.RoundValidityLive {
+		decision.DiscardPrevious = true
+		t.discard(types.RoundValidityRestarted, t.roundStartTick, tick, 1)
+	}
+
+	t.roundInProgress = true
+	t.roundStartTick = tick
+	t.roundStartScore = scoreTotal
+
+	if warmup {
+		t.current = types.RoundValidityWarmup
+		t.discard(types.RoundValidityWarmup, tick, tick, 1)
+		decision.Validity = t.current
+		return decision
+	}
+
+	if scoreTotal == 0 && len(t.completed) > 0 {
+		decision.Restart = true
+		t.discard(types.RoundValidityRestarted, t.completed[0].startTick, tick, len(t.completed))
+		t.completed = nil
+	} else if superseded := t.rolledBackRounds(scoreTotal); superseded > 0 {
+		decision.SupersededRounds = superseded
+		first := len(t.completed) - superseded
+		t.discard(types.RoundValiditySuperseded, t.completed[first].startTick, tick, superseded)
+		t.completed = t.completed[:first]
+	}
+
+	t.current = types.RoundValidityLive
+	decision.Validity = t.current
+	return decision
+}
+
+// rolledBackRounds returns how many completed rounds a backup restore undid. The
+// score only stays the same after a draw, so a lower total means the rounds that
+// began at or above it are being replayed.
+func (t *RoundValidityTracker) rolledBackRounds(scoreTotal int) int {
+	if scoreTotal < 0 || len(t.completed) == 0 {
+		return 0
+	}
+
+	last := t.completed[len(t.completed)-1]
+	if last.startScore < 0 {
+		return 0
+	}
+
+	expected := last.startScore + 1
+	if last.draw {
+		expected = last.startScore
+	}
+	if scoreTotal >= expected {
+		return 0
+	}
+
+	superseded := 0
+	for i := len(t.completed) - 1; i >= 0; i-- {
+		if t.completed[i].startScore < scoreTotal {
+			break
+		}
+		superseded++
+	}
+	return superseded
+}
+
+// FreezetimeEnded checks the loadouts once buying is over. It returns true when the
+// live round in progress turned out to be a knife round.
+func (t *RoundValidityTracker) FreezetimeEnded(tick int64, knifeOnly bool) bool {
+	if t.current != types.RoundValidityLive || !knifeOnly {
+		return false
+	}
+
+	t.current = types.RoundValidityKnife
+	t.discard(types.RoundValidityKnife, t.roundStartTick, tick, 1)
+	return true
+}
+
+// RoundEnded classifies the end of the round in progress
+func (t *RoundValidityTracker) RoundEnded(tick int64, reason events.RoundEndReason) RoundEndDecision {
+	decision := RoundEndDecision{}
+	wasLive := t.IsLive()
+	t.roundInProgress = false
+
+	if !wasLive {
+		t.extendLastDiscard(tick)
+		return decision
+	}
+
+	// "Game commencing" ends the round that was in progress when the match (re)started
+	if reason == events.RoundEndReasonGameStart {
+		decision.DiscardCurrent = t.current == types.RoundValidityLive
+		decision.Restart = len(t.completed) > 0
+
+		dropped := len(t.completed)
+		startTick := t.roundStartTick
+		if dropped > 0 {
+			startTick = t.completed[0].startTick
+		}
+		if decision.DiscardCurrent {
+			dropped++
+		}
+		if dropped > 0 {
+			t.discard(types.RoundValidityRestarted, startTick, tick, dropped)
+		}
+		t.completed = nil
+		t.current = types.RoundValidityRestarted
+		return decision
+	}
+
+	t.completed = append(t.completed, completedRound{
+		startTick:  t.roundStartTick,
+		startScore: t.roundStartScore,
+		draw:       reason == events.RoundEndReasonDraw,
+	})
+	decision.Live = true
+	return decision
+}
+
+// MatchRestarted handles a match start announcement. Completed rounds are discarded;
+// a live round in progress is kept and becomes the first round. It returns the
+// number of completed rounds that were dropped.
+func (t *RoundValidityTracker) MatchRestarted(tick int64) int {
+	dropped := len(t.completed)
+	if dropped > 0 {
+		t.discard(types.RoundValidityRestarted, t.completed[0].startTick, tick, dropped)
+	}
+
+	t.completed = nil
+	return dropped
+}
+
+// RoundAbandoned drops the live round in progress when the demo ends before the
+// round does. It reports whether there was such a round.
+func (t *RoundValidityTracker) RoundAbandoned(tick int64) bool {
+	if !t.LiveRoundInProgress() {
+		return false
+	}
+
+	t.discard(types.RoundValidityIncomplete, t.roundStartTick, tick, 1)
+	t.roundInProgress = false
+	return true
+}
+
+// Discarded returns the rounds left out of the match so far
+func (t *RoundValidityTracker) Discarded() []types.DiscardedRound {
+	if t == nil {
+		return nil
+	}
+	return t.discarded
+}
+
+func (t *RoundValidityTracker) discard(reason string, startTick, endTick int64, rounds int) {
+	t.discarded = append(t.discarded, types.DiscardedRound{
+		Reason:    reason,
+		StartTick: startTick,
+		EndTick:   endTick,
+		Rounds:    rounds,
+	})
+}
+
+func (t *RoundValidityTracker) extendLastDiscard(tick int64) {
+	if len(t.discarded) == 0 {
+		return
+	}
+	t.discarded[len(t.discarded)-1].EndTick = tick
+}
+
+// isKnifeOnlyLoadout reports whether every alive player carries nothing but a knife
+// (and possibly the bomb), which is how knife rounds for side selection are played.
+// Each entry holds the weapons of one alive player.
+func isKnifeOnlyLoadout(loadouts [][]*common.Equipment) bool {
+	// One player alone is more likely a late joiner than a knife round
+	if len(loadouts) < 2 {
+		return false
+	}
+
+	for _, weapons := range loadouts {
+		for _, weapon := range weapons {
+			if weapon == nil {
+				continue
+			}
+			if weapon.Type != common.EqKnife && weapon.Type != common.EqBomb {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// isWarmupPeriod reports whether the game rules say the server is in warmup
+func (ep *EventProcessor) isWarmupPeriod() bool {
+	if ep.demoParser == nil {
+		return false
+	}
+
+	gameState := ep.demoParser.GameState()
+	if gameState == nil {
+		return false
+	}
+
+	return gameState.IsWarmupPeriod()
+}
+
+// totalTeamScore returns the sum of both team scores, or -1 when the team
+// entities are not available yet
+func (ep *EventProcessor) totalTeamScore() int {
+	if ep.demoParser == nil {
+		return -1
+	}
+
+	gameState := ep.demoParser.GameState()
+	if gameState == nil {
+		return -1
+	}
+
+	ctState := gameState.Team(common.TeamCounterTerrorists)
+	tState := gameState.Team(common.TeamTerrorists)
+	// Without the team entities Score() reports 0, which would look like a reset
+	if ctState == nil || tState == nil || ctState.Entity == nil || tState.Entity == nil {
+		return -1
+	}
+
+	return ctState.Score() + tState.Score()
+}
+
+// aliveLoadouts returns the weapons carried by every alive player on either side
+func (ep *EventProcessor) aliveLoadouts() [][]*common.Equipment {
+	if ep.demoParser == nil {
+		return nil
+	}
+
+	gameState := ep.demoParser.GameState()
+	if gameState == nil {
+		return nil
+	}
+
+	var loadouts [][]*common.Equipment
+	for _, team := range []common.Team{common.TeamCounterTerrorists, common.TeamTerrorists} {
+		teamState := gameState.Team(team)
+		if teamState == nil {
+			continue
+		}
+		for _, player := range teamState.Members() {
+			if player == nil || !player.IsAlive() {
+				continue
+			}
+			loadouts = append(loadouts, player.Weapons())
+		}
+	}
+
+	return loadouts
+}
+
+// discardCurrentRound removes everything recorded for the round in progress and
+// gives its number back, so the next live round reuses it
+func (ep *EventProcessor) discardCurrentRound() {
+	if ep.matchState == nil || ep.matchState.CurrentRound == 0 {
+		return
+	}
+
+	discarded := ep.matchState.CurrentRound
+	ep.renumberRounds(func(round int) int {
+		if round == discarded {
+			return 0
+		}
+		return round
+	})
+
+	ep.matchState.CurrentRound--
+	ep.currentRound = ep.matchState.CurrentRound
+}
+
+// TruncateIncompleteRound drops the round that was still being played when a demo
+// ended unexpectedly and returns the last complete round
+func (ep *EventProcessor) TruncateIncompleteRound() int {
+	if ep.matchState == nil {
+		return 0
+	}
+
+	if ep.roundValidity != nil && ep.roundValidity.RoundAbandoned(ep.currentTick) {
+		ep.discardCurrentRound()
+	}
+
+	// A pause that outlived the last complete round belongs to the dropped round
+	if ep.pauseTracker != nil {
+		ep.pauseTracker.Finish(ep.currentTick)
+	}
+	pauseEvents := ep.matchState.PauseEvents[:0]
+	for _, pause := range ep.matchState.PauseEvents {
+		if pause.RoundNumber <= ep.matchState.CurrentRound {
+			pauseEvents = append(pauseEvents, pause)
+		}
+	}
+	ep.matchState.PauseEvents = pauseEvents
+
+	return ep.matchState.CurrentRound
+}
+
+// restartMatch drops every round played so far. With keepCurrent the round in
+// progress survives as the first round of the restarted match.
+func (ep *EventProcessor) restartMatch(keepCurrent bool) {
+	if ep.matchState == nil {
+		return
+	}
+
+	current := ep.matchState.CurrentRound
+	ep.renumberRounds(func(round int) int {
+		if keepCurrent && round == current {
+			return 1
+		}
+		return 0
+	})
+
+	ep.matchState.CurrentRound = 0
+	if keepCurrent && current > 0 {
+		ep.matchState.CurrentRound = 1
+	}
+	ep.currentRound = ep.matchState.CurrentRound
+	ep.matchState.MatchStartTick = 0
+
+	ep.discardStoredShots(1)
+
+	ep.logger.WithFields(logrus.Fields{
+		"tick":          ep.currentTick,
+		"current_round": ep.matchState.CurrentRound,
+	}).Info("Match restarted")
+}
+
+// supersedeRounds drops the latest completed rounds after a backup restore, so the
+// replayed rounds take over their numbers
+func (ep *EventProcessor) supersedeRounds(rounds int) {
+	if ep.matchState == nil || rounds <= 0 {
+		return
+	}
+
+	firstSuperseded := ep.matchState.CurrentRound - rounds + 1
+	if firstSuperseded < 1 {
+		firstSuperseded = 1
+	}
+
+	ep.renumberRounds(func(round int) int {
+		if round >= firstSuperseded {
+			return 0
+		}
+		return round
+	})
+
+	ep.matchState.CurrentRound = firstSuperseded - 1
+	ep.currentRound = ep.matchState.CurrentRound
+
+	ep.discardStoredShots(firstSuperseded)
+}
+
+// discardStoredShots removes shots already persisted for rounds that no longer count.
+// Shots are written at every round end, so dropped rounds have reached the writer.
+func (ep *EventProcessor) discardStoredShots(fromRound int) {
+	if ep.tickWriter == nil {
+		return
+	}
+
+	if err := ep.tickWriter.DiscardShots(context.Background(), fromRound); err != nil {
+		ep.logger.WithError(err).Warn("Failed to discard shooting data of dropped rounds")
+	}
+}
+
+// renumberRounds rewrites the round number of everything recorded so far.
+// mapRound returns the new number of a round, or 0 to drop it.
+func (ep *EventProcessor) renumberRounds(mapRound func(int) int) {
+	ms := ep.matchState
+
+	ms.RoundEvents = renumberSlice(ms.RoundEvents, func(e *types.RoundEvent) *int { return &e.RoundNumber }, mapRound)
+	ms.GunfightEvents = renumberSlice(ms.GunfightEvents, func(e *types.GunfightEvent) *int { return &e.RoundNumber }, mapRound)
+	ms.GrenadeEvents = renumberSlice(ms.GrenadeEvents, func(e *types.GrenadeEvent) *int { return &e.RoundNumber }, mapRound)
+	ms.DamageEvents = renumberSlice(ms.DamageEvents, func(e *types.DamageEvent) *int { return &e.RoundNumber }, mapRound)
+	ms.BombEvents = renumberSlice(ms.BombEvents, func(e *types.BombEvent) *int { return &e.RoundNumber }, mapRound)
+	ms.TeamEconomyEvents = renumberSlice(ms.TeamEconomyEvents, func(e *types.TeamEconomyEvent) *int { return &e.RoundNumber }, mapRound)
+	ms.ItemEvents = renumberSlice(ms.ItemEvents, func(e *types.ItemEvent) *int { return &e.RoundNumber }, mapRound)
+	ms.LoadoutEvents = renumberSlice(ms.LoadoutEvents, func(e *types.LoadoutEvent) *int { return &e.RoundNumber }, mapRound)
+	ms.TradeEvents = renumberSlice(ms.TradeEvents, func(e *types.TradeEvent) *int { return &e.RoundNumber }, mapRound)
+	ms.RoundReplays = renumberSlice(ms.RoundReplays, func(e *types.RoundReplay) *int { return &e.RoundNumber }, mapRound)
+	ms.PlayerRoundEvents = renumberSlice(ms.PlayerRoundEvents, func(e *types.PlayerRoundEvent) *int { return &e.RoundNumber }, mapRound)
+	ep.aimEvents = renumberSlice(ep.aimEvents, func(e *types.AimAnalysisResult) *int { return &e.RoundNumber }, mapRound)
+	ep.aimWeaponEvents = renumberSlice(ep.aimWeaponEvents, func(e *types.WeaponAimAnalysisResult) *int { return &e.RoundNumber }, mapRound)
+
+	// The time of a pause was spent even if its round no longer counts, so the pause
+	// moves to the round played next instead of being dropped
+	ms.PauseEvents = renumberSlice(ms.PauseEvents, func(e *types.PauseEvent) *int { return &e.RoundNumber }, followingRound(mapRound))
+	if ep.pauseTracker != nil {
+		ep.pauseTracker.Renumber(followingRound(mapRound))
+	}
+	if ep.roster != nil {
+		ep.roster.Renumber(mapRound)
+	}
+
+	if ep.aimTrackingHandler != nil {
+		ep.aimTrackingHandler.RenumberShootingData(mapRound)
+	}
+
+	// Team wins follow the rounds that are left
+	roundWinners := make(map[int]string, len(ep.roundWinners))
+	ep.teamAWins, ep.teamBWins = 0, 0
+	for round, team := range ep.roundWinners {
+		if round = mapRound(round); round == 0 {
+			continue
+		}
+		roundWinners[round] = team
+		if team == "A" {
+			ep.teamAWins++
+		} else {
+			ep.teamBWins++
+		}
+	}
+	ep.roundWinners = roundWinners
+
+	ep.renumberStoredTicks(mapRound)
+}
+
+// renumberSlice rewrites the round number of each item in place and keeps the items
+// whose round still counts
+func renumberSlice[T any](items []T, roundNumber func(*T) *int, mapRound func(int) int) []T {
+	kept := items[:0]
+	for i := range items {
+		round := roundNumber(&items[i])
+		if *round = mapRound(*round); *round > 0 {
+			kept = append(kept, items[i])
+		}
+	}
+	return kept
+}
+
+// followingRound extends a renumbering to keep dropped rounds, mapping each to the
+// number the round played after it takes: one past the last earlier round kept
+func followingRound(mapRound func(int) int) func(int) int {
+	return func(round int) int {
+		if newRound := mapRound(round); newRound > 0 {
+			return newRound
+		}
+		for earlier := round - 1; earlier > 0; earlier-- {
+			if newRound := mapRound(earlier); newRound > 0 {
+				return newRound + 1
+			}
+		}
+		return 1
+	}
+}
+
+// renumberStoredTicks applies a renumbering to the tick data already handed to the
+// tick writer, which is written as it is sampled and so includes dropped rounds
+func (ep *EventProcessor) renumberStoredTicks(mapRound func(int) int) {
+	if ep.tickWriter == nil {
+		return
+	}
+
+	if err := ep.tickWriter.RenumberRounds(context.Background(), mapRound); err != nil {
+		ep.logger.WithError(err).Warn("Failed to renumber tick data of dropped rounds")
+	}
+}
@@ -0,0 +1,334 @@
+package parser
+
+import (
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundValidityTracker_Warmup(t *testing.T) {
+	tracker := NewRoundValidityTracker()
+
+	decision := tracker.RoundStarted(100, true, 0)
+	assert.Equal(t, types.RoundValidityWarmup, decision.Validity)
+	assert.False(t, tracker.IsLive())
+
+	end := tracker.RoundEnded(500, events.RoundEndReasonDraw)
+	assert.False(t, end.Live)
+
+	decision = tracker.RoundStarted(600, false, 0)
+	assert.Equal(t, types.RoundValidityLive, decision.Validity)
+	assert.False(t, decision.Restart)
+	assert.True(t, tracker.IsLive())
+
+	require.Len(t, tracker.Discarded(), 1)
+	assert.Equal(t, types.DiscardedRound{Reason: types.RoundValidityWarmup, StartTick: 100, EndTick: 500, Rounds: 1}, tracker.Discarded()[0])
+}
+
+func TestRoundValidityTracker_KnifeRound(t *testing.T) {
+	tracker := NewRoundValidityTracker()
+
+	tracker.RoundStarted(100, false, 0)
+	assert.True(t, tracker.FreezetimeEnded(200, true))
+	assert.Equal(t, types.RoundValidityKnife, tracker.Current())
+
+	end := tracker.RoundEnded(900, events.RoundEndReasonCTWin)
+	assert.False(t, end.Live)
+	assert.False(t, end.DiscardCurrent)
+
+	// The knife round does not count as a completed round, so a 0:0 score is no restart
+	decision := tracker.RoundStarted(1000, false, 0)
+	assert.False(t, decision.Restart)
+	assert.Equal(t, types.RoundValidityLive, decision.Validity)
+	assert.False(t, tracker.FreezetimeEnded(1100, false))
+
+	require.Len(t, tracker.Discarded(), 1)
+	assert.Equal(t, types.DiscardedRound{Reason: types.RoundValidityKnife, StartTick: 100, EndTick: 900, Rounds: 1}, tracker.Discarded()[0])
+}
+
+func TestRoundValidityTracker_LiveOnThree(t *testing.T) {
+	tracker := NewRoundValidityTracker()
+
+	// Each mp_restartgame starts a new round before the previous one ended
+	tracker.RoundStarted(100, false, -1)
+	decision := tracker.RoundStarted(200, false, -1)
+	assert.True(t, decision.DiscardPrevious)
+	decision = tracker.RoundStarted(300, false, -1)
+	assert.True(t, decision.DiscardPrevious)
+
+	end := tracker.RoundEnded(1000, events.RoundEndReasonTerroristsWin)
+	assert.True(t, end.Live)
+	assert.Len(t, tracker.Discarded(), 2)
+}
+
+func TestRoundValidityTracker_ScoreReset(t *testing.T) {
+	tracker := NewRoundValidityTracker()
+
+	tracker.RoundStarted(100, false, 0)
+	tracker.RoundEnded(200, events.RoundEndReasonCTWin)
+	decision := tracker.RoundStarted(300, false, 1)
+	assert.False(t, decision.Restart)
+	tracker.RoundEnded(400, events.RoundEndReasonCTWin)
+
+	decision = tracker.RoundStarted(500, false, 0)
+	assert.True(t, decision.Restart)
+	assert.False(t, decision.DiscardPrevious)
+
+	require.Len(t, tracker.Discarded(), 1)
+	assert.Equal(t, types.DiscardedRound{Reason: types.RoundValidityRestarted, StartTick: 100, EndTick: 500, Rounds: 2}, tracker.Discarded()[0])
+}
+
+func TestRoundValidityTracker_GameCommencing(t *testing.T) {
+	tracker := NewRoundValidityTracker()
+
+	tracker.RoundStarted(100, false, -1)
+	end := tracker.RoundEnded(200, events.RoundEndReasonGameStart)
+	assert.False(t, end.Live)
+	assert.True(t, end.DiscardCurrent)
+	assert.False(t, end.Restart)
+	assert.False(t, tracker.IsLive())
+
+	decision := tracker.RoundStarted(300, false, 0)
+	assert.Equal(t, types.RoundValidityLive, decision.Validity)
+	assert.False(t, decision.Restart)
+}
+
+func TestIsKnifeOnlyLoadout(t *testing.T) {
+	knife := &common.Equipment{Type: common.EqKnife}
+	bomb := &common.Equipment{Type: common.EqBomb}
+	glock := &common.Equipment{Type: common.EqGlock}
+
+	assert.True(t, isKnifeOnlyLoadout([][]*common.Equipment{{knife}, {knife, bomb}}))
+	assert.False(t, isKnifeOnlyLoadout([][]*common.Equipment{{knife}, {knife, glock}}))
+	assert.False(t, isKnifeOnlyLoadout([][]*common.Equipment{{knife}}))
+	assert.False(t, isKnifeOnlyLoadout(nil))
+}
+
+func TestEventProcessor_KnifeRoundIsNotNumbered(t *testing.T) {
+	matchState := &types.MatchState{
+		Players:     make(map[string]*types.Player),
+		RoundEvents: make([]types.RoundEvent, 0),
+	}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+
+	processor.currentTick = 100
+	require.NoError(t, processor.HandleRoundStart(events.RoundStart{}))
+	assert.Equal(t, 1, matchState.CurrentRound)
+	matchState.GunfightEvents = append(matchState.GunfightEvents, types.GunfightEvent{RoundNumber: 1})
+
+	// Without a demo parser there are no loadouts, so mark the knife round directly
+	processor.currentTick = 200
+	require.True(t, processor.roundValidity.FreezetimeEnded(processor.currentTick, true))
+	processor.discardCurrentRound()
+	assert.True(t, processor.shouldSkipCurrentRound())
+
+	processor.currentTick = 900
+	require.NoError(t, processor.HandleRoundEnd(events.RoundEnd{Winner: common.TeamCounterTerrorists, Reason: events.RoundEndReasonCTWin}))
+
+	processor.currentTick = 1000
+	require.NoError(t, processor.HandleRoundStart(events.RoundStart{}))
+
+	assert.Equal(t, 1, matchState.CurrentRound)
+	assert.Empty(t, matchState.GunfightEvents)
+	require.Len(t, matchState.RoundEvents, 1)
+	assert.Equal(t, int64(1000), matchState.RoundEvents[0].TickTimestamp)
+	assert.Equal(t, 1, matchState.RoundEvents[0].RoundNumber)
+}
+
+func TestEventProcessor_MatchRestartKeepsRoundInProgress(t *testing.T) {
+	matchState := &types.MatchState{
+		Players:     make(map[string]*types.Player),
+		RoundEvents: make([]types.RoundEvent, 0),
+	}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+
+	// A completed round, recorded without the end of round analysis
+	processor.currentTick = 100
+	require.NoError(t, processor.HandleRoundStart(events.RoundStart{}))
+	processor.currentTick = 500
+	processor.roundValidity.RoundEnded(processor.currentTick, events.RoundEndReasonCTWin)
+	require.NoError(t, processor.matchHandler.HandleRoundEnd(events.RoundEnd{Winner: common.TeamCounterTerrorists}))
+	matchState.DamageEvents = append(matchState.DamageEvents, types.DamageEvent{RoundNumber: 1})
+
+	processor.currentTick = 600
+	require.NoError(t, processor.HandleRoundStart(events.RoundStart{}))
+	assert.Equal(t, 2, matchState.CurrentRound)
+	matchState.DamageEvents = append(matchState.DamageEvents, types.DamageEvent{RoundNumber: 2})
+
+	processor.currentTick = 700
+	require.NoError(t, processor.HandleMatchStart(events.MatchStart{}))
+
+	assert.Equal(t, 1, matchState.CurrentRound)
+	assert.Equal(t, 1, processor.currentRound)
+	assert.Equal(t, 0, processor.teamAWins+processor.teamBWins)
+	assert.Equal(t, int64(600), matchState.MatchStartTick)
+	require.Len(t, matchState.RoundEvents, 1)
+	assert.Equal(t, 1, matchState.RoundEvents[0].RoundNumber)
+	require.Len(t, matchState.DamageEvents, 1)
+	assert.Equal(t, 1, matchState.DamageEvents[0].RoundNumber)
+
+	require.Len(t, processor.roundValidity.Discarded(), 1)
+	assert.Equal(t, 1, processor.roundValidity.Discarded()[0].Rounds)
+}
+
+func TestRoundValidityTracker_BackupRestore(t *testing.T) {
+	tracker := NewRoundValidityTracker()
+
+	// Rounds 1-4 start at 0:0, 1:0, 2:0 and 2:1
+	for i, score := range []int{0, 1, 2, 3} {
+		decision := tracker.RoundStarted(int64(100*(i+1)), false, score)
+		assert.Zero(t, decision.SupersededRounds)
+		tracker.RoundEnded(int64(100*(i+1)+50), events.RoundEndReasonCTWin)
+	}
+
+	// The admin restores the backup of round 3, which started at a total of 2
+	decision := tracker.RoundStarted(600, false, 2)
+	assert.Equal(t, 2, decision.SupersededRounds)
+	assert.False(t, decision.Restart)
+	assert.Equal(t, types.RoundValidityLive, decision.Validity)
+
+	require.Len(t, tracker.Discarded(), 1)
+	assert.Equal(t, types.DiscardedRound{Reason: types.RoundValiditySuperseded, StartTick: 300, EndTick: 600, Rounds: 2}, tracker.Discarded()[0])
+
+	// Play continues normally from the restored round
+	tracker.RoundEnded(650, events.RoundEndReasonTerroristsWin)
+	decision = tracker.RoundStarted(700, false, 3)
+	assert.Zero(t, decision.SupersededRounds)
+}
+
+func TestRoundValidityTracker_DrawIsNoRollback(t *testing.T) {
+	tracker := NewRoundValidityTracker()
+
+	tracker.RoundStarted(100, false, 0)
+	tracker.RoundEnded(150, events.RoundEndReasonCTWin)
+	tracker.RoundStarted(200, false, 1)
+	tracker.RoundEnded(250, events.RoundEndReasonDraw)
+
+	decision := tracker.RoundStarted(300, false, 1)
+	assert.Zero(t, decision.SupersededRounds)
+	assert.Empty(t, tracker.Discarded())
+}
+
+func TestEventProcessor_BackupRestoreDropsReplayedRounds(t *testing.T) {
+	matchState := &types.MatchState{
+		Players:     make(map[string]*types.Player),
+		RoundEvents: make([]types.RoundEvent, 0),
+	}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+	processor.teamACurrentSide = "CT"
+	processor.teamBCurrentSide = "T"
+
+	// Three completed rounds, all won by team A on CT
+	for round := 1; round <= 3; round++ {
+		processor.currentTick = int64(round * 1000)
+		require.NoError(t, processor.HandleRoundStart(events.RoundStart{}))
+		processor.roundValidity.roundStartScore = round - 1
+		processor.currentTick += 500
+		processor.roundValidity.RoundEnded(processor.currentTick, events.RoundEndReasonCTWin)
+		require.NoError(t, processor.matchHandler.HandleRoundEnd(events.RoundEnd{Winner: common.TeamCounterTerrorists}))
+		matchState.GunfightEvents = append(matchState.GunfightEvents, types.GunfightEvent{RoundNumber: round})
+		matchState.PauseEvents = append(matchState.PauseEvents, types.PauseEvent{RoundNumber: round, PauseType: types.PauseTypeTechnical})
+	}
+	require.Equal(t, 3, processor.teamAWins)
+
+	// Restoring round 3 resets the score total to 2
+	decision := processor.roundValidity.RoundStarted(5000, false, 2)
+	require.Equal(t, 1, decision.SupersededRounds)
+	processor.supersedeRounds(decision.SupersededRounds)
+
+	assert.Equal(t, 2, matchState.CurrentRound)
+	assert.Equal(t, 2, processor.teamAWins)
+	require.Len(t, matchState.GunfightEvents, 2)
+	assert.Equal(t, 2, matchState.GunfightEvents[1].RoundNumber)
+	// The pause of the superseded round precedes its replay, which takes number 3
+	require.Len(t, matchState.PauseEvents, 3)
+	assert.Equal(t, 3, matchState.PauseEvents[2].RoundNumber)
+	for _, event := range matchState.RoundEvents {
+		assert.LessOrEqual(t, event.RoundNumber, 2)
+	}
+}
+
+func TestEventProcessor_TruncateIncompleteRound(t *testing.T) {
+	matchState := &types.MatchState{
+		Players:     make(map[string]*types.Player),
+		RoundEvents: make([]types.RoundEvent, 0),
+	}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+
+	processor.currentTick = 100
+	require.NoError(t, processor.HandleRoundStart(events.RoundStart{}))
+	processor.currentTick = 500
+	processor.roundValidity.RoundEnded(processor.currentTick, events.RoundEndReasonCTWin)
+	require.NoError(t, processor.matchHandler.HandleRoundEnd(events.RoundEnd{Winner: common.TeamCounterTerrorists}))
+	matchState.DamageEvents = append(matchState.DamageEvents, types.DamageEvent{RoundNumber: 1})
+
+	// The server crashes halfway through round 2, during a technical pause
+	processor.currentTick = 600
+	require.NoError(t, processor.HandleRoundStart(events.RoundStart{}))
+	matchState.DamageEvents = append(matchState.DamageEvents, types.DamageEvent{RoundNumber: 2})
+	processor.currentTick = 700
+	processor.UpdatePauseState(PauseState{Technical: true})
+	processor.currentTick = 800
+
+	assert.Equal(t, 1, processor.TruncateIncompleteRound())
+	assert.Equal(t, 1, matchState.CurrentRound)
+	require.Len(t, matchState.DamageEvents, 1)
+	assert.Equal(t, 1, matchState.DamageEvents[0].RoundNumber)
+	for _, event := range matchState.RoundEvents {
+		assert.Equal(t, 1, event.RoundNumber)
+	}
+	assert.Empty(t, matchState.PauseEvents)
+
+	processor.FinishPauses()
+	assert.Empty(t, matchState.PauseEvents)
+
+	require.Len(t, processor.roundValidity.Discarded(), 1)
+	assert.Equal(t, types.DiscardedRound{Reason: types.RoundValidityIncomplete, StartTick: 600, EndTick: 800, Rounds: 1}, processor.roundValidity.Discarded()[0])
+}
+
+func TestEventProcessor_TruncateBetweenRounds(t *testing.T) {
+	matchState := &types.MatchState{
+		Players:     make(map[string]*types.Player),
+		RoundEvents: make([]types.RoundEvent, 0),
+	}
+	processor := NewEventProcessor(matchState, logrus.New(), nil, nil)
+
+	processor.currentTick = 100
+	require.NoError(t, processor.HandleRoundStart(events.RoundStart{}))
+	processor.currentTick = 500
+	processor.roundValidity.RoundEnded(processor.currentTick, events.RoundEndReasonCTWin)
+	require.NoError(t, processor.matchHandler.HandleRoundEnd(events.RoundEnd{Winner: common.TeamCounterTerrorists}))
+
+	processor.currentTick = 550
+	assert.Equal(t, 1, processor.TruncateIncompleteRound())
+	assert.Len(t, matchState.RoundEvents, 2)
+	assert.Empty(t, processor.roundValidity.Discarded())
+}
+
+func TestFollowingRound(t *testing.T) {
+	// Rounds 3 and 4 were replayed, round 5 onwards moves down by two
+	mapRound := followingRound(func(round int) int {
+		switch {
+		case round < 3:
+			return round
+		case round < 5:
+			return 0
+		default:
+			return round - 2
+		}
+	})
+
+	assert.Equal(t, 2, mapRound(2))
+	assert.Equal(t, 3, mapRound(3))
+	assert.Equal(t, 3, mapRound(4))
+	assert.Equal(t, 4, mapRound(6))
+
+	// A dropped first round moves to round 1
+	assert.Equal(t, 1, followingRound(func(int) int { return 0 })(1))
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"sort"
+
+	"parser-service/internal/types"
+)
+
+// AggregateSeries combines the maps of a best-of series, in playing order, into a
+// series result. Team letters of later maps are aligned to the first map by their
+// players, and the maps are modified in place to match.
+func AggregateSeries(maps []*types.ParsedDemoData) *types.SeriesResult {
+	result := &types.SeriesResult{
+		Maps:    maps,
+		Players: make([]types.SeriesPlayerSummary, 0),
+	}
+
+	teams := make(map[string]string)
+	summaries := make(map[string]*types.SeriesPlayerSummary)
+
+	for i, data := range maps {
+		if i > 0 && teamsSwapped(teams, data.Players) {
+			swapTeamLetters(data)
+		}
+
+		switch {
+		case data.Match.WinningTeamScore == data.Match.LosingTeamScore:
+			// A drawn map counts for neither team
+		case data.Match.WinningTeam == "A":
+			result.TeamAMapWins++
+		case data.Match.WinningTeam == "B":
+			result.TeamBMapWins++
+		}
+
+		for _, player := range data.Players {
+			teams[player.SteamID] = player.Team
+
+			summary, exists := summaries[player.SteamID]
+			if !exists {
+				summary = &types.SeriesPlayerSummary{PlayerSteamID: player.SteamID}
+				summaries[player.SteamID] = summary
+			}
+			summary.Name = player.Name
+			summary.Team = player.Team
+		}
+
+		for _, event := range data.PlayerMatchEvents {
+			summary, exists := summaries[event.PlayerSteamID]
+			if !exists {
+				continue
+			}
+
+			summary.MapsPlayed++
+			summary.RoundsPlayed += event.RoundsPlayed
+			summary.Kills += event.Kills
+			summary.Assists += event.Assists
+			summary.Deaths += event.Deaths
+			summary.Damage += event.Damage
+			summary.Headshots += event.Headshots
+			summary.FirstKills += event.FirstKills
+			summary.FirstDeaths += event.FirstDeaths
+			summary.TotalImpact += event.TotalImpact
+		}
+	}
+
+	switch {
+	case result.TeamAMapWins > result.TeamBMapWins:
+		result.WinningTeam = "A"
+	case result.TeamBMapWins > result.TeamAMapWins:
+		result.WinningTeam = "B"
+	}
+
+	for _, summary := range summaries {
+		if summary.RoundsPlayed > 0 {
+			summary.ADR = float64(summary.Damage) / float64(summary.RoundsPlayed)
+			summary.AverageImpact = summary.TotalImpact / float64(summary.RoundsPlayed)
+		}
+		result.Players = append(result.Players, *summary)
+	}
+
+	sort.Slice(result.Players, func(i, j int) bool {
+		if result.Players[i].Team != result.Players[j].Team {
+			return result.Players[i].Team < result.Players[j].Team
+		}
+		return result.Players[i].PlayerSteamID < result.Players[j].PlayerSteamID
+	})
+
+	return result
+}
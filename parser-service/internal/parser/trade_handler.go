@@ -0,0 +1,205 @@
+package parser
+
+import (
+	"sort"
+	"strconv"
+
+	"parser-service/internal/types"
+	"parser-service/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Trade detection constants
+const (
+	TRADE_POSITION_MAX_AGE_SECONDS = 0.25 // How old a teammate's last recorded position may be
+	TRADE_EYE_HEIGHT               = 64.0 // Height above the feet line of sight is checked from
+)
+
+// TradeHandler links deaths to the refrags that traded them
+type TradeHandler struct {
+	processor   *EventProcessor
+	logger      *logrus.Logger
+	losDetector *utils.LOSDetector
+	losMap      string // Map the detector was last loaded for, so a missing map is only tried once
+}
+
+// NewTradeHandler creates a new trade handler
+func NewTradeHandler(processor *EventProcessor, logger *logrus.Logger) *TradeHandler {
+	return &TradeHandler{
+		processor: processor,
+		logger:    logger,
+	}
+}
+
+// tradeDeath is a kill of an enemy during the round
+type tradeDeath struct {
+	tick           int64
+	victimSteamID  string
+	victimSide     string
+	victimPosition types.Position
+	killerSteamID  string
+	killerPosition types.Position
+}
+
+// tradeWindow returns how many ticks after a death killing the killer counts as a trade
+func (th *TradeHandler) tradeWindow() int64 {
+	seconds := types.TradeTimeWindowSeconds
+	if config := th.processor.config; config != nil && config.Parser.TradeWindowSeconds > 0 {
+		seconds = config.Parser.TradeWindowSeconds
+	}
+	return th.processor.Timing().SecondsToTicks(seconds)
+}
+
+// ProcessRoundEnd records a trade event for every death of the round that a teammate
+// was in position to trade, and for every death that was traded
+func (th *TradeHandler) ProcessRoundEnd() {
+	ep := th.processor
+	roundNumber := ep.matchState.CurrentRound
+	deaths := th.roundDeaths(roundNumber)
+	window := th.tradeWindow()
+
+	dead := make(map[string]bool)
+	for i, death := range deaths {
+		dead[death.victimSteamID] = true
+		victimTeam := ep.getAssignedTeam(death.victimSteamID)
+
+		event := types.TradeEvent{
+			RoundNumber:         roundNumber,
+			KillTick:            death.tick,
+			TradedPlayerSteamID: death.victimSteamID,
+			TradedPlayerSide:    death.victimSide,
+			KillerSteamID:       death.killerSteamID,
+			OpportunitySteamIDs: make([]string, 0),
+		}
+
+		// Only killing the original killer counts, not any kill inside the window
+		for _, refrag := range deaths[i+1:] {
+			if refrag.tick-death.tick > window {
+				break
+			}
+			if refrag.victimSteamID == death.killerSteamID && ep.getAssignedTeam(refrag.killerSteamID) == victimTeam {
+				trader := refrag.killerSteamID
+				tradeTick := refrag.tick
+				timeGap := ep.Timing().TicksToSeconds(refrag.tick - death.tick)
+				event.Traded = true
+				event.TraderSteamID = &trader
+				event.TradeTick = &tradeTick
+				event.TimeGapSeconds = &timeGap
+				break
+			}
+		}
+
+		for _, steamID := range ep.roundHandler.getPlayersInRound(roundNumber) {
+			if dead[steamID] || ep.getAssignedTeam(steamID) != victimTeam {
+				continue
+			}
+			if (event.TraderSteamID != nil && *event.TraderSteamID == steamID) || th.couldTrade(steamID, roundNumber, death) {
+				event.OpportunitySteamIDs = append(event.OpportunitySteamIDs, steamID)
+			}
+		}
+
+		if len(event.OpportunitySteamIDs) == 0 {
+			continue
+		}
+		ep.matchState.TradeEvents = append(ep.matchState.TradeEvents, event)
+	}
+}
+
+// roundDeaths returns the round's kills of enemies in the order they happened
+func (th *TradeHandler) roundDeaths(roundNumber int) []tradeDeath {
+	ep := th.processor
+	var deaths []tradeDeath
+
+	for _, gunfightEvent := range ep.matchState.GunfightEvents {
+		if gunfightEvent.RoundNumber != roundNumber || gunfightEvent.VictorSteamID == nil {
+			continue
+		}
+
+		death := tradeDeath{tick: gunfightEvent.TickTimestamp, killerSteamID: *gunfightEvent.VictorSteamID}
+		if gunfightEvent.Player1SteamID == death.killerSteamID {
+			death.victimSteamID = gunfightEvent.Player2SteamID
+			death.victimSide = gunfightEvent.Player2Side
+			death.victimPosition = gunfightEvent.Player2Position
+			death.killerPosition = gunfightEvent.Player1Position
+		} else {
+			death.victimSteamID = gunfightEvent.Player1SteamID
+			death.victimSide = gunfightEvent.Player1Side
+			death.victimPosition = gunfightEvent.Player1Position
+			death.killerPosition = gunfightEvent.Player2Position
+		}
+
+		// Teamkills are nobody's to trade
+		if ep.getAssignedTeam(death.victimSteamID) == ep.getAssignedTeam(death.killerSteamID) {
+			continue
+		}
+		deaths = append(deaths, death)
+	}
+
+	sort.SliceStable(deaths, func(i, j int) bool {
+		return deaths[i].tick < deaths[j].tick
+	})
+	return deaths
+}
+
+// couldTrade reports whether a teammate of the victim was close to the fight or had
+// sight of the killer when the victim died
+func (th *TradeHandler) couldTrade(steamID string, roundNumber int, death tradeDeath) bool {
+	ep := th.processor
+	if ep.grenadeHandler == nil || ep.grenadeHandler.movementService == nil {
+		return false
+	}
+
+	steamID64, err := strconv.ParseUint(steamID, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	maxAge := ep.Timing().SecondsToTicks(TRADE_POSITION_MAX_AGE_SECONDS)
+	position := ep.grenadeHandler.movementService.PositionAt(steamID64, roundNumber, death.tick, maxAge)
+	if position == nil {
+		return false
+	}
+
+	if types.CalculateDistance(*position, death.victimPosition) <= types.TradeDistanceThreshold {
+		return true
+	}
+
+	detector := th.lineOfSight()
+	if detector == nil {
+		return false
+	}
+	visible, _ := detector.CheckLineOfSight(eyePosition(*position), eyePosition(death.killerPosition))
+	return visible
+}
+
+// lineOfSight returns the line of sight detector for the map being parsed, or nil
+// when the map has no geometry
+func (th *TradeHandler) lineOfSight() *utils.LOSDetector {
+	mapName := th.processor.mapName
+	if mapName == "" {
+		return nil
+	}
+	if mapName == th.losMap {
+		return th.losDetector
+	}
+
+	th.losMap = mapName
+	detector, err := utils.NewLOSDetector(mapName)
+	if err != nil {
+		th.logger.WithError(err).WithField("map", mapName).Debug("No map geometry, trade opportunities use distance only")
+		detector = nil
+	}
+	th.losDetector = detector
+	return th.losDetector
+}
+
+// eyePosition returns the point line of sight is checked from for a player standing
+// at a position
+func eyePosition(position types.Position) utils.Vector3 {
+	return utils.Vector3{
+		X: float32(position.X),
+		Y: float32(position.Y),
+		Z: float32(position.Z + TRADE_EYE_HEIGHT),
+	}
+}
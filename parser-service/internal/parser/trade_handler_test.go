@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"parser-service/internal/config"
+	"parser-service/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newTradeTestProcessor sets up players 1-3 on team A and 4-5 on team B. Player 2
+// stands next to player 1 when player 1 dies, player 3 is across the map.
+func newTradeTestProcessor(cfg *config.Config) *EventProcessor {
+	matchState := &types.MatchState{
+		CurrentRound: 1,
+		Players:      make(map[string]*types.Player),
+	}
+	processor := NewEventProcessor(matchState, logrus.New(), cfg, nil)
+
+	for steamID, team := range map[string]string{"1": "A", "2": "A", "3": "A", "4": "B", "5": "B"} {
+		matchState.Players[steamID] = &types.Player{SteamID: steamID}
+		processor.teamAssignments[steamID] = team
+	}
+
+	movement := processor.grenadeHandler.movementService
+	record := func(steamID uint64, tick int64, position types.Position) {
+		movement.positionRecords = append(movement.positionRecords, PlayerPositionRecord{SteamID: steamID, Round: 1, Tick: tick, Position: position})
+		movement.positionIndex[fmt.Sprintf("%d:%d:%d", steamID, 1, tick)] = &movement.positionRecords[len(movement.positionRecords)-1]
+	}
+	record(2, 998, types.Position{X: 100})
+	record(3, 1000, types.Position{X: 5000})
+
+	kill := func(tick int64, killer, victim string) types.GunfightEvent {
+		return types.GunfightEvent{RoundNumber: 1, TickTimestamp: tick, Player1SteamID: killer, Player2SteamID: victim, VictorSteamID: &killer, Player2Side: "CT"}
+	}
+	matchState.GunfightEvents = []types.GunfightEvent{
+		kill(1150, "3", "4"), // Trades player 1
+		kill(1000, "4", "1"),
+		kill(1100, "2", "5"), // Inside the window but not the original killer
+	}
+
+	return processor
+}
+
+func TestTradeHandler_ProcessRoundEnd(t *testing.T) {
+	processor := newTradeTestProcessor(nil)
+	processor.tradeHandler.ProcessRoundEnd()
+
+	tradeEvents := processor.matchState.TradeEvents
+	if len(tradeEvents) != 1 {
+		t.Fatalf("Expected only player 1's death to be tradeable, got %d trade events", len(tradeEvents))
+	}
+
+	trade := tradeEvents[0]
+	if trade.TradedPlayerSteamID != "1" || trade.KillerSteamID != "4" || trade.KillTick != 1000 || trade.TradedPlayerSide != "CT" {
+		t.Errorf("Expected player 1's death to player 4, got %+v", trade)
+	}
+	if !trade.Traded || trade.TraderSteamID == nil || *trade.TraderSteamID != "3" {
+		t.Fatalf("Expected player 3 to trade player 1, got %+v", trade)
+	}
+	if *trade.TradeTick != 1150 || math.Abs(*trade.TimeGapSeconds-150.0/64) > 1e-9 {
+		t.Errorf("Expected the refrag 150 ticks later, got tick %d and gap %f", *trade.TradeTick, *trade.TimeGapSeconds)
+	}
+
+	// Player 2 was close enough, player 3 was not but traded anyway
+	opportunities := append([]string(nil), trade.OpportunitySteamIDs...)
+	sort.Strings(opportunities)
+	if len(opportunities) != 2 || opportunities[0] != "2" || opportunities[1] != "3" {
+		t.Errorf("Expected players 2 and 3 to have had the trade, got %v", opportunities)
+	}
+}
+
+func TestTradeHandler_ProcessRoundEnd_Window(t *testing.T) {
+	cfg := &config.Config{Parser: config.ParserConfig{TradeWindowSeconds: 2}}
+	processor := newTradeTestProcessor(cfg)
+	processor.tradeHandler.ProcessRoundEnd()
+
+	tradeEvents := processor.matchState.TradeEvents
+	if len(tradeEvents) != 1 {
+		t.Fatalf("Expected 1 trade event, got %d", len(tradeEvents))
+	}
+	if trade := tradeEvents[0]; trade.Traded || trade.TraderSteamID != nil {
+		t.Errorf("Expected a refrag after 2 seconds not to be a trade, got %+v", trade)
+	}
+	if opportunities := tradeEvents[0].OpportunitySteamIDs; len(opportunities) != 1 || opportunities[0] != "2" {
+		t.Errorf("Expected only player 2 to have had the trade, got %v", opportunities)
+	}
+}
+
+func TestRoundHandler_AggregateTradeMetrics(t *testing.T) {
+	processor := newTradeTestProcessor(nil)
+	processor.tradeHandler.ProcessRoundEnd()
+
+	tests := []struct {
+		steamID                   string
+		successfulTrades          int
+		totalPossibleTrades       int
+		successfulTradedDeaths    int
+		totalPossibleTradedDeaths int
+	}{
+		{steamID: "1", successfulTradedDeaths: 1, totalPossibleTradedDeaths: 1},
+		{steamID: "2", totalPossibleTrades: 1},
+		{steamID: "3", successfulTrades: 1, totalPossibleTrades: 1},
+		{steamID: "4"},
+	}
+
+	for _, tt := range tests {
+		event := types.PlayerRoundEvent{PlayerSteamID: tt.steamID, RoundNumber: 1}
+		processor.roundHandler.aggregateTradeMetrics(&event, tt.steamID, 1)
+
+		if event.SuccessfulTrades != tt.successfulTrades || event.TotalPossibleTrades != tt.totalPossibleTrades ||
+			event.SuccessfulTradedDeaths != tt.successfulTradedDeaths || event.TotalPossibleTradedDeaths != tt.totalPossibleTradedDeaths {
+			t.Errorf("Player %s: expected trades %d/%d and traded deaths %d/%d, got %d/%d and %d/%d", tt.steamID,
+				tt.successfulTrades, tt.totalPossibleTrades, tt.successfulTradedDeaths, tt.totalPossibleTradedDeaths,
+				event.SuccessfulTrades, event.TotalPossibleTrades, event.SuccessfulTradedDeaths, event.TotalPossibleTradedDeaths)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package radar
+
+import "parser-service/internal/types"
+
+// Annotate adds radar coordinates to every position of a parsed demo
+func Annotate(data *types.ParsedDemoData, overview Overview) {
+	project := func(position *types.Position) {
+		radarPosition := overview.Project(*position)
+		position.Radar = &radarPosition
+	}
+
+	for i := range data.GunfightEvents {
+		project(&data.GunfightEvents[i].Player1Position)
+		project(&data.GunfightEvents[i].Player2Position)
+	}
+
+	for i := range data.GrenadeEvents {
+		project(&data.GrenadeEvents[i].PlayerPosition)
+		if data.GrenadeEvents[i].GrenadeFinalPosition != nil {
+			project(data.GrenadeEvents[i].GrenadeFinalPosition)
+		}
+		for j := range data.GrenadeEvents[i].Trajectory {
+			project(&data.GrenadeEvents[i].Trajectory[j].Position)
+		}
+	}
+
+	for i := range data.BombEvents {
+		project(&data.BombEvents[i].Position)
+	}
+
+	for i := range data.ItemEvents {
+		project(&data.ItemEvents[i].Position)
+	}
+
+	for i := range data.RoundReplays {
+		replay := &data.RoundReplays[i]
+		for j := range replay.Players {
+			for k := range replay.Players[j].Frames {
+				project(&replay.Players[j].Frames[k].Position)
+			}
+		}
+		for j := range replay.Grenades {
+			for k := range replay.Grenades[j].Path {
+				project(&replay.Grenades[j].Path[k])
+			}
+		}
+		for j := range replay.Events {
+			if replay.Events[j].Position != nil {
+				project(replay.Events[j].Position)
+			}
+		}
+	}
+}
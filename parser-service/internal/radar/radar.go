@@ -0,0 +1,147 @@
+// Package radar converts world positions to positions on a map's radar image,
+// using the same overview parameters as the game's radar.
+package radar
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"parser-service/internal/types"
+)
+
+// ImageSize is the width and height in pixels of the radar images the overview
+// parameters are given for
+const ImageSize = 1024.0
+
+//go:embed overviews.json
+var builtinOverviews []byte
+
+// Overview holds a map's radar parameters. PosX and PosY are the world coordinates
+// of the radar image's top left corner and Scale is world units per pixel.
+type Overview struct {
+	Map    string  `json:"map"`
+	PosX   float64 `json:"pos_x"`
+	PosY   float64 `json:"pos_y"`
+	Scale  float64 `json:"scale"`
+	Levels []Level `json:"levels,omitempty"` // Levels besides the default one, each with its own radar image
+}
+
+// Level is a vertical section of a map. A position belongs to the first level whose
+// altitude range contains it; an unset bound is open.
+type Level struct {
+	Name        string   `json:"name"`
+	AltitudeMin *float64 `json:"altitude_min,omitempty"`
+	AltitudeMax *float64 `json:"altitude_max,omitempty"`
+}
+
+// Registry holds the overviews of every known map
+type Registry struct {
+	overviews map[string]Overview
+}
+
+// NewRegistry returns a registry with the built in overviews
+func NewRegistry() *Registry {
+	registry := &Registry{overviews: make(map[string]Overview)}
+
+	var overviews []Overview
+	if err := json.Unmarshal(builtinOverviews, &overviews); err != nil {
+		panic(fmt.Sprintf("invalid built in radar overviews: %v", err))
+	}
+	for _, overview := range overviews {
+		registry.Add(overview)
+	}
+
+	return registry
+}
+
+// LoadRegistry returns a registry with the built in overviews and those in the
+// .json files of dir, which replace built in overviews of the same map. Each file
+// holds one overview or a list of them. An empty dir loads only the built in ones.
+func LoadRegistry(dir string) (*Registry, error) {
+	registry := NewRegistry()
+	if dir == "" {
+		return registry, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list radar overviews in %s: %w", dir, err)
+	}
+
+	for _, path := range paths {
+		overviews, err := readOverviews(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, overview := range overviews {
+			registry.Add(overview)
+		}
+	}
+
+	return registry, nil
+}
+
+func readOverviews(path string) ([]Overview, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read radar overview %s: %w", path, err)
+	}
+
+	var overviews []Overview
+	if strings.HasPrefix(strings.TrimSpace(string(content)), "[") {
+		err = json.Unmarshal(content, &overviews)
+	} else {
+		var overview Overview
+		err = json.Unmarshal(content, &overview)
+		overviews = append(overviews, overview)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse radar overview %s: %w", path, err)
+	}
+
+	for _, overview := range overviews {
+		if overview.Map == "" || overview.Scale <= 0 {
+			return nil, fmt.Errorf("radar overview %s needs a map name and a positive scale", path)
+		}
+	}
+
+	return overviews, nil
+}
+
+// Add adds or replaces the overview of a map
+func (r *Registry) Add(overview Overview) {
+	r.overviews[strings.ToLower(overview.Map)] = overview
+}
+
+// Overview returns the overview of a map
+func (r *Registry) Overview(mapName string) (Overview, bool) {
+	overview, exists := r.overviews[strings.ToLower(mapName)]
+	return overview, exists
+}
+
+// Project converts a world position to a position on the radar image
+func (o Overview) Project(position types.Position) types.RadarPosition {
+	return types.RadarPosition{
+		X:     (position.X - o.PosX) / o.Scale / ImageSize,
+		Y:     (o.PosY - position.Y) / o.Scale / ImageSize,
+		Level: o.LevelAt(position.Z),
+	}
+}
+
+// LevelAt returns the level index of a height, 0 being the default level
+func (o Overview) LevelAt(z float64) int {
+	for i, level := range o.Levels {
+		if level.AltitudeMin != nil && z < *level.AltitudeMin {
+			continue
+		}
+		if level.AltitudeMax != nil && z > *level.AltitudeMax {
+			continue
+		}
+		return i + 1
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+package radar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"parser-service/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistry_BuiltinOverviews(t *testing.T) {
+	registry := NewRegistry()
+
+	for _, mapName := range []string{"de_ancient", "de_anubis", "de_dust2", "de_inferno", "de_mirage", "de_nuke", "de_overpass", "de_train", "de_vertigo"} {
+		overview, exists := registry.Overview(mapName)
+		require.True(t, exists, mapName)
+		assert.Greater(t, overview.Scale, 0.0, mapName)
+	}
+
+	_, exists := registry.Overview("DE_MIRAGE")
+	assert.True(t, exists, "map names are case insensitive")
+
+	_, exists = registry.Overview("de_unknown")
+	assert.False(t, exists)
+}
+
+func TestOverview_Project(t *testing.T) {
+	overview := Overview{Map: "de_test", PosX: -2000, PosY: 2000, Scale: 4}
+
+	topLeft := overview.Project(types.Position{X: -2000, Y: 2000})
+	assert.Equal(t, types.RadarPosition{X: 0, Y: 0, Level: 0}, topLeft)
+
+	bottomRight := overview.Project(types.Position{X: -2000 + 4*ImageSize, Y: 2000 - 4*ImageSize})
+	assert.InDelta(t, 1.0, bottomRight.X, 1e-9)
+	assert.InDelta(t, 1.0, bottomRight.Y, 1e-9)
+
+	centre := overview.Project(types.Position{X: 48, Y: -48})
+	assert.InDelta(t, 0.5, centre.X, 1e-9)
+	assert.InDelta(t, 0.5, centre.Y, 1e-9)
+}
+
+func TestOverview_LevelAt(t *testing.T) {
+	nuke, exists := NewRegistry().Overview("de_nuke")
+	require.True(t, exists)
+
+	assert.Equal(t, 0, nuke.LevelAt(-400))
+	assert.Equal(t, 1, nuke.LevelAt(-600))
+
+	min, max := 100.0, 200.0
+	overview := Overview{Levels: []Level{{Name: "upper", AltitudeMin: &min}, {Name: "middle", AltitudeMin: &min, AltitudeMax: &max}}}
+	assert.Equal(t, 1, overview.LevelAt(150), "the first matching level wins")
+	assert.Equal(t, 0, overview.LevelAt(50))
+}
+
+func TestLoadRegistry_FromDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "custom.json"), []byte(`{"map": "de_custom", "pos_x": -1000, "pos_y": 1000, "scale": 2}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "overrides.json"), []byte(`[{"map": "de_mirage", "pos_x": 0, "pos_y": 0, "scale": 1}]`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`not an overview`), 0644))
+
+	registry, err := LoadRegistry(dir)
+	require.NoError(t, err)
+
+	custom, exists := registry.Overview("de_custom")
+	require.True(t, exists)
+	assert.Equal(t, 2.0, custom.Scale)
+
+	mirage, _ := registry.Overview("de_mirage")
+	assert.Equal(t, 1.0, mirage.Scale)
+
+	_, exists = registry.Overview("de_nuke")
+	assert.True(t, exists, "built in overviews stay available")
+}
+
+func TestLoadRegistry_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"map": "de_broken"}`), 0644))
+
+	_, err := LoadRegistry(dir)
+	assert.Error(t, err)
+}
+
+func TestAnnotate(t *testing.T) {
+	overview := Overview{Map: "de_test", PosX: 0, PosY: 1024, Scale: 1}
+	final := types.Position{X: 512, Y: 512}
+
+	data := &types.ParsedDemoData{
+		GunfightEvents: []types.GunfightEvent{{Player1Position: types.Position{X: 256, Y: 768}, Player2Position: types.Position{X: 768, Y: 256}}},
+		GrenadeEvents:  []types.GrenadeEvent{{PlayerPosition: types.Position{X: 0, Y: 1024}, GrenadeFinalPosition: &final}, {}},
+		BombEvents:     []types.BombEvent{{Position: types.Position{X: 1024, Y: 0}}},
+		ItemEvents:     []types.ItemEvent{{Position: types.Position{X: 512, Y: 1024}}},
+		RoundReplays: []types.RoundReplay{{
+			Players:  []types.ReplayTrack{{Frames: []types.ReplayFrame{{Position: types.Position{X: 256, Y: 768}}}}},
+			Grenades: []types.ReplayGrenade{{Path: []types.Position{{X: 512, Y: 512}}}},
+			Events:   []types.ReplayEvent{{Position: &types.Position{X: 1024, Y: 0}}, {}},
+		}},
+	}
+
+	Annotate(data, overview)
+
+	require.NotNil(t, data.GunfightEvents[0].Player1Position.Radar)
+	assert.Equal(t, types.RadarPosition{X: 0.25, Y: 0.25}, *data.GunfightEvents[0].Player1Position.Radar)
+	assert.Equal(t, types.RadarPosition{X: 0.75, Y: 0.75}, *data.GunfightEvents[0].Player2Position.Radar)
+	assert.Equal(t, types.RadarPosition{X: 0.5, Y: 0.5}, *data.GrenadeEvents[0].GrenadeFinalPosition.Radar)
+	assert.Nil(t, data.GrenadeEvents[1].GrenadeFinalPosition)
+	assert.Equal(t, types.RadarPosition{X: 1, Y: 1}, *data.BombEvents[0].Position.Radar)
+	assert.Equal(t, types.RadarPosition{X: 0.5, Y: 0}, *data.ItemEvents[0].Position.Radar)
+
+	replay := data.RoundReplays[0]
+	assert.Equal(t, types.RadarPosition{X: 0.25, Y: 0.25}, *replay.Players[0].Frames[0].Position.Radar)
+	assert.Equal(t, types.RadarPosition{X: 0.5, Y: 0.5}, *replay.Grenades[0].Path[0].Radar)
+	assert.Equal(t, types.RadarPosition{X: 1, Y: 1}, *replay.Events[0].Position.Radar)
+	assert.Nil(t, replay.Events[1].Position)
+}
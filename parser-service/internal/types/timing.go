@@ -0,0 +1,51 @@
+package types
+
+import "math"
+
+// DefaultTickRate is assumed until the demo reports its own tick interval
+const DefaultTickRate = 64.0
+
+// TickTiming converts between demo ticks and game time. Every second to tick
+// conversion goes through it so 128 tick demos use the same time windows as 64 tick ones.
+type TickTiming struct {
+	TickRate float64
+}
+
+// NewTickTiming returns a timing context for the given tick rate, falling back to
+// DefaultTickRate when the rate is unknown
+func NewTickTiming(tickRate float64) *TickTiming {
+	if tickRate <= 0 || math.IsNaN(tickRate) || math.IsInf(tickRate, 0) {
+		tickRate = DefaultTickRate
+	}
+	return &TickTiming{TickRate: tickRate}
+}
+
+// NewTickTimingFromInterval builds a timing context from a tick interval in seconds
+func NewTickTimingFromInterval(interval float64) *TickTiming {
+	if interval <= 0 {
+		return NewTickTiming(DefaultTickRate)
+	}
+	return NewTickTiming(1 / interval)
+}
+
+// SecondsToTicks converts a duration in seconds to the nearest whole number of ticks
+func (t *TickTiming) SecondsToTicks(seconds float64) int64 {
+	return int64(math.Round(seconds * t.rate()))
+}
+
+// TicksToSeconds converts a tick count to seconds
+func (t *TickTiming) TicksToSeconds(ticks int64) float64 {
+	return float64(ticks) / t.rate()
+}
+
+// TicksToMilliseconds converts a (possibly fractional) tick count to milliseconds
+func (t *TickTiming) TicksToMilliseconds(ticks float64) float64 {
+	return ticks / t.rate() * 1000.0
+}
+
+func (t *TickTiming) rate() float64 {
+	if t == nil || t.TickRate <= 0 {
+		return DefaultTickRate
+	}
+	return t.TickRate
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTickTiming_DefaultsUnknownRate(t *testing.T) {
+	assert.Equal(t, DefaultTickRate, NewTickTiming(0).TickRate)
+	assert.Equal(t, DefaultTickRate, NewTickTiming(-1).TickRate)
+	assert.Equal(t, DefaultTickRate, NewTickTimingFromInterval(0).TickRate)
+	assert.InDelta(t, 128.0, NewTickTimingFromInterval(1.0/128).TickRate, 0.0001)
+}
+
+func TestTickTiming_Conversions(t *testing.T) {
+	tests := []struct {
+		name          string
+		tickRate      float64
+		seconds       float64
+		expectedTicks int64
+	}{
+		{"trade window at 64 tick", 64, TradeTimeWindowSeconds, 192},
+		{"trade window at 128 tick", 128, TradeTimeWindowSeconds, 384},
+		{"spray window at 64 tick", 64, 0.2, 13},
+		{"spray window at 128 tick", 128, 0.2, 26},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timing := NewTickTiming(tt.tickRate)
+			assert.Equal(t, tt.expectedTicks, timing.SecondsToTicks(tt.seconds))
+		})
+	}
+
+	timing := NewTickTiming(128)
+	assert.InDelta(t, 1.5, timing.TicksToSeconds(192), 0.0001)
+	assert.InDelta(t, 250.0, timing.TicksToMilliseconds(32), 0.0001)
+
+	var nilTiming *TickTiming
+	assert.Equal(t, int64(64), nilTiming.SecondsToTicks(1))
+}
@@ -17,6 +17,17 @@ type Position struct {
 	X float64 `json:"x"`
 	Y float64 `json:"y"`
 	Z float64 `json:"z"`
+
+	Radar *RadarPosition `json:"radar,omitempty"` // Set when radar coordinates are enabled and the map is known
+}
+
+// RadarPosition is a position on the map's radar image. X and Y run from 0 to 1
+// starting at the top left corner; Level is 0 for the default level and counts up
+// through the map's other levels, e.g. 1 for the lower level of Nuke.
+type RadarPosition struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Level int     `json:"level"`
 }
 
 type Vector struct {
@@ -34,6 +45,8 @@ type Player struct {
 	RankString *string `json:"rank_string,omitempty"`
 	RankType   *string `json:"rank_type,omitempty"`
 	RankValue  *int    `json:"rank_value,omitempty"`
+
+	IsBot bool `json:"is_bot"`
 }
 
 type GunfightEvent struct {
@@ -59,12 +72,23 @@ type GunfightEvent struct {
 
 	Player1Position Position `json:"player_1_position"`
 	Player2Position Position `json:"player_2_position"`
+	Player1Place    string   `json:"player_1_place,omitempty"` // Callout name of the position
+	Player2Place    string   `json:"player_2_place,omitempty"`
 
 	Distance          float64 `json:"distance"`
 	Headshot          bool    `json:"headshot"`
 	Wallbang          bool    `json:"wallbang"`
 	PenetratedObjects int     `json:"penetrated_objects"`
 
+	// Kill modifiers, from the kill feed where the demo has them
+	ThroughSmoke        bool    `json:"through_smoke"`
+	NoScope             bool    `json:"no_scope"`
+	AttackerBlind       bool    `json:"attacker_blind"`
+	AttackerInAir       bool    `json:"attacker_in_air"`
+	VictimBlindDuration float64 `json:"victim_blind_duration"` // Seconds of blindness the victim had left
+	AssistedFlash       bool    `json:"assisted_flash"`
+	DistanceBucket      string  `json:"distance_bucket"` // One of the DistanceBucket constants
+
 	VictorSteamID        *string `json:"victor_steam_id,omitempty"`
 	DamageDealt          int     `json:"damage_dealt"`
 	IsFirstKill          bool    `json:"is_first_kill"`
@@ -93,7 +117,7 @@ type GrenadeEvent struct {
 	RoundNumber   int   `json:"round_number"`
 	RoundTime     int   `json:"round_time"`
 	TickTimestamp int64 `json:"tick_timestamp"`
-	ExplosionTick int64 `json:"explosion_tick,omitempty"` // For flashbang matching
+	ExplosionTick int64 `json:"explosion_tick,omitempty"` // Tick the grenade went off, also used for flashbang matching
 
 	PlayerSteamID string `json:"player_steam_id"`
 	PlayerSide    string `json:"player_side"` // "CT" or "T"
@@ -101,9 +125,17 @@ type GrenadeEvent struct {
 	EntityID      int    `json:"entity_id,omitempty"` // For flashbang matching
 
 	PlayerPosition Position `json:"player_position"`
+	PlayerPlace    string   `json:"player_place,omitempty"` // Callout name of the throw position
 	PlayerAim      Vector   `json:"player_aim"`
 
 	GrenadeFinalPosition *Position `json:"grenade_final_position,omitempty"`
+	GrenadeFinalPlace    string    `json:"grenade_final_place,omitempty"`
+
+	// Flight from the throw to the detonation, sampled every tick and thinned out to
+	// the configured number of points
+	Trajectory     []TrajectoryPoint `json:"trajectory,omitempty"`
+	BounceCount    int               `json:"bounce_count"`
+	AirtimeSeconds float64           `json:"airtime_seconds"` // From the throw to the detonation
 
 	DamageDealt     int              `json:"damage_dealt"`
 	TeamDamageDealt int              `json:"team_damage_dealt"`
@@ -123,17 +155,41 @@ type GrenadeEvent struct {
 	// Smoke blocking tracking
 	SmokeBlockingDuration int `json:"smoke_blocking_duration"` // Total ticks the smoke blocked enemy LOS
 
+	// Fire tracking, set on molotovs and incendiaries from the inferno they started
+	BurnDurationSeconds float64 `json:"burn_duration_seconds"`
+	FireAreaCovered     float64 `json:"fire_area_covered"`     // Largest area on fire at once, in square units
+	EnemiesDisplaced    int     `json:"enemies_displaced"`     // Enemies that left the fire or took damage from it
+	KillsDuringBurn     int     `json:"kills_during_burn"`     // Enemies killed in the fire area while it burned
+	ExtinguishedBySmoke bool    `json:"extinguished_by_smoke"` // A smoke put the fire out
+
 	ThrowType string `json:"throw_type"`
 
 	EffectivenessRating int `json:"effectiveness_rating"`
 }
 
+// TrajectoryPoint is a position of a grenade in flight
+type TrajectoryPoint struct {
+	Tick     int64    `json:"tick"`
+	Position Position `json:"position"`
+	Bounce   bool     `json:"bounce,omitempty"` // The grenade bounced here
+}
+
 type RoundEvent struct {
 	RoundNumber   int     `json:"round_number"`
 	TickTimestamp int64   `json:"tick_timestamp"`
 	EventType     string  `json:"event_type"`
 	Winner        *string `json:"winner,omitempty"`
-	Duration      *int    `json:"duration,omitempty"`
+	Duration      *int    `json:"duration,omitempty"` // Seconds of live play, from freeze time end to round end
+
+	// Round timing, set on "end" events
+	FreezeEndTick     *int64   `json:"freeze_end_tick,omitempty"`
+	EndReason         *string  `json:"end_reason,omitempty"`          // One of the RoundEndReason constants
+	BombPlantTick     *int64   `json:"bomb_plant_tick,omitempty"`     // Tick the bomb was planted
+	BombPlantTime     *float64 `json:"bomb_plant_time,omitempty"`     // Seconds after freeze time ended
+	BombSite          *string  `json:"bomb_site,omitempty"`           // "A" or "B"
+	BombDefuseTick    *int64   `json:"bomb_defuse_tick,omitempty"`    // Tick the bomb was defused
+	BombExplodeTick   *int64   `json:"bomb_explode_tick,omitempty"`   // Tick the bomb exploded
+	BombPhaseDuration *float64 `json:"bomb_phase_duration,omitempty"` // Seconds from plant to defuse, explosion or round end
 
 	// Impact Rating Fields
 	TotalImpact       float64 `json:"total_impact"`
@@ -143,6 +199,183 @@ type RoundEvent struct {
 	ImpactPercentage  float64 `json:"impact_percentage"`
 }
 
+// PauseEvent records a tactical timeout or technical pause. Pauses are kept even
+// when the round they happened in is later discarded, since the time was still spent;
+// they move to the round played next, which is usually the restarted round. Only a
+// pause in a round cut off by the end of the demo is dropped with it.
+type PauseEvent struct {
+	RoundNumber int     `json:"round_number"`
+	PauseType   string  `json:"pause_type"`     // One of the PauseType constants
+	Side        *string `json:"side,omitempty"` // "CT" or "T" for tactical timeouts
+	Team        *string `json:"team,omitempty"` // Team letter that called a tactical timeout
+	StartTick   int64   `json:"start_tick"`
+	EndTick     int64   `json:"end_tick"`
+	Duration    float64 `json:"duration"` // Seconds
+}
+
+// Substitution records a player taking over the team slot of a player who left
+// during the match. A player who reconnects keeps their identity and is not a substitute.
+type Substitution struct {
+	Team             string `json:"team"` // Team letter
+	PlayerOutSteamID string `json:"player_out_steam_id"`
+	PlayerInSteamID  string `json:"player_in_steam_id"`
+	RoundNumber      int    `json:"round_number"` // First round the substitute played
+
+	// Ticks the replaced player and the substitute were on the team
+	PlayerOutStartTick int64 `json:"player_out_start_tick"`
+	PlayerOutEndTick   int64 `json:"player_out_end_tick"`
+	PlayerInStartTick  int64 `json:"player_in_start_tick"`
+	PlayerInEndTick    int64 `json:"player_in_end_tick"`
+}
+
+// BombEvent records something happening to the bomb. TimeRemaining is read from
+// the round clock before the plant and from the bomb timer after it.
+type BombEvent struct {
+	RoundNumber   int   `json:"round_number"`
+	RoundTime     int   `json:"round_time"`
+	TickTimestamp int64 `json:"tick_timestamp"`
+
+	EventType     string   `json:"event_type"`                // One of the BombEventType constants
+	PlayerSteamID *string  `json:"player_steam_id,omitempty"` // Not set in POV demos or for the explosion
+	PlayerSide    string   `json:"player_side,omitempty"`
+	Site          string   `json:"site,omitempty"`  // "A" or "B" for plant, defuse and explosion events
+	Position      Position `json:"position"`        // Player position, or bomb position once it is planted
+	Place         string   `json:"place,omitempty"` // Callout name of the position
+	HasKit        bool     `json:"has_kit"`         // Defuser had a kit, set on defuse events
+	TimeRemaining float64  `json:"time_remaining"`  // Seconds
+}
+
+// TeamEconomyEvent summarises one team's economy in a round. Money is read from the
+// players' money fields; equipment values are what the game reports for the team.
+type TeamEconomyEvent struct {
+	RoundNumber int    `json:"round_number"`
+	Team        string `json:"team"` // Team letter
+	Side        string `json:"side"` // "CT" or "T"
+	Players     int    `json:"players"`
+
+	StartMoney              int    `json:"start_money"` // Money before buying, including the round's income
+	MoneySpent              int    `json:"money_spent"`
+	SavedEquipmentValue     int    `json:"saved_equipment_value"` // Equipment kept from the previous round
+	FreezeEndEquipmentValue int    `json:"freeze_end_equipment_value"`
+	LossBonusLevel          int    `json:"loss_bonus_level"` // Losses counted towards the loss bonus, 0 to MaxLossBonusLevel
+	LossBonus               int    `json:"loss_bonus"`       // Money each player gets for losing this round
+	BuyType                 string `json:"buy_type"`         // One of the TeamBuyType constants
+}
+
+// ItemEvent records an item being bought, dropped or picked up. A drop picked up
+// by a teammate is linked to the teammate, and the pickup to the player who dropped it.
+type ItemEvent struct {
+	RoundNumber   int   `json:"round_number"`
+	RoundTime     int   `json:"round_time"`
+	TickTimestamp int64 `json:"tick_timestamp"`
+
+	EventType     string   `json:"event_type"` // One of the ItemEvent constants
+	PlayerSteamID string   `json:"player_steam_id"`
+	PlayerSide    string   `json:"player_side"`
+	Item          string   `json:"item"`
+	Price         int      `json:"price"` // Money paid for purchases, the item's value otherwise
+	Position      Position `json:"position"`
+
+	RecipientSteamID *string `json:"recipient_steam_id,omitempty"`  // Teammate who picked up a dropped item
+	DroppedBySteamID *string `json:"dropped_by_steam_id,omitempty"` // Teammate who dropped a picked up item
+}
+
+// LoadoutEvent is a player's inventory when freeze time ends, after buying
+type LoadoutEvent struct {
+	RoundNumber   int    `json:"round_number"`
+	TickTimestamp int64  `json:"tick_timestamp"`
+	PlayerSteamID string `json:"player_steam_id"`
+	PlayerSide    string `json:"player_side"`
+
+	Primary      string         `json:"primary,omitempty"`   // Rifle, SMG or heavy weapon
+	Secondary    string         `json:"secondary,omitempty"` // Pistol
+	Grenades     map[string]int `json:"grenades"`            // Count by grenade type
+	GrenadeValue int            `json:"grenade_value"`
+	Armor        int            `json:"armor"`
+	HasHelmet    bool           `json:"has_helmet"`
+	HasDefuseKit bool           `json:"has_defuse_kit"`
+	HasZeus      bool           `json:"has_zeus"`
+
+	EquipmentValue int `json:"equipment_value"`
+	MoneyLeft      int `json:"money_left"`
+}
+
+// TradeEvent is a death that a teammate of the victim was in position to trade. When
+// a teammate killed the original killer inside the trade window, the event links the
+// original kill to that refrag.
+type TradeEvent struct {
+	RoundNumber         int      `json:"round_number"`
+	KillTick            int64    `json:"kill_tick"`
+	TradedPlayerSteamID string   `json:"traded_player_steam_id"`
+	TradedPlayerSide    string   `json:"traded_player_side"`
+	KillerSteamID       string   `json:"killer_steam_id"`
+	OpportunitySteamIDs []string `json:"opportunity_steam_ids"` // Teammates close to or with sight of the fight
+
+	Traded         bool     `json:"traded"`
+	TraderSteamID  *string  `json:"trader_steam_id,omitempty"`
+	TradeTick      *int64   `json:"trade_tick,omitempty"`
+	TimeGapSeconds *float64 `json:"time_gap_seconds,omitempty"` // Between the original kill and the refrag
+}
+
+// Replay event types
+const (
+	ReplayEventKill    = "kill"
+	ReplayEventDamage  = "damage"
+	ReplayEventUtility = "utility" // A grenade went off
+	ReplayEventBomb    = "bomb"
+)
+
+// RoundReplay is everything a 2D viewer needs to play back a round: downsampled
+// player tracks, grenade flights and the round's events keyed by tick
+type RoundReplay struct {
+	RoundNumber    int     `json:"round_number"`
+	StartTick      int64   `json:"start_tick"`
+	EndTick        int64   `json:"end_tick"`
+	TickRate       float64 `json:"tick_rate"`
+	SampleInterval int64   `json:"sample_interval"` // Ticks between two frames of a track
+
+	Players  []ReplayTrack   `json:"players"`
+	Grenades []ReplayGrenade `json:"grenades"`
+	Events   []ReplayEvent   `json:"events"` // In tick order
+}
+
+// ReplayTrack holds one player's frames from the round start until their death
+type ReplayTrack struct {
+	PlayerSteamID string        `json:"player_steam_id"`
+	Team          string        `json:"team"` // "A" or "B"
+	Side          string        `json:"side"` // "CT" or "T"
+	Frames        []ReplayFrame `json:"frames"`
+}
+
+type ReplayFrame struct {
+	Tick     int64    `json:"tick"`
+	Position Position `json:"position"`
+	Yaw      float64  `json:"yaw"` // View direction in degrees
+	Pitch    float64  `json:"pitch"`
+	Health   int      `json:"health"`
+	Weapon   string   `json:"weapon,omitempty"` // Active weapon
+}
+
+type ReplayGrenade struct {
+	PlayerSteamID  string     `json:"player_steam_id"`
+	PlayerSide     string     `json:"player_side"`
+	GrenadeType    string     `json:"grenade_type"`
+	ThrowTick      int64      `json:"throw_tick"`
+	DetonationTick int64      `json:"detonation_tick"`
+	Path           []Position `json:"path"` // From the throw position to where the grenade went off
+}
+
+type ReplayEvent struct {
+	Tick          int64     `json:"tick"`
+	Type          string    `json:"type"`                      // One of the ReplayEvent constants
+	PlayerSteamID string    `json:"player_steam_id,omitempty"` // Killer, attacker, thrower or bomb player
+	TargetSteamID string    `json:"target_steam_id,omitempty"` // Victim of a kill or damage
+	Detail        string    `json:"detail,omitempty"`          // Weapon, grenade type or bomb event type
+	Damage        int       `json:"damage,omitempty"`
+	Headshot      bool      `json:"headshot,omitempty"`
+	Position      *Position `json:"position,omitempty"`
+}
+
 type DamageEvent struct {
 	RoundNumber   int   `json:"round_number"`
 	RoundTime     int   `json:"round_time"`
@@ -150,6 +383,8 @@ type DamageEvent struct {
 
 	AttackerSteamID string `json:"attacker_steam_id"`
 	VictimSteamID   string `json:"victim_steam_id"`
+	AttackerPlace   string `json:"attacker_place,omitempty"` // Callout names of the positions
+	VictimPlace     string `json:"victim_place,omitempty"`
 
 	Damage       int    `json:"damage"`
 	ArmorDamage  int    `json:"armor_damage"`
@@ -232,6 +467,7 @@ type PlayerRoundEvent struct {
 type PlayerMatchEvent struct {
 	// Basic fields
 	PlayerSteamID string `json:"player_steam_id"`
+	RoundsPlayed  int    `json:"rounds_played"` // Averages are per round played, not per match round
 
 	// Gun Fights
 	Kills                   int     `json:"kills"`
@@ -308,6 +544,7 @@ type GrenadeThrowInfo struct {
 }
 
 type Match struct {
+	MatchID          string     `json:"match_id"` // Key for stored per-match data such as shooting data
 	Map              string     `json:"map"`
 	WinningTeam      string     `json:"winning_team"` // "A" or "B"
 	WinningTeamScore int        `json:"winning_team_score"`
@@ -315,9 +552,24 @@ type Match struct {
 	MatchType        string     `json:"match_type"`          // Legacy field for backward compatibility
 	GameMode         *GameMode  `json:"game_mode,omitempty"` // Detailed game mode information
 	StartTimestamp   *time.Time `json:"start_timestamp,omitempty"`
-	EndTimestamp     *time.Time `json:"end_timestamp,omitempty"`
+	EndTimestamp     *time.Time `json:"end_timestamp,omitempty"` // Parse time; demos record no wall clock time, the start is derived from the duration
+	StartTick        int64      `json:"start_tick"`              // Tick the match went live
+	EndTick          int64      `json:"end_tick"`                // Tick the last round ended
+	StartTime        float64    `json:"start_time"`              // Seconds into the demo the match went live
+	EndTime          float64    `json:"end_time"`                // Seconds into the demo the last round ended
+	DurationSeconds  float64    `json:"duration_seconds"`        // Match length in demo time
 	TotalRounds      int        `json:"total_rounds"`
 	PlaybackTicks    int        `json:"playback_ticks"` // Match duration in ticks from demo header
+
+	// How MatchType was determined
+	MatchTypeDetection *MatchTypeDetection `json:"match_type_detection,omitempty"`
+}
+
+// MatchTypeDetection explains how the match type was determined
+type MatchTypeDetection struct {
+	MatchType  string   `json:"match_type"`
+	Confidence float64  `json:"confidence"` // 0 to 1
+	Evidence   []string `json:"evidence"`
 }
 
 // GameMode represents the detected game mode
@@ -328,6 +580,14 @@ type GameMode struct {
 	HasHalftime bool   `json:"has_halftime"` // Whether this mode has halftime
 }
 
+// DiscardedRound records rounds left out of the match because they were not competitive
+type DiscardedRound struct {
+	Reason    string `json:"reason"` // One of the RoundValidity constants other than live
+	StartTick int64  `json:"start_tick"`
+	EndTick   int64  `json:"end_tick"`
+	Rounds    int    `json:"rounds"` // Number of rounds dropped, more than one when a restart discards played rounds
+}
+
 type ParsedDemoData struct {
 	Match             Match                     `json:"match"`
 	Players           []Player                  `json:"players"`
@@ -340,6 +600,77 @@ type ParsedDemoData struct {
 	AimEvents         []AimAnalysisResult       `json:"aim_events"`
 	AimWeaponEvents   []WeaponAimAnalysisResult `json:"aim_weapon_events"`
 	Achievements      []Achievement             `json:"achievements"`
+	PauseEvents       []PauseEvent              `json:"pause_events"`
+	DiscardedRounds   []DiscardedRound          `json:"discarded_rounds,omitempty"`
+	Substitutions     []Substitution            `json:"substitutions"`
+	BombEvents        []BombEvent               `json:"bomb_events"`
+	TeamEconomyEvents []TeamEconomyEvent        `json:"team_economy_events"`
+	ItemEvents        []ItemEvent               `json:"item_events"`
+	LoadoutEvents     []LoadoutEvent            `json:"loadout_events"`
+	TradeEvents       []TradeEvent              `json:"trade_events"`
+	RoundReplays      []RoundReplay             `json:"round_replays,omitempty"`
+
+	// Per round aim results AimEvents and AimWeaponEvents were aggregated from, kept
+	// so stitching can leave out replayed rounds before aggregating again
+	RoundAimEvents       []AimAnalysisResult       `json:"-"`
+	RoundAimWeaponEvents []WeaponAimAnalysisResult `json:"-"`
+
+	// Partial is set when the demo ended unexpectedly and only the rounds completed
+	// before that point are included
+	Partial           bool              `json:"partial"`
+	LastCompleteRound int               `json:"last_complete_round"`
+	Diagnostics       []ParseDiagnostic `json:"diagnostics,omitempty"`
+
+	// Demo files the match was stitched together from, in playing order. Empty when
+	// the match was parsed from a single demo.
+	Segments []DemoSegment `json:"segments,omitempty"`
+}
+
+// DemoSegment records which rounds of a stitched match came from which demo file.
+// Ticks are relative to the demo file the event was recorded in.
+type DemoSegment struct {
+	Index      int    `json:"index"`    // Position of the demo in the submitted order
+	MatchID    string `json:"match_id"` // ID the demo was parsed under; its stored data is moved under the match's ID
+	FirstRound int    `json:"first_round"`
+	LastRound  int    `json:"last_round"`
+	EndTick    int64  `json:"end_tick"` // Tick the demo's last kept round ended, 0 when unknown
+	Partial    bool   `json:"partial"`
+}
+
+// SeriesResult holds every map of a best-of series and the totals over the series.
+// Team letters are aligned across maps, so team "A" is the same team on every map.
+type SeriesResult struct {
+	Maps         []*ParsedDemoData     `json:"maps"`
+	TeamAMapWins int                   `json:"team_a_map_wins"`
+	TeamBMapWins int                   `json:"team_b_map_wins"`
+	WinningTeam  string                `json:"winning_team"` // "A", "B" or empty when the series is tied
+	Players      []SeriesPlayerSummary `json:"players"`
+}
+
+// SeriesPlayerSummary totals a player's performance over the maps of a series
+type SeriesPlayerSummary struct {
+	PlayerSteamID string  `json:"player_steam_id"`
+	Name          string  `json:"name"`
+	Team          string  `json:"team"` // Team letter
+	MapsPlayed    int     `json:"maps_played"`
+	RoundsPlayed  int     `json:"rounds_played"`
+	Kills         int     `json:"kills"`
+	Assists       int     `json:"assists"`
+	Deaths        int     `json:"deaths"`
+	Damage        int     `json:"damage"`
+	ADR           float64 `json:"adr"`
+	Headshots     int     `json:"headshots"`
+	FirstKills    int     `json:"first_kills"`
+	FirstDeaths   int     `json:"first_deaths"`
+	TotalImpact   float64 `json:"total_impact"`
+	AverageImpact float64 `json:"average_impact"` // Per round played
+}
+
+// ParseDiagnostic describes a problem the parser recovered from
+type ParseDiagnostic struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Tick    int64  `json:"tick"`
 }
 
 // Achievement represents an achievement awarded to a player
@@ -356,6 +687,26 @@ type ParseDemoRequest struct {
 	DemoFile              *multipart.FileHeader `form:"demo_file" binding:"required"`
 }
 
+// ParseMatchRequest submits demo files that each hold part of one match, e.g. after
+// a server crash. Files are parsed in the order they were uploaded.
+type ParseMatchRequest struct {
+	JobID                 string                  `form:"job_id"`
+	ProgressCallbackURL   string                  `form:"progress_callback_url" binding:"required"`
+	CompletionCallbackURL string                  `form:"completion_callback_url" binding:"required"`
+	DemoFiles             []*multipart.FileHeader `form:"demo_files" binding:"required"`
+}
+
+// ParseSeriesRequest submits the demo files of a best-of series in playing order.
+// MapNumbers gives the map each file belongs to, so that a map split across several
+// files is stitched together. Without it every file is a separate map.
+type ParseSeriesRequest struct {
+	JobID                 string                  `form:"job_id"`
+	ProgressCallbackURL   string                  `form:"progress_callback_url" binding:"required"`
+	CompletionCallbackURL string                  `form:"completion_callback_url" binding:"required"`
+	DemoFiles             []*multipart.FileHeader `form:"demo_files" binding:"required"`
+	MapNumbers            []int                   `form:"map_numbers"`
+}
+
 type ParseDemoResponse struct {
 	Success bool   `json:"success"`
 	JobID   string `json:"job_id"`
@@ -390,7 +741,8 @@ type CompletionData struct {
 
 type ProcessingJob struct {
 	JobID                 string
-	TempFilePath          string // Path to temporary uploaded file
+	TempFilePath          string   // Path to temporary uploaded file
+	TempFilePaths         []string // Paths of all uploaded files when a job has several demos
 	ProgressCallbackURL   string
 	CompletionCallbackURL string
 	Status                string
@@ -413,6 +765,8 @@ type MatchState struct {
 	TotalRounds        int
 	RoundStartTick     int64
 	RoundEndTick       int64
+	MatchStartTick     int64
+	MatchEndTick       int64
 	MapName            string
 	Players            map[string]*Player
 	RoundEvents        []RoundEvent
@@ -421,10 +775,25 @@ type MatchState struct {
 	DamageEvents       []DamageEvent
 	PlayerRoundEvents  []PlayerRoundEvent
 	PlayerMatchEvents  []PlayerMatchEvent
+	PauseEvents        []PauseEvent
+	Substitutions      []Substitution
+	BombEvents         []BombEvent
+	TeamEconomyEvents  []TeamEconomyEvent
+	ItemEvents         []ItemEvent
+	LoadoutEvents      []LoadoutEvent
+	TradeEvents        []TradeEvent
+	RoundReplays       []RoundReplay
 	CurrentRoundKills  int
 	CurrentRoundDeaths int
 	FirstKillPlayer    *string
 	FirstDeathPlayer   *string
+
+	// Timing of the round in progress, reset at every round start
+	RoundFreezeEndTick   int64
+	RoundBombPlantTick   int64
+	RoundBombSite        string
+	RoundBombDefuseTick  int64
+	RoundBombExplodeTick int64
 }
 
 type PlayerState struct {
@@ -515,6 +884,11 @@ const (
 	MatchTypeESPortal = "esportal"
 	MatchTypeOther    = "other"
 	MatchTypeUnknown  = "unknown"
+
+	MatchTypeESEA       = "esea"
+	MatchTypeESL        = "esl"
+	MatchTypeTournament = "tournament" // Tournament plugins such as Get5 or MatchZy
+	MatchTypeCommunity  = "community"  // Community servers such as CyberShoke
 )
 
 // Weapon categories for aim tracking
@@ -538,7 +912,70 @@ const (
 
 // Game timing constants
 const (
-	CS2FreezeTime = 15 // Freeze time duration in seconds for CS2
+	CS2FreezeTime = 15  // Freeze time duration in seconds for CS2
+	CS2RoundTime  = 115 // Round duration in seconds after freeze time, used when the demo does not report it
+	CS2BombTime   = 40  // Bomb timer in seconds, used when the demo does not report it
+)
+
+// Team buy types recorded on TeamEconomyEvent.BuyType
+const (
+	TeamBuyTypePistol  = "pistol"
+	TeamBuyTypeEco     = "eco"
+	TeamBuyTypeForce   = "force"
+	TeamBuyTypeHalfBuy = "half_buy"
+	TeamBuyTypeFullBuy = "full_buy"
+)
+
+// Bomb event types recorded on BombEvent.EventType
+const (
+	BombEventPlantStart  = "plant_start"
+	BombEventPlantAbort  = "plant_abort"
+	BombEventPlanted     = "planted"
+	BombEventDefuseStart = "defuse_start"
+	BombEventDefuseAbort = "defuse_abort"
+	BombEventDefused     = "defused"
+	BombEventExploded    = "exploded"
+	BombEventDropped     = "dropped"
+	BombEventPickedUp    = "picked_up"
+)
+
+// Item event types recorded on ItemEvent.EventType
+const (
+	ItemEventPurchase = "purchase"
+	ItemEventRefund   = "refund"
+	ItemEventDrop     = "drop"
+	ItemEventPickup   = "pickup"
+)
+
+// Round end reasons recorded on RoundEvent.EndReason
+const (
+	RoundEndReasonElimination     = "elimination"
+	RoundEndReasonBombExploded    = "bomb_exploded"
+	RoundEndReasonBombDefused     = "bomb_defused"
+	RoundEndReasonTimeExpired     = "time_expired"
+	RoundEndReasonSurrender       = "surrender"
+	RoundEndReasonHostagesRescued = "hostages_rescued"
+	RoundEndReasonDraw            = "draw"
+	RoundEndReasonGameStart       = "game_start"
+	RoundEndReasonOther           = "other"
+)
+
+// Round validity classifications. Only live rounds are numbered and counted.
+const (
+	RoundValidityLive      = "live"
+	RoundValidityWarmup    = "warmup"
+	RoundValidityKnife     = "knife"
+	RoundValidityRestarted = "restarted"
+	// Rounds replayed after an admin restored an earlier backup
+	RoundValiditySuperseded = "superseded"
+	// The round still in progress when a truncated demo ended
+	RoundValidityIncomplete = "incomplete"
+)
+
+// Pause types reported on PauseEvent
+const (
+	PauseTypeTacticalTimeout = "tactical_timeout"
+	PauseTypeTechnical       = "technical"
 )
 
 // Damage assist constants
@@ -553,10 +990,20 @@ const (
 	GrenadeDamageWindow = 1.5 + BufferDuration
 )
 
+// Kill distance buckets
+const (
+	DistanceBucketClose  = "close"
+	DistanceBucketMedium = "medium"
+	DistanceBucketLong   = "long"
+
+	CloseDistanceMax  = 500.0  // In-game units up to which a kill is close range
+	MediumDistanceMax = 1500.0 // In-game units up to which a kill is medium range
+)
+
 // Trade constants
 const (
-	TradeTimeWindowSeconds = 3.0   // 3 seconds window for trades
-	TradeDistanceThreshold = 250.0 // 250 in-game units distance for trade eligibility
+	TradeTimeWindowSeconds = 3.0   // Default window for trades, in seconds
+	TradeDistanceThreshold = 250.0 // A teammate within this many in-game units can trade without line of sight
 )
 
 // Economy constants
@@ -564,6 +1011,15 @@ const (
 	EcoThreshold      = 2000 // Below or equal to this value = eco round
 	ForceBuyThreshold = 4000 // Between eco and this value = force buy round
 	// Above ForceBuyThreshold = full buy round
+
+	// A team between eco and full buy that keeps at least this much money per player
+	// on average is half-buying to afford the next round; otherwise it is forcing
+	HalfBuyMoneyLeftThreshold = 2000
+
+	// Loss bonus: the base amount plus one step for every counted loss
+	BaseLossBonus     = 1400
+	LossBonusStep     = 500
+	MaxLossBonusLevel = 4
 )
 
 // Impact Rating constants
@@ -721,6 +1177,18 @@ func CalculateDistance(pos1, pos2 Position) float64 {
 	return math.Sqrt(dx*dx + dy*dy + dz*dz)
 }
 
+// GetDistanceBucket returns the bucket of a kill distance in in-game units
+func GetDistanceBucket(distance float64) string {
+	switch {
+	case distance <= CloseDistanceMax:
+		return DistanceBucketClose
+	case distance <= MediumDistanceMax:
+		return DistanceBucketMedium
+	default:
+		return DistanceBucketLong
+	}
+}
+
 func NormalizeVector(v Vector) Vector {
 	length := math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
 	if length == 0 {
@@ -994,6 +1462,33 @@ func (PlayerTickData) TableName() string {
 	return "player_tick_data"
 }
 
+// Tick storage formats for player tick data
+const (
+	TickStorageRows     = "rows"
+	TickStorageColumnar = "columnar"
+)
+
+// PlayerTickBlob stores a run of one player's tick data within a round as a single
+// delta-encoded, compressed blob. StartTick and EndTick form the tick index used to
+// select blobs overlapping a tick range without decoding them.
+type PlayerTickBlob struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	MatchID     string    `gorm:"type:varchar(36);not null;index:idx_blob_match_ticks" json:"match_id"`
+	RoundNumber int       `gorm:"not null" json:"round_number"`
+	PlayerID    string    `gorm:"type:varchar(20);not null" json:"player_id"`
+	Team        string    `gorm:"type:varchar(10);not null" json:"team"`
+	StartTick   int64     `gorm:"not null;index:idx_blob_match_ticks" json:"start_tick"`
+	EndTick     int64     `gorm:"not null;index:idx_blob_match_ticks" json:"end_tick"`
+	TickCount   int       `gorm:"not null" json:"tick_count"`
+	Data        []byte    `gorm:"type:mediumblob;not null" json:"-"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (PlayerTickBlob) TableName() string {
+	return "player_tick_blobs"
+}
+
 // PlayerShootingData represents raw shooting data for aim analysis
 type PlayerShootingData struct {
 	ID             uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -1016,6 +1511,38 @@ func (PlayerShootingData) TableName() string {
 	return "player_shooting_data"
 }
 
+// HeatmapPoint is a position of a heatmap layer stored per match, so the event layers
+// can be rendered after the demo is gone
+type HeatmapPoint struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	MatchID     string    `gorm:"type:varchar(36);not null;index:idx_heatmap_match_layer" json:"match_id"`
+	Layer       string    `gorm:"type:varchar(16);not null;index:idx_heatmap_match_layer" json:"layer"`
+	RoundNumber int       `gorm:"not null" json:"round_number"`
+	PlayerID    string    `gorm:"type:varchar(20);not null" json:"player_id"`
+	PositionX   float64   `gorm:"type:double;not null" json:"position_x"`
+	PositionY   float64   `gorm:"type:double;not null" json:"position_y"`
+	PositionZ   float64   `gorm:"type:double;not null" json:"position_z"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (HeatmapPoint) TableName() string {
+	return "heatmap_points"
+}
+
+// SchemaMigration records a versioned SQL migration applied to the database
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	Checksum  string    `gorm:"type:char(64);not null" json:"checksum"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
+
+// TableName specifies the table name for GORM
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
 // AimAnalysisResult contains aggregated aim statistics for a player
 type AimAnalysisResult struct {
 	PlayerSteamID string
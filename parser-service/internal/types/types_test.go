@@ -64,6 +64,22 @@ func TestCalculateDistance(t *testing.T) {
 	}
 }
 
+func TestGetDistanceBucket(t *testing.T) {
+	tests := map[float64]string{
+		0:    DistanceBucketClose,
+		500:  DistanceBucketClose,
+		501:  DistanceBucketMedium,
+		1500: DistanceBucketMedium,
+		3000: DistanceBucketLong,
+	}
+
+	for distance, expected := range tests {
+		if bucket := GetDistanceBucket(distance); bucket != expected {
+			t.Errorf("GetDistanceBucket(%v) = %q, want %q", distance, bucket, expected)
+		}
+	}
+}
+
 func TestNormalizeVector(t *testing.T) {
 	tests := []struct {
 		name     string
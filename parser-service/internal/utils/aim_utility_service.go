@@ -33,14 +33,15 @@ type AimUtilityService struct {
 	pool        *ObjectPool
 	logger      *logrus.Logger
 	config      *config.Config
+	timing      *types.TickTiming
 }
 
 const (
-	EngagementGapSeconds     = 5
-	ReactionTimeSearchWindow = 128
-	MaxParallelLOSWorkers    = 9
-	LOSCheckInterval         = 2
-	MovementThreshold        = 5.0
+	EngagementGapSeconds            = 5
+	ReactionTimeSearchWindowSeconds = 2.0
+	MaxParallelLOSWorkers           = 9
+	LOSCheckInterval                = 2
+	MovementThreshold               = 5.0
 )
 
 // Aim Rating Constants
@@ -105,6 +106,11 @@ func NewAimUtilityService(mapName string, cfg *config.Config) (*AimUtilityServic
 	}, nil
 }
 
+// SetTickTiming sets the timing context of the demo the damage and shots came from
+func (aus *AimUtilityService) SetTickTiming(timing *types.TickTiming) {
+	aus.timing = timing
+}
+
 // shouldProcessPlayer checks if we should process this player based on config
 func (aus *AimUtilityService) shouldProcessPlayer(playerID string) bool {
 	// If config is not available, process all players
@@ -232,7 +238,7 @@ func (aus *AimUtilityService) calculateReactionTimesFromDamageEvents(
 
 func (aus *AimUtilityService) identifyFirstShots(damageEvents []types.DamageEvent) []types.DamageEvent {
 	var firstShots []types.DamageEvent
-	gapTicks := int64(EngagementGapSeconds * 64)
+	gapTicks := aus.timing.SecondsToTicks(EngagementGapSeconds)
 
 	for i, damage := range damageEvents {
 		if !aus.isGunDamage(damage) {
@@ -289,7 +295,7 @@ func (aus *AimUtilityService) calculateReactionTimeForFirstShot(
 	damage types.DamageEvent,
 	playerTickData []types.PlayerTickData,
 ) float64 {
-	searchStartTick := damage.TickTimestamp - ReactionTimeSearchWindow
+	searchStartTick := damage.TickTimestamp - aus.timing.SecondsToTicks(ReactionTimeSearchWindowSeconds)
 	if searchStartTick < 0 {
 		searchStartTick = 0
 	}
@@ -331,7 +337,7 @@ func (aus *AimUtilityService) calculateReactionTimeForFirstShot(
 	}
 
 	tickDiff := float64(damage.TickTimestamp - visibilityTick)
-	reactionTimeMs := (tickDiff / 64.0) * 1000.0
+	reactionTimeMs := aus.timing.TicksToMilliseconds(tickDiff)
 
 	return reactionTimeMs
 }
@@ -340,7 +346,7 @@ func (aus *AimUtilityService) calculateReactionTimeForFirstShotOptimized(
 	damage types.DamageEvent,
 	tickDataByPlayer map[string][]types.PlayerTickData,
 ) float64 {
-	searchStartTick := damage.TickTimestamp - ReactionTimeSearchWindow
+	searchStartTick := damage.TickTimestamp - aus.timing.SecondsToTicks(ReactionTimeSearchWindowSeconds)
 	if searchStartTick < 0 {
 		searchStartTick = 0
 	}
@@ -369,7 +375,7 @@ func (aus *AimUtilityService) calculateReactionTimeForFirstShotOptimized(
 	}
 
 	tickDiff := float64(damage.TickTimestamp - visibilityTick)
-	reactionTimeMs := (tickDiff / 64.0) * 1000.0
+	reactionTimeMs := aus.timing.TicksToMilliseconds(tickDiff)
 	return reactionTimeMs
 }
 
@@ -637,7 +643,7 @@ func (aus *AimUtilityService) calculateReactionTime(
 	}
 
 	tickDiff := float64(abs(shot.Tick - closestDamage.TickTimestamp))
-	reactionTimeMs := (tickDiff / 64.0) * 1000.0
+	reactionTimeMs := aus.timing.TicksToMilliseconds(tickDiff)
 
 	return reactionTimeMs
 }
@@ -88,6 +88,46 @@ func ScoreExplosive(GrenadeEvent types.GrenadeEvent) int {
 	return int(math.Round(score))
 }
 
+// Score molotov or incendiary (-100 -> +100)
+func ScoreMolotov(GrenadeEvent types.GrenadeEvent) int {
+	const (
+		maxDamage    = 100.0
+		maxTeamHurt  = 50.0
+		maxDisplaced = 3.0
+		maxKills     = 2.0
+		fullBurn     = 7.0     // Seconds a molotov burns when left alone
+		fullArea     = 50000.0 // Square units a fully spread fire covers
+	)
+
+	score := 0.0
+
+	// Enemy damage (clamped to 0-1, then scaled by 25)
+	score += clamp(float64(GrenadeEvent.DamageDealt)/maxDamage, 0, 1) * 25
+
+	// Team damage (clamped to 0-1, then scaled by -25)
+	score -= clamp(float64(GrenadeEvent.TeamDamageDealt)/maxTeamHurt, 0, 1) * 25
+
+	// Enemies pushed out of or hurt by the fire (clamped to 0-1, then scaled by 20)
+	score += clamp(float64(GrenadeEvent.EnemiesDisplaced)/maxDisplaced, 0, 1) * 20
+
+	// Enemies killed in the fire area while it burned (clamped to 0-1, then scaled by 25)
+	score += clamp(float64(GrenadeEvent.KillsDuringBurn)/maxKills, 0, 1) * 25
+
+	// Area denial, how long and how much of the area burned (clamped to 0-1, then scaled by 20)
+	burn := clamp(GrenadeEvent.BurnDurationSeconds/fullBurn, 0, 1)
+	area := clamp(GrenadeEvent.FireAreaCovered/fullArea, 0, 1)
+	score += burn * area * 20
+
+	// Enemies spent a smoke to put the fire out (10 points)
+	if GrenadeEvent.ExtinguishedBySmoke {
+		score += 10
+	}
+
+	// Cap at -100 to +100
+	score = clamp(score, -100, 100)
+	return int(math.Round(score))
+}
+
 // Score smoke (-100 -> +100)
 func ScoreSmoke(TimeBlocked float64, KillsThroughSmoke float64, FriendlyHurt float64) int {
 	const (
@@ -107,12 +147,11 @@ func ScoreSmoke(TimeBlocked float64, KillsThroughSmoke float64, FriendlyHurt flo
 }
 
 // ScoreSmokeWithBlockingDuration calculates smoke effectiveness based on blocking duration
-// 1 point for every 64 ticks blocked (as per requirements)
-func ScoreSmokeWithBlockingDuration(blockingDuration int) int {
-	// Convert ticks to effectiveness points (1 point per 64 ticks)
-	effectiveness := float64(blockingDuration) / 64.0
+// 1 point for every second blocked (as per requirements)
+func ScoreSmokeWithBlockingDuration(blockingSeconds float64) int {
+	effectiveness := blockingSeconds
 
-	// Cap at reasonable maximum (18 seconds = 1152 ticks = 18 points)
+	// Cap at reasonable maximum (18 seconds = full smoke lifetime = 18 points)
 	maxEffectiveness := 18.0
 	effectiveness = clamp(effectiveness, 0, maxEffectiveness)
 
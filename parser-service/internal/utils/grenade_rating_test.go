@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"parser-service/internal/types"
+	"testing"
+)
+
+func TestScoreMolotov(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    types.GrenadeEvent
+		expected int
+	}{
+		{
+			name:     "fire that did nothing",
+			event:    types.GrenadeEvent{GrenadeType: "Molotov"},
+			expected: 0,
+		},
+		{
+			name: "full burn over a full area",
+			event: types.GrenadeEvent{
+				GrenadeType:         "Molotov",
+				BurnDurationSeconds: 7,
+				FireAreaCovered:     50000,
+			},
+			expected: 20,
+		},
+		{
+			name: "damage, displaced enemies and a kill",
+			event: types.GrenadeEvent{
+				GrenadeType:      "Incendiary Grenade",
+				DamageDealt:      50,
+				EnemiesDisplaced: 3,
+				KillsDuringBurn:  1,
+			},
+			expected: 45, // 12.5 + 20 + 12.5
+		},
+		{
+			name: "put out by a smoke after half a burn",
+			event: types.GrenadeEvent{
+				GrenadeType:         "Molotov",
+				BurnDurationSeconds: 3.5,
+				FireAreaCovered:     50000,
+				ExtinguishedBySmoke: true,
+			},
+			expected: 20,
+		},
+		{
+			name: "burned teammates",
+			event: types.GrenadeEvent{
+				GrenadeType:     "Molotov",
+				TeamDamageDealt: 100,
+			},
+			expected: -25,
+		},
+		{
+			name: "everything at once is capped",
+			event: types.GrenadeEvent{
+				GrenadeType:         "Molotov",
+				DamageDealt:         300,
+				EnemiesDisplaced:    5,
+				KillsDuringBurn:     4,
+				BurnDurationSeconds: 7,
+				FireAreaCovered:     80000,
+				ExtinguishedBySmoke: true,
+			},
+			expected: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if score := ScoreMolotov(tt.event); score != tt.expected {
+				t.Errorf("Expected score %d, got %d", tt.expected, score)
+			}
+		})
+	}
+}
@@ -137,6 +137,7 @@ func (pl *PerformanceLogger) writeInitialLog() error {
 	pl.writeLine(fmt.Sprintf("  Logging Performance Detail: %s", pl.config.Logging.PerformanceDetail))
 	pl.writeLine("")
 	pl.writeLine(fmt.Sprintf("  Database Cleanup On Finish: %t", pl.config.Database.CleanupOnFinish))
+	pl.writeLine(fmt.Sprintf("  Database Tick Storage: %s", pl.config.Database.TickStorage))
 	pl.writeLine(fmt.Sprintf("  Limit Aim Processing: %t", pl.config.AimProcessing.LimitAimProcessing))
 	pl.writeLine(fmt.Sprintf("  Limited Aim Processing To Player IDs: %s", strings.Join(pl.config.AimProcessing.PlayerIds, ", ")))
 	pl.writeLine("")
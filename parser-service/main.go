@@ -52,8 +52,10 @@ func main() {
 
 	parseDemoHandler := handlers.NewParseDemoHandler(cfg, logger, demoParser, batchSender, progressManager, perfLogger)
 	healthHandler := handlers.NewHealthHandler(logger)
+	shootingDataHandler := handlers.NewShootingDataHandler(logger, demoParser.PlayerTickService())
+	heatmapHandler := handlers.NewHeatmapHandler(cfg, logger, demoParser.PlayerTickService())
 
-	router := setupRouter(parseDemoHandler, healthHandler, cfg)
+	router := setupRouter(parseDemoHandler, healthHandler, shootingDataHandler, heatmapHandler, cfg)
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -123,7 +125,7 @@ func setupLogger(cfg *config.Config) *logrus.Logger {
 	return logger
 }
 
-func setupRouter(parseDemoHandler *handlers.ParseDemoHandler, healthHandler *handlers.HealthHandler, cfg *config.Config) *gin.Engine {
+func setupRouter(parseDemoHandler *handlers.ParseDemoHandler, healthHandler *handlers.HealthHandler, shootingDataHandler *handlers.ShootingDataHandler, heatmapHandler *handlers.HeatmapHandler, cfg *config.Config) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 
 	router := gin.New()
@@ -138,6 +140,11 @@ func setupRouter(parseDemoHandler *handlers.ParseDemoHandler, healthHandler *han
 	apiGroup := router.Group("/api")
 	apiGroup.Use(middleware.APIKeyAuth(cfg.Server.APIKey))
 	apiGroup.POST(api.ParseDemoEndpoint, parseDemoHandler.HandleParseDemo)
+	apiGroup.POST(api.ParseMatchEndpoint, parseDemoHandler.HandleParseMatch)
+	apiGroup.POST(api.ParseSeriesEndpoint, parseDemoHandler.HandleParseSeries)
+	apiGroup.GET(api.MatchShotsEndpoint, shootingDataHandler.HandleGetShots)
+	apiGroup.GET(api.MatchHeatmapEndpoint, heatmapHandler.HandleGetMatchHeatmap)
+	apiGroup.POST(api.HeatmapEndpoint, heatmapHandler.HandlePostHeatmap)
 
 	return router
 }
@@ -0,0 +1,13 @@
+-- Rollback: Drop the (match_id, tick) index on player_tick_data
+SET @index_exists := (
+    SELECT COUNT(1) FROM information_schema.statistics
+    WHERE table_schema = DATABASE()
+    AND table_name = 'player_tick_data'
+    AND index_name = 'idx_player_tick_match_tick'
+);
+SET @drop_index := IF(@index_exists > 0,
+    'DROP INDEX idx_player_tick_match_tick ON player_tick_data',
+    'SELECT 1');
+PREPARE drop_index_stmt FROM @drop_index;
+EXECUTE drop_index_stmt;
+DEALLOCATE PREPARE drop_index_stmt;
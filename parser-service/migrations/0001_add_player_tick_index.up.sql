@@ -0,0 +1,21 @@
+-- Migration: Add index for efficient round-based tick data queries
+-- This index significantly improves performance when loading round tick data
+-- Expected improvement: ~150ms -> ~50ms per round query
+
+-- Add composite index on (match_id, tick) for efficient range queries
+-- This supports the query pattern: WHERE match_id = ? AND tick BETWEEN ? AND ?
+-- MySQL has no CREATE INDEX IF NOT EXISTS, so check information_schema first.
+-- This keeps the migration safe on databases where the index was created by
+-- the old unversioned migration runner.
+SET @index_exists := (
+    SELECT COUNT(1) FROM information_schema.statistics
+    WHERE table_schema = DATABASE()
+    AND table_name = 'player_tick_data'
+    AND index_name = 'idx_player_tick_match_tick'
+);
+SET @create_index := IF(@index_exists = 0,
+    'CREATE INDEX idx_player_tick_match_tick ON player_tick_data (match_id, tick)',
+    'SELECT 1');
+PREPARE create_index_stmt FROM @create_index;
+EXECUTE create_index_stmt;
+DEALLOCATE PREPARE create_index_stmt;